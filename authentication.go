@@ -2,12 +2,20 @@ package sheets
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -18,6 +26,14 @@ const (
 	ScopeReadOnly = "https://www.googleapis.com/auth/spreadsheets.readonly"
 	// ScopeReadWrite is the full-access oauth2 scope.
 	ScopeReadWrite = "https://www.googleapis.com/auth/spreadsheets"
+	// ScopeDrive is the full-access oauth2 scope of Google Drive,
+	// required to create, copy, share and delete any spreadsheet.
+	ScopeDrive = "https://www.googleapis.com/auth/drive"
+	// ScopeDriveFile is the oauth2 scope of Google Drive which gives access
+	// only to the files created or opened by the application.
+	ScopeDriveFile = "https://www.googleapis.com/auth/drive.file"
+	// ScopeDriveReadOnly is the readonly oauth2 scope of Google Drive.
+	ScopeDriveReadOnly = "https://www.googleapis.com/auth/drive.readonly"
 )
 
 // ServiceAccount is an oauth2 authentication function which
@@ -27,102 +43,506 @@ const (
 // but it does not need to keep and maintain a token.
 //
 // It panics on errors.
+//
+// Deprecated: Use `ServiceAccountE` instead, which returns the error.
 func ServiceAccount(ctx context.Context, serviceAccountFile string, scopes ...string) http.RoundTripper {
+	t, err := ServiceAccountE(ctx, serviceAccountFile, scopes...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return t
+}
+
+// ServiceAccountE is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+//
+// It requires Sheet -> Share button to the email of the service account
+// but it does not need to keep and maintain a token.
+//
+// It returns a non-nil error if the service account file cannot be read or parsed.
+func ServiceAccountE(ctx context.Context, serviceAccountFile string, scopes ...string) (http.RoundTripper, error) {
+	b, err := os.ReadFile(serviceAccountFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read service account secret file: %w", err)
+	}
+
+	return ServiceAccountJSON(ctx, b, scopes...)
+}
+
+// ServiceAccountJSON is like `ServiceAccountE` but it accepts the contents of the service account file,
+// so the credentials can come from a secret manager instead of a file on disk.
+// See `ServiceAccountEnv` too.
+func ServiceAccountJSON(ctx context.Context, serviceAccountJSON []byte, scopes ...string) (http.RoundTripper, error) {
+	return serviceAccount(ctx, serviceAccountJSON, "", scopes...)
+}
+
+// ServiceAccountAs is like `ServiceAccountE` but the service account acts on behalf of the "subject" user
+// (e.g. "user@corp.com") through Google Workspace domain-wide delegation.
+// The service account's client ID must be authorized for the "scopes" in the Workspace admin console.
+func ServiceAccountAs(ctx context.Context, serviceAccountFile, subject string, scopes ...string) (http.RoundTripper, error) {
 	b, err := os.ReadFile(serviceAccountFile)
 	if err != nil {
-		log.Fatalf("Unable to read service account secret file: %v", err)
+		return nil, fmt.Errorf("unable to read service account secret file: %w", err)
+	}
+
+	return ServiceAccountJSONAs(ctx, b, subject, scopes...)
+}
+
+// ServiceAccountJSONAs is like `ServiceAccountAs` but it accepts the contents of the service account file.
+func ServiceAccountJSONAs(ctx context.Context, serviceAccountJSON []byte, subject string, scopes ...string) (http.RoundTripper, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("empty subject")
 	}
 
+	return serviceAccount(ctx, serviceAccountJSON, subject, scopes...)
+}
+
+func serviceAccount(ctx context.Context, serviceAccountJSON []byte, subject string, scopes ...string) (http.RoundTripper, error) {
 	if len(scopes) == 0 {
 		scopes = []string{ScopeReadOnly}
 	}
 
-	config, err := google.JWTConfigFromJSON(b, scopes...)
+	config, err := google.JWTConfigFromJSON(serviceAccountJSON, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service account secret file to config: %w", err)
+	}
+	config.Subject = subject
+	return newCredentialsTransport(ctx, func(ctx context.Context) oauth2.TokenSource {
+		return config.TokenSource(ctx)
+	}, universeDomainFromJSON(serviceAccountJSON)), nil
+}
+
+// ServiceAccountEnv is like `ServiceAccountJSON` but it reads the contents of the service account file
+// from the "envKey" environment variable. The value can be the raw JSON or its base64 encoding.
+func ServiceAccountEnv(ctx context.Context, envKey string, scopes ...string) (http.RoundTripper, error) {
+	value := strings.TrimSpace(os.Getenv(envKey))
+	if value == "" {
+		return nil, fmt.Errorf("environment variable %q is empty", envKey)
+	}
+
+	b := []byte(value)
+	if !strings.HasPrefix(value, "{") {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode environment variable %q: %w", envKey, err)
+		}
+
+		b = decoded
+	}
+
+	return ServiceAccountJSON(ctx, b, scopes...)
+}
+
+// FromTokenSource is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+// It accepts a custom token source, for applications that already manage their tokens.
+// See `FromCredentials` and `Client.SetTokenSource` too.
+func FromTokenSource(ts oauth2.TokenSource) http.RoundTripper {
+	return newCredentialsTransport(context.Background(), func(context.Context) oauth2.TokenSource {
+		return oauth2.ReuseTokenSource(nil, ts)
+	}, "")
+}
+
+// MetadataCredentials is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+// It uses the service account attached to the Compute Engine instance
+// (or the GKE workload identity) through the metadata server, no credentials file is required.
+// The scopes, if any, must be granted to the instance, most of the times only on Compute Engine.
+func MetadataCredentials(ctx context.Context, scopes ...string) http.RoundTripper {
+	return newCredentialsTransport(ctx, func(ctx context.Context) oauth2.TokenSource {
+		return google.ComputeTokenSource("", scopes...)
+	}, "")
+}
+
+// FromCredentials is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+// It accepts credentials loaded by the google package, e.g. through `google.CredentialsFromJSON`
+// or `google.FindDefaultCredentials`. The Client respects their universe domain.
+func FromCredentials(creds *google.Credentials) (http.RoundTripper, error) {
+	universeDomain, err := creds.GetUniverseDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	return newCredentialsTransport(context.Background(), func(context.Context) oauth2.TokenSource {
+		return oauth2.ReuseTokenSource(nil, creds.TokenSource)
+	}, universeDomain), nil
+}
+
+// AuthorizedUser is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+// It accepts an authorized-user credentials file, as created by
+// "gcloud auth application-default login", so developers can run tools locally with their own identity.
+// If "credentialsFile" is empty, the gcloud well-known location is used,
+// see `DefaultAuthorizedUserFile` package-level function.
+//
+// Note that the scopes are granted at login time, e.g.
+// "gcloud auth application-default login --scopes=openid,https://www.googleapis.com/auth/cloud-platform,https://www.googleapis.com/auth/spreadsheets".
+func AuthorizedUser(ctx context.Context, credentialsFile string, scopes ...string) (http.RoundTripper, error) {
+	if credentialsFile == "" {
+		credentialsFile = DefaultAuthorizedUserFile()
+	}
+
+	b, err := os.ReadFile(credentialsFile)
 	if err != nil {
-		log.Fatalf("Unable to parse service account secret file to config: %v", err)
+		return nil, fmt.Errorf("unable to read authorized user file: %w", err)
+	}
+
+	return AuthorizedUserJSON(ctx, b, scopes...)
+}
+
+// AuthorizedUserJSON is like `AuthorizedUser` but it accepts the contents of the credentials file.
+func AuthorizedUserJSON(ctx context.Context, credentialsJSON []byte, scopes ...string) (http.RoundTripper, error) {
+	var f struct {
+		Type         string `json:"type"`
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		RefreshToken string `json:"refresh_token"`
+		TokenURI     string `json:"token_uri"`
+	}
+
+	if err := json.Unmarshal(credentialsJSON, &f); err != nil {
+		return nil, fmt.Errorf("unable to parse authorized user file: %w", err)
+	}
+
+	if f.Type != "authorized_user" {
+		return nil, fmt.Errorf("unexpected credentials type %q, expected %q", f.Type, "authorized_user")
+	}
+
+	if f.RefreshToken == "" {
+		return nil, fmt.Errorf("authorized user file: missing refresh token")
 	}
-	client := config.Client(ctx)
-	return client.Transport
+
+	endpoint := google.Endpoint
+	if f.TokenURI != "" {
+		endpoint.TokenURL = f.TokenURI
+	}
+
+	config := &oauth2.Config{
+		ClientID:     f.ClientID,
+		ClientSecret: f.ClientSecret,
+		Endpoint:     endpoint,
+		Scopes:       scopes,
+	}
+
+	return newCredentialsTransport(ctx, func(ctx context.Context) oauth2.TokenSource {
+		return config.TokenSource(ctx, &oauth2.Token{RefreshToken: f.RefreshToken})
+	}, universeDomainFromJSON(credentialsJSON)), nil
+}
+
+// DefaultAuthorizedUserFile returns the well-known location of the gcloud application default credentials file.
+// It respects the CLOUDSDK_CONFIG environment variable.
+func DefaultAuthorizedUserFile() string {
+	const filename = "application_default_credentials.json"
+
+	if dir := os.Getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return filepath.Join(dir, filename)
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud", filename)
+	}
+
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gcloud", filename)
+}
+
+// credentialsTransport is an authenticated `http.RoundTripper`
+// which also reports the universe domain of its credentials
+// and can invalidate its cached token.
+type credentialsTransport struct {
+	http.RoundTripper
+	universeDomain string // empty if unknown.
+	source         *resettableTokenSource
+	base           http.RoundTripper // nil means the default transport.
+}
+
+// newCredentialsTransport returns a new authenticated transport, the "newSource" is called
+// to create the token source initially and after the token was invalidated,
+// its context holds the HTTP client of the token requests.
+// The base transport can be configured through the "ctx", see `WithTransport`, or later through `Client.SetTransport`.
+func newCredentialsTransport(ctx context.Context, newSource func(ctx context.Context) oauth2.TokenSource, universeDomain string) *credentialsTransport {
+	var base http.RoundTripper
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && hc != nil {
+		base = hc.Transport
+	}
+
+	source := &resettableTokenSource{ctx: ctx, newSource: newSource}
+	return &credentialsTransport{
+		RoundTripper:   &oauth2.Transport{Source: source, Base: base},
+		universeDomain: universeDomain,
+		source:         source,
+		base:           base,
+	}
+}
+
+// setBase sets the base transport of the API and the token requests.
+func (t *credentialsTransport) setBase(base http.RoundTripper) {
+	t.base = base
+	t.RoundTripper = &oauth2.Transport{Source: t.source, Base: base}
+	t.source.setHTTPClient(&http.Client{Transport: base})
+}
+
+// UniverseDomain returns the universe domain of the credentials.
+func (t *credentialsTransport) UniverseDomain() string {
+	return t.universeDomain
+}
+
+// Token returns the active token, it is retrieved if necessary.
+func (t *credentialsTransport) Token() (*oauth2.Token, error) {
+	return t.source.Token()
+}
+
+// InvalidateToken drops the cached token, the next request retrieves a new one.
+func (t *credentialsTransport) InvalidateToken() {
+	t.source.Reset()
+}
+
+// resettableTokenSource is an oauth2 token source
+// which can drop its source, and so its cached token.
+type resettableTokenSource struct {
+	newSource func(ctx context.Context) oauth2.TokenSource
+
+	mu     sync.Mutex
+	ctx    context.Context
+	source oauth2.TokenSource
+}
+
+// Token implements the `oauth2.TokenSource` interface.
+func (s *resettableTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	if s.source == nil {
+		s.source = s.newSource(s.ctx)
+	}
+	source := s.source
+	s.mu.Unlock()
+
+	return source.Token()
+}
+
+// Reset drops the current token source.
+func (s *resettableTokenSource) Reset() {
+	s.mu.Lock()
+	s.source = nil
+	s.mu.Unlock()
+}
+
+// setHTTPClient sets the HTTP client of the token requests, the current token source is dropped.
+func (s *resettableTokenSource) setHTTPClient(hc *http.Client) {
+	s.mu.Lock()
+	s.ctx = context.WithValue(s.ctx, oauth2.HTTPClient, hc)
+	s.source = nil
+	s.mu.Unlock()
+}
+
+// universeDomainFromJSON returns the "universe_domain" field of a credentials file contents,
+// defaults to `DefaultUniverseDomain`.
+func universeDomainFromJSON(b []byte) string {
+	var f struct {
+		UniverseDomain string `json:"universe_domain"`
+	}
+
+	if err := json.Unmarshal(b, &f); err != nil || f.UniverseDomain == "" {
+		return DefaultUniverseDomain
+	}
+
+	return f.UniverseDomain
 }
 
 // Token is an oauth2 authentication function which
 // can be passed on the `New` package-level function.
 // It accepts a token file and optionally scopes (see `ScopeReadOnly` and `ScopeReadWrite` package-level variables).
-// At the future it may accept scopes from different APIs (e.g google drive to save the spreadsheets on a specified folder).
+// Use the `Scopes` package-level function to combine scopes of different APIs (e.g. Google Drive).
 //
 // It panics on errors.
+//
+// Deprecated: Use `TokenE` instead, which returns the error.
 func Token(ctx context.Context, credentialsFile, tokenFile string, scopes ...string) http.RoundTripper {
-	b, err := ioutil.ReadFile(credentialsFile)
+	t, err := TokenE(ctx, credentialsFile, tokenFile, scopes...)
 	if err != nil {
-		log.Fatalf("Unable to read client secret file: %v", err)
+		log.Fatal(err)
+	}
+
+	return t
+}
+
+// TokenE is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+// It accepts a token file and optionally scopes (see `ScopeReadOnly` and `ScopeReadWrite` package-level variables).
+// If the token file does not exist, it opens the browser to the consent page
+// and captures the authorization code through a temporary local server.
+// If the refresh token is revoked later on, the requests fail with `ErrTokenRevoked`,
+// unless the "ctx" enables the re-consent, see `WithReconsent`.
+//
+// It returns a non-nil error if the credentials file cannot be read or parsed,
+// or if a token cannot be retrieved or saved.
+func TokenE(ctx context.Context, credentialsFile, tokenFile string, scopes ...string) (http.RoundTripper, error) {
+	return TokenWithStore(ctx, credentialsFile, FileTokenStore(tokenFile), scopes...)
+}
+
+// TokenWithStore is like `TokenE` but it accepts a custom `TokenStore`
+// to load and persist the token instead of a local token file.
+func TokenWithStore(ctx context.Context, credentialsFile string, store TokenStore, scopes ...string) (http.RoundTripper, error) {
+	b, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client secret file: %w", err)
 	}
 
 	if len(scopes) == 0 {
 		scopes = []string{ScopeReadOnly}
 	}
 
-	// If modifying these scopes, delete your previously saved token.json.
+	// If modifying these scopes, delete your previously saved token.
 	config, err := google.ConfigFromJSON(b, scopes...)
 	if err != nil {
-		log.Fatalf("Unable to parse client secret file to config: %v", err)
+		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
 	}
 
-	client := getClient(ctx, tokenFile, config)
-	return client.Transport
+	return getTransport(ctx, store, config)
 }
 
-// Retrieve a token, saves the token, then returns the generated client.
-func getClient(ctx context.Context, tokenFile string, config *oauth2.Config) *http.Client {
-	// The file token.json stores the user's access and refresh tokens, and is
-	// created automatically when the authorization flow completes for the first
-	// time.
-	tok, err := tokenFromFile(tokenFile)
+// Retrieve a token, saves the token, then returns the generated transport.
+// The refreshed tokens are saved to the store too.
+func getTransport(ctx context.Context, store TokenStore, config *oauth2.Config) (http.RoundTripper, error) {
+	// The store keeps the user's access and refresh tokens, and is
+	// filled automatically when the authorization flow completes for the first
+	// time. The flow runs only when there is no stored token,
+	// any other error of the store (e.g. a corrupted file) is returned.
+	tok, err := store.Get(ctx)
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(tokenFile, tok)
+		if !errors.Is(err, ErrTokenNotFound) {
+			return nil, fmt.Errorf("unable to read the stored token: %w", err)
+		}
+
+		tok, err = getTokenFromWeb(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = store.Put(ctx, tok); err != nil {
+			return nil, err
+		}
+	}
+
+	ts := &userTokenSource{
+		config:       config,
+		store:        store,
+		reconsent:    reconsentEnabled(ctx),
+		ctx:          ctx,
+		refreshToken: tok.RefreshToken,
 	}
-	return config.Client(ctx, tok)
+
+	initial := tok
+	return newCredentialsTransport(ctx, func(ctx context.Context) oauth2.TokenSource {
+		ts.mu.Lock()
+		ts.ctx = ctx
+		ts.mu.Unlock()
+
+		// The stored token is used until it expires,
+		// after an invalidation a new one is retrieved.
+		source := oauth2.ReuseTokenSource(initial, ts)
+		initial = nil
+		return source
+	}, ""), nil
 }
 
 // Request a token from the web, then returns the retrieved token.
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the "+
-		"authorization code: \n%v\n", authURL)
+// It starts a temporary server on the loopback interface, opens the browser
+// on the consent page and captures the authorization code from the redirect.
+// The request is protected with PKCE and a random state value.
+func getTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to start local redirect server: %w", err)
+	}
+	defer ln.Close()
 
-	var authCode string
-	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("Unable to read authorization code: %v", err)
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	c := *config
+	c.RedirectURL = "http://" + ln.Addr().String() + "/"
+
+	type result struct {
+		code string
+		err  error
 	}
+	results := make(chan result, 1)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		var res result
+		if errText := query.Get("error"); errText != "" {
+			res.err = fmt.Errorf("authorization failed: %s", errText)
+			fmt.Fprintln(w, "Authorization failed, you can close this window.")
+		} else if res.code = query.Get("code"); res.code == "" {
+			res.err = fmt.Errorf("authorization failed: missing code")
+			fmt.Fprintln(w, "Authorization failed, you can close this window.")
+		} else {
+			fmt.Fprintln(w, "Authorization completed, you can close this window.")
+		}
+
+		select {
+		case results <- res:
+		default:
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	// Proof Key for Code Exchange (RFC 7636).
+	verifier := oauth2.GenerateVerifier()
 
-	tok, err := config.Exchange(context.TODO(), authCode)
+	authURL := c.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("Go to the following link in your browser to authorize the application: \n%v\n", authURL)
+	_ = openBrowser(authURL)
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	tok, err := c.Exchange(ctx, res.code, oauth2.VerifierOption(verifier))
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web: %v", err)
+		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
 	}
-	return tok
+	return tok, nil
 }
 
-// Retrieves a token from a local file.
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
+// openBrowser opens the "url" on the default browser.
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
 	}
-	defer f.Close()
 
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
+	return cmd.Start()
 }
 
-// Saves a token to a file path.
-func saveToken(path string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	defer f.Close()
 
-	_ = json.NewEncoder(f).Encode(token)
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
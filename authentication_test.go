@@ -0,0 +1,293 @@
+package sheets
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestAuthenticationErrors(t *testing.T) {
+	ctx := context.Background()
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	if _, err := ServiceAccountE(ctx, missing); err == nil {
+		t.Fatal("expected an error for a missing service account file")
+	}
+
+	if _, err := TokenE(ctx, missing, missing); err == nil {
+		t.Fatal("expected an error for a missing credentials file")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ServiceAccountE(ctx, invalid); err == nil {
+		t.Fatal("expected an error for an invalid service account file")
+	}
+}
+
+func TestServiceAccountEnv(t *testing.T) {
+	ctx := context.Background()
+	const envKey = "SHEETS_TEST_SERVICE_ACCOUNT"
+
+	t.Setenv(envKey, "")
+	if _, err := ServiceAccountEnv(ctx, envKey); err == nil {
+		t.Fatal("expected an error for an empty environment variable")
+	}
+
+	t.Setenv(envKey, "not base64!")
+	if _, err := ServiceAccountEnv(ctx, envKey); err == nil {
+		t.Fatal("expected an error for an invalid environment variable value")
+	}
+
+	t.Setenv(envKey, "{}")
+	if _, err := ServiceAccountEnv(ctx, envKey); err == nil {
+		t.Fatal("expected an error for invalid credentials")
+	}
+}
+
+func testServiceAccountJSON(t *testing.T, tokenURI, universeDomain string) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	b, err := json.Marshal(map[string]string{
+		"type":            "service_account",
+		"client_email":    "test@test.iam.gserviceaccount.com",
+		"private_key":     string(pemKey),
+		"token_uri":       tokenURI,
+		"universe_domain": universeDomain,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestServiceAccountJSONUniverseDomain(t *testing.T) {
+	b := testServiceAccountJSON(t, "https://oauth2.example-tpc.goog/token", "example-tpc.goog")
+
+	transport, err := ServiceAccountJSON(context.Background(), b, ScopeReadWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "example-tpc.goog", NewClient(transport).UniverseDomain; expected != got {
+		t.Fatalf("expected universe domain %q but got %q", expected, got)
+	}
+}
+
+func TestServiceAccountJSONAs(t *testing.T) {
+	var subject string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			r.ParseForm()
+			parts := strings.Split(r.Form.Get("assertion"), ".")
+			if len(parts) == 3 {
+				claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+				var c struct {
+					Sub string `json:"sub"`
+				}
+				json.Unmarshal(claims, &c)
+				subject = c.Sub
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"access","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	b := testServiceAccountJSON(t, srv.URL+"/token", "")
+
+	if _, err := ServiceAccountJSONAs(context.Background(), b, ""); err == nil {
+		t.Fatal("expected an error for an empty subject")
+	}
+
+	transport, err := ServiceAccountJSONAs(context.Background(), b, "user@corp.com", ScopeReadWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload ValueRange
+	if err = NewClient(transport).ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "user@corp.com", subject; expected != got {
+		t.Fatalf("expected subject %q but got %q", expected, got)
+	}
+}
+
+func TestAuthorizedUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "user-refresh", r.FormValue("refresh_token"); expected != got {
+			t.Errorf("expected refresh token %q but got %q", expected, got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"user-access","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	t.Setenv("CLOUDSDK_CONFIG", dir)
+	b := []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"user-refresh","token_uri":"` + srv.URL + `"}`)
+	if err := os.WriteFile(filepath.Join(dir, "application_default_credentials.json"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	transport, err := AuthorizedUser(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := transport.(*credentialsTransport).Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "user-access", tok.AccessToken; expected != got {
+		t.Fatalf("expected access token %q but got %q", expected, got)
+	}
+
+	if _, err = AuthorizedUserJSON(context.Background(), testServiceAccountJSON(t, srv.URL, "")); err == nil {
+		t.Fatal("expected an error for a service account file")
+	}
+}
+
+func TestFromTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ValueRange{Range: r.Header.Get("Authorization")})
+	}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	c.SetTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}))
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Bearer token", payload.Range; expected != got {
+		t.Fatalf("expected authorization %q but got %q", expected, got)
+	}
+}
+
+func TestGetTokenFromWeb(t *testing.T) {
+	var challenge string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if expected, got := "auth-code", r.Form.Get("code"); expected != got {
+			t.Errorf("expected code %q but got %q", expected, got)
+		}
+
+		verifier := r.Form.Get("code_verifier")
+		if verifier == "" || oauth2.S256ChallengeFromVerifier(verifier) != challenge {
+			t.Errorf("expected a code verifier which matches the %q challenge but got %q", challenge, verifier)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenSrv.Close()
+
+	defer func(original func(string) error) { openBrowser = original }(openBrowser)
+	openBrowser = func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+
+		query := u.Query()
+		if expected, got := "S256", query.Get("code_challenge_method"); expected != got {
+			t.Errorf("expected code challenge method %q but got %q", expected, got)
+		}
+		challenge = query.Get("code_challenge")
+
+		go func() {
+			resp, err := http.Get(query.Get("redirect_uri") + "?state=" + url.QueryEscape(query.Get("state")) + "&code=auth-code")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	config := &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{AuthURL: tokenSrv.URL + "/auth", TokenURL: tokenSrv.URL + "/token"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tok, err := getTokenFromWeb(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "access", tok.AccessToken; expected != got {
+		t.Fatalf("expected access token %q but got %q", expected, got)
+	}
+}
+
+type sequenceTokenSource struct{ tokens []string }
+
+func (s *sequenceTokenSource) Token() (*oauth2.Token, error) {
+	tok := &oauth2.Token{AccessToken: s.tokens[0], Expiry: time.Now().Add(time.Hour)}
+	if len(s.tokens) > 1 {
+		s.tokens = s.tokens[1:]
+	}
+	return tok, nil
+}
+
+func TestClientReauthenticateOnUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"range":"A1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(FromTokenSource(&sequenceTokenSource{tokens: []string{"revoked", "valid"}}))
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	c = NewClient(FromTokenSource(&sequenceTokenSource{tokens: []string{"revoked"}}))
+	err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload)
+	if _, ok := IsStatusError(http.StatusUnauthorized, err); !ok {
+		t.Fatalf("expected unauthorized error but got %v", err)
+	}
+}
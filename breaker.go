@@ -0,0 +1,103 @@
+package sheets
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned from Client's methods when its `CircuitBreaker` is open,
+// the request was not sent to the server.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker is open")
+
+// CircuitBreaker protects services which read spreadsheets synchronously
+// from a degraded Sheets API. It opens after a number of consecutive
+// server errors (5xx) or timeouts and fails fast with `ErrCircuitOpen` for a cool-down period.
+// After the cool-down period a single trial request is allowed,
+// if it succeeds the circuit is closed again, otherwise it re-opens.
+//
+// Set it to the `Client.Breaker` field.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures that opens the circuit.
+	Threshold int
+	// Cooldown is the period the circuit stays open.
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker which opens after "threshold"
+// consecutive failures and stays open for "cooldown" duration.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+	}
+}
+
+func (b *CircuitBreaker) timeNow() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+
+	return time.Now()
+}
+
+// Allow reports whether a request can be sent.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+
+	if b.trial || b.timeNow().Sub(b.openedAt) < b.Cooldown {
+		return false
+	}
+
+	// half-open, let a single request through.
+	b.trial = true
+	return true
+}
+
+// Open reports whether the circuit is open.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.openedAt.IsZero()
+}
+
+// Success records a successful request, it closes the circuit.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	b.failures = 0
+	b.openedAt = time.Time{}
+	b.trial = false
+	b.mu.Unlock()
+}
+
+// Failure records a failed request, it opens the circuit
+// when the consecutive failures reach the threshold or when the trial request failed.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	b.failures++
+	if b.trial || b.failures >= b.Threshold {
+		b.openedAt = b.timeNow()
+	}
+	b.trial = false
+	b.mu.Unlock()
+}
+
+// release allows a new trial request without recording the result of the current one,
+// e.g. when the request was canceled by the caller.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
@@ -0,0 +1,59 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	statusCode := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	c := NewClient(http.DefaultTransport)
+	c.Breaker = b
+
+	do := func() error {
+		var v map[string]interface{}
+		return c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &v)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := do(); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("[%d] expected a resource error but got %v", i, err)
+		}
+	}
+
+	if err := do(); err != ErrCircuitOpen {
+		t.Fatalf("expected ErrCircuitOpen but got %v", err)
+	}
+
+	// trial request fails, re-opens.
+	now = now.Add(time.Minute)
+	if err := do(); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("expected a resource error but got %v", err)
+	}
+	if err := do(); err != ErrCircuitOpen {
+		t.Fatalf("expected ErrCircuitOpen but got %v", err)
+	}
+
+	// trial request succeeds, closes.
+	statusCode = http.StatusOK
+	now = now.Add(time.Minute)
+	if err := do(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Open() {
+		t.Fatal("expected circuit to be closed")
+	}
+}
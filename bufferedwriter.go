@@ -0,0 +1,233 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBufferedWriterMaxRows is the default `BufferedWriter.MaxRows` value.
+	DefaultBufferedWriterMaxRows = 500
+	// DefaultBufferedWriterFlushInterval is the default `BufferedWriter.FlushInterval` value.
+	DefaultBufferedWriterFlushInterval = 5 * time.Second
+)
+
+// ErrWriterClosed is returned by the `BufferedWriter` methods after it's closed.
+var ErrWriterClosed = errors.New("sheets: writer is closed")
+
+// BufferedWriter accumulates appended rows and row updates of a spreadsheet in memory
+// and writes them in batches, when `MaxRows` are buffered, every `FlushInterval` and on `Close`,
+// cutting the request count of high-frequency writers such as event loggers.
+// On flush, the updates are written in a single values batchUpdate request
+// and then the appended rows in a single append request per table range.
+// It's safe for concurrent use.
+//
+// See `NewBufferedWriter` package-level function.
+type BufferedWriter struct {
+	// MaxRows is the number of buffered rows (appends and updates) which triggers a flush.
+	// Defaults to `DefaultBufferedWriterMaxRows`.
+	MaxRows int
+	// FlushInterval is the interval of the periodic flushes, a negative value disables them.
+	// Defaults to `DefaultBufferedWriterFlushInterval`. It should be set before the first write.
+	FlushInterval time.Duration
+	// OnError, if not nil, is called with the errors of the periodic flushes.
+	OnError func(err error)
+
+	ctx           context.Context
+	client        *Client
+	spreadsheetID string
+
+	mu      sync.Mutex
+	appends []ValueRange // per table range, in order of their first append.
+	updates []ValueRange
+	rows    int
+	started bool
+	closed  bool
+	stop    chan struct{}
+	done    chan struct{}
+
+	flushMu sync.Mutex // serializes the flushes so the rows are written in order.
+}
+
+// NewBufferedWriter returns a new `BufferedWriter` of the spreadsheet.
+// The "ctx" is used by all the flush requests.
+func NewBufferedWriter(ctx context.Context, client *Client, spreadsheetID string) *BufferedWriter {
+	return &BufferedWriter{
+		MaxRows:       DefaultBufferedWriterMaxRows,
+		FlushInterval: DefaultBufferedWriterFlushInterval,
+		ctx:           ctx,
+		client:        client,
+		spreadsheetID: spreadsheetID,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Append buffers a row to be appended after the table of the "dataRange", e.g. "Events!A1".
+// The "v" can be a row of values or a struct value, see `SheetService.AppendRow`.
+// If the buffer is full then the buffered rows are flushed and the flush error is returned.
+func (w *BufferedWriter) Append(dataRange string, v interface{}) error {
+	row, err := encodeRow(v)
+	if err != nil {
+		return err
+	}
+
+	return w.add(func() {
+		for i := range w.appends {
+			if w.appends[i].Range == dataRange {
+				w.appends[i].Values = append(w.appends[i].Values, row)
+				return
+			}
+		}
+
+		w.appends = append(w.appends, ValueRange{Range: dataRange, MajorDimension: Rows, Values: [][]interface{}{row}})
+	})
+}
+
+// Update buffers a row to be written to the "dataRange", e.g. "Status!A2".
+// The "v" can be a row of values or a struct value, see `Append` method.
+func (w *BufferedWriter) Update(dataRange string, v interface{}) error {
+	row, err := encodeRow(v)
+	if err != nil {
+		return err
+	}
+
+	return w.add(func() {
+		w.updates = append(w.updates, ValueRange{Range: dataRange, MajorDimension: Rows, Values: [][]interface{}{row}})
+	})
+}
+
+func (w *BufferedWriter) add(buffer func()) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+
+	buffer()
+	w.rows++
+	full := w.MaxRows <= 0 || w.rows >= w.MaxRows
+
+	if !w.started {
+		w.started = true
+		if w.FlushInterval >= 0 {
+			go w.flushPeriodically()
+		} else {
+			close(w.done)
+		}
+	}
+	w.mu.Unlock()
+
+	if full {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+func (w *BufferedWriter) flushPeriodically() {
+	defer close(w.done)
+
+	interval := w.FlushInterval
+	if interval == 0 {
+		interval = DefaultBufferedWriterFlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil && w.OnError != nil {
+				w.OnError(err)
+			}
+		}
+	}
+}
+
+// Flush writes the buffered rows. If a request fails then its rows, and the ones not written yet,
+// are kept in the buffer to be retried by the next flush, and the error is returned.
+func (w *BufferedWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	updates, appends := w.updates, w.appends
+	w.updates, w.appends, w.rows = nil, nil, 0
+	w.mu.Unlock()
+
+	if len(updates) > 0 {
+		if _, err := w.client.BatchUpdateValues(w.ctx, w.spreadsheetID, updates...); err != nil {
+			w.requeue(updates, appends)
+			return err
+		}
+	}
+
+	for i, valueRange := range appends {
+		if _, err := w.client.AppendSpreadsheet(w.ctx, w.spreadsheetID, valueRange); err != nil {
+			w.requeue(nil, appends[i:])
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requeue puts the unwritten "updates" and "appends" back to the front of the buffer.
+func (w *BufferedWriter) requeue(updates, appends []ValueRange) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, valueRange := range w.appends {
+		merged := false
+		for i := range appends {
+			if appends[i].Range == valueRange.Range {
+				appends[i].Values = append(appends[i].Values, valueRange.Values...)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			appends = append(appends, valueRange)
+		}
+	}
+
+	w.updates = append(updates, w.updates...)
+	w.appends = appends
+
+	w.rows = 0
+	for _, valueRange := range w.updates {
+		w.rows += len(valueRange.Values)
+	}
+	for _, valueRange := range w.appends {
+		w.rows += len(valueRange.Values)
+	}
+}
+
+// Close stops the periodic flushes and flushes the buffered rows.
+// Writing after Close fails with `ErrWriterClosed`.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.closed = true
+	started := w.started
+	w.mu.Unlock()
+
+	if started {
+		close(w.stop)
+		<-w.done
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,126 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+		appended [][]interface{}
+		fail     bool
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"bad request","status":"INVALID_ARGUMENT"}}`))
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/id/values")
+		requests = append(requests, path)
+
+		if path == ":batchUpdate" {
+			w.Write([]byte(`{"spreadsheetId":"id"}`))
+			return
+		}
+
+		var values ValueRange
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+			t.Error(err)
+		}
+		appended = append(appended, values.Values...)
+		w.Write([]byte(`{"spreadsheetId":"id"}`))
+	}))
+	defer srv.Close()
+
+	w := NewBufferedWriter(context.Background(), NewClient(rewriteTransport(srv.URL)), "id")
+	w.MaxRows = 3
+	w.FlushInterval = -1
+
+	for _, v := range []interface{}{testRow{Name: "makis", Age: 27}, []interface{}{"status", "ok"}} {
+		if err := w.Append("Events!A1", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(requests) != 0 {
+		t.Fatalf("expected the rows to be buffered but got requests %q", requests)
+	}
+
+	if err := w.Update("Status!A2", []interface{}{"running"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{":batchUpdate", "/Events!A1:append"}; !reflect.DeepEqual(expected, requests) {
+		t.Fatalf("expected requests %q but got %q", expected, requests)
+	}
+	if expected := [][]interface{}{{"makis", 27.0}, {"status", "ok"}}; !reflect.DeepEqual(expected, appended) {
+		t.Fatalf("expected appended rows %v but got %v", expected, appended)
+	}
+
+	// Failed flushes keep the rows.
+	requests, appended = nil, nil
+	fail = true
+	if err := w.Append("Events!A1", []interface{}{"retried"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected a flush error")
+	}
+	if err := w.Append("Events!A1", []interface{}{"next"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := [][]interface{}{{"retried"}, {"next"}}; !reflect.DeepEqual(expected, appended) {
+		t.Fatalf("expected appended rows %v but got %v", expected, appended)
+	}
+
+	if err := w.Append("Events!A1", []interface{}{"late"}); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected a closed writer error but got %v", err)
+	}
+}
+
+func TestBufferedWriterFlushInterval(t *testing.T) {
+	flushed := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"spreadsheetId":"id"}`))
+		select {
+		case flushed <- struct{}{}:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	w := NewBufferedWriter(context.Background(), NewClient(rewriteTransport(srv.URL)), "id")
+	w.FlushInterval = 10 * time.Millisecond
+	defer w.Close()
+
+	if err := w.Append("Events!A1", []interface{}{"tick"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-flushed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a periodic flush")
+	}
+}
@@ -0,0 +1,126 @@
+package sheets
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Cell is the address of a cell on a sheet, the indexes are zero-based like the `GridRange` ones,
+// e.g. Cell{Row: 1, Col: 2} is the "C2" cell.
+// It's the foundation of programmatic layout code, e.g. report builders which place blocks of data at computed positions.
+type Cell struct {
+	Row int
+	Col int
+}
+
+// ParseCell parses an A1 notation cell, e.g. "C2".
+func ParseCell(a1 string) (Cell, error) {
+	col, row, ok := parseA1Cell(a1)
+	if !ok || col == 0 || row == 0 {
+		return Cell{}, &InvalidRangeError{Range: a1, Component: "start cell", Value: a1, Reason: cellReason(a1)}
+	}
+
+	return Cell{Row: row - 1, Col: col - 1}, nil
+}
+
+// A1 returns the A1 notation of the cell, e.g. "C2".
+func (c Cell) A1() string {
+	return IndexToColumn(c.Col) + strconv.Itoa(c.Row+1)
+}
+
+// String implements the `fmt.Stringer` interface, it returns the A1 notation of the cell.
+func (c Cell) String() string {
+	return c.A1()
+}
+
+// Right returns the cell "n" columns to the right, a negative "n" moves to the left.
+func (c Cell) Right(n int) Cell {
+	c.Col += n
+	return c
+}
+
+// Down returns the cell "n" rows below, a negative "n" moves up.
+func (c Cell) Down(n int) Cell {
+	c.Row += n
+	return c
+}
+
+// RangeTo returns the range from this cell to the "other" one, both inclusive,
+// in any order, e.g. the "C2" cell's range to the "E5" cell is "C2:E5".
+func (c Cell) RangeTo(other Cell) GridRange {
+	top, bottom := c.Row, other.Row
+	if top > bottom {
+		top, bottom = bottom, top
+	}
+
+	left, right := c.Col, other.Col
+	if left > right {
+		left, right = right, left
+	}
+
+	return GridRange{
+		StartRowIndex:    int64(top),
+		EndRowIndex:      int64(bottom + 1),
+		StartColumnIndex: int64(left),
+		EndColumnIndex:   int64(right + 1),
+	}
+}
+
+// Block returns the range of "rows" rows and "columns" columns starting from this cell,
+// e.g. the block of the values to be written at this cell.
+func (c Cell) Block(rows, columns int) GridRange {
+	return c.RangeTo(Cell{Row: c.Row + rows - 1, Col: c.Col + columns - 1})
+}
+
+// RangeForStructs returns the exact A1 notation range of "rows", a slice of structs (or pointers to structs),
+// written at the "startCell", e.g. "B2" or "Sheet1!B2". The columns are computed from the struct headers
+// and the rows from the slice length, so writes never overshoot or truncate.
+// It returns an empty string if "startCell" is not a valid cell, "rows" is not a slice of structs or it's empty.
+//
+// See `RangeForStructsWithHeader` to include a header row too.
+func RangeForStructs(startCell string, rows interface{}) string {
+	return rangeForStructs(startCell, rows, false)
+}
+
+// RangeForStructsWithHeader is like `RangeForStructs` but the range includes a header row before the "rows".
+func RangeForStructsWithHeader(startCell string, rows interface{}) string {
+	return rangeForStructs(startCell, rows, true)
+}
+
+func rangeForStructs(startCell string, rows interface{}, withHeader bool) string {
+	sheetTitle, cell := "", startCell
+	if i := strings.LastIndexByte(startCell, '!'); i >= 0 {
+		sheetTitle, cell = unquoteSheetTitle(startCell[:i]), startCell[i+1:]
+	}
+
+	start, err := ParseCell(cell)
+	if err != nil {
+		return ""
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(rows))
+	if v.Kind() != reflect.Slice {
+		return ""
+	}
+
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return ""
+	}
+
+	n := v.Len()
+	if withHeader {
+		n++
+	}
+
+	columns := len(getMetadata(typ).headers)
+	if n == 0 || columns == 0 {
+		return ""
+	}
+
+	return start.Block(n, columns).A1(sheetTitle)
+}
@@ -0,0 +1,62 @@
+package sheets
+
+import "testing"
+
+func TestCell(t *testing.T) {
+	c, err := ParseCell("C2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Cell{Row: 1, Col: 2}); expected != c {
+		t.Fatalf("expected %#v but got %#v", expected, c)
+	}
+
+	if expected, got := "E5", c.Right(2).Down(3).A1(); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if expected, got := "B1", c.Right(-1).Down(-1).String(); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	if expected, got := "Report!C2:E5", c.RangeTo(c.Right(2).Down(3)).A1("Report"); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if expected, got := "A1:C2", c.RangeTo(Cell{}).A1(""); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if expected, got := "C2:D11", c.Block(10, 2).A1(""); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	for _, a1 := range []string{"", "C", "2", "C0", "Sheet1!C2"} {
+		if _, err = ParseCell(a1); err == nil {
+			t.Fatalf("expected %q to be invalid", a1)
+		}
+	}
+}
+
+func TestRangeForStructs(t *testing.T) {
+	rows := []testRow{{Name: "makis"}, {Name: "giwrgos"}, {Name: "efi"}}
+
+	if expected, got := "B2:C4", RangeForStructs("B2", rows); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if expected, got := "'My Sheet'!B2:C5", RangeForStructsWithHeader("'My Sheet'!B2", &rows); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if expected, got := "A1:B1", RangeForStructsWithHeader("A1", []*testRowFailingDecoder{}); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	for _, got := range []string{
+		RangeForStructs("A1", []testRow{}),
+		RangeForStructs("A0", rows),
+		RangeForStructs("A1", []string{"a"}),
+		RangeForStructs("A1", testRow{}),
+	} {
+		if got != "" {
+			t.Fatalf("expected an empty range but got %q", got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sheets
+
+import "strings"
+
+type (
+	// CellData is the data of a cell, written by `Client.UpdateCells`.
+	CellData struct {
+		// UserEnteredValue is the value of the cell as if the user typed it.
+		UserEnteredValue *ExtendedValue `json:"userEnteredValue,omitempty"`
+		// TextFormatRuns are the runs of rich text of a string value,
+		// each run applies its format from its start index until the start index of the next one.
+		// See `RichText` to build them.
+		TextFormatRuns []TextFormatRun `json:"textFormatRuns,omitempty"`
+		// Note is the note of the cell.
+		Note string `json:"note,omitempty"`
+		// ChipRuns are the smart chips of the cell's text, e.g. people and Drive file chips.
+		// Each run applies its chip from its start index to the next character only.
+		ChipRuns []ChipRun `json:"chipRuns,omitempty"`
+		// FormattedValue is the value of the cell as it's displayed, it's read only.
+		FormattedValue string `json:"formattedValue,omitempty"`
+	}
+
+	// ExtendedValue is the value of a cell, only one of its fields should be set.
+	ExtendedValue struct {
+		StringValue  *string  `json:"stringValue,omitempty"`
+		NumberValue  *float64 `json:"numberValue,omitempty"`
+		BoolValue    *bool    `json:"boolValue,omitempty"`
+		FormulaValue *string  `json:"formulaValue,omitempty"`
+	}
+
+	// TextFormatRun is a run of a text format, see `CellData.TextFormatRuns`.
+	TextFormatRun struct {
+		// StartIndex is the zero-based character index, in UTF-16 code units, where this run starts.
+		StartIndex int64 `json:"startIndex,omitempty"`
+		// Format is the format of this run, unset fields inherit the cell's format.
+		Format TextFormat `json:"format"`
+	}
+
+	// TextFormat is the format of a run of text in a cell.
+	TextFormat struct {
+		ForegroundColor *Color `json:"foregroundColor,omitempty"`
+		FontFamily      string `json:"fontFamily,omitempty"`
+		FontSize        int64  `json:"fontSize,omitempty"`
+		Bold            bool   `json:"bold,omitempty"`
+		Italic          bool   `json:"italic,omitempty"`
+		Strikethrough   bool   `json:"strikethrough,omitempty"`
+		Underline       bool   `json:"underline,omitempty"`
+		Link            *Link  `json:"link,omitempty"`
+	}
+
+	// Color is an RGBA color, each component is in the [0, 1] interval.
+	Color struct {
+		Red   float64 `json:"red,omitempty"`
+		Green float64 `json:"green,omitempty"`
+		Blue  float64 `json:"blue,omitempty"`
+		Alpha float64 `json:"alpha,omitempty"`
+	}
+
+	// Link is an external or local reference of a run of text.
+	Link struct {
+		URI string `json:"uri"`
+	}
+)
+
+// StringValue returns a cell data of the string value "s".
+func StringValue(s string) CellData {
+	return CellData{UserEnteredValue: &ExtendedValue{StringValue: &s}}
+}
+
+// RichText builds the text of a cell which mixes formats, e.g. a bold "Status:" prefix
+// followed by a red "FAILED" suffix:
+//
+//	cell := new(sheets.RichText).
+//		Bold("Status: ").
+//		Add("FAILED", sheets.TextFormat{ForegroundColor: &sheets.Color{Red: 1}}).
+//		CellData()
+//
+// See `Client.UpdateCells` method.
+type RichText struct {
+	text   strings.Builder
+	length int64 // in UTF-16 code units.
+	runs   []TextFormatRun
+}
+
+// Add appends the "text" with the "format".
+func (t *RichText) Add(text string, format TextFormat) *RichText {
+	if text == "" {
+		return t
+	}
+
+	t.runs = append(t.runs, TextFormatRun{StartIndex: t.length, Format: format})
+	t.text.WriteString(text)
+	for _, r := range text {
+		if r >= 0x10000 { // a surrogate pair.
+			t.length += 2
+		} else {
+			t.length++
+		}
+	}
+
+	return t
+}
+
+// Plain appends the "text" with the cell's format.
+func (t *RichText) Plain(text string) *RichText {
+	return t.Add(text, TextFormat{})
+}
+
+// Bold appends the "text" in bold.
+func (t *RichText) Bold(text string) *RichText {
+	return t.Add(text, TextFormat{Bold: true})
+}
+
+// Italic appends the "text" in italic.
+func (t *RichText) Italic(text string) *RichText {
+	return t.Add(text, TextFormat{Italic: true})
+}
+
+// String returns the whole text.
+func (t *RichText) String() string {
+	return t.text.String()
+}
+
+// CellData returns the string value and its text format runs.
+func (t *RichText) CellData() CellData {
+	cell := StringValue(t.text.String())
+	cell.TextFormatRuns = append([]TextFormatRun(nil), t.runs...)
+	return cell
+}
@@ -0,0 +1,62 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRichText(t *testing.T) {
+	cell := new(RichText).
+		Bold("Status: ").
+		Plain("").
+		Add("😀 FAILED", TextFormat{ForegroundColor: &Color{Red: 1}}).
+		Italic(" now").
+		CellData()
+
+	b, err := json.Marshal(cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"userEnteredValue":{"stringValue":"Status: 😀 FAILED now"},"textFormatRuns":[` +
+		`{"format":{"bold":true}},` +
+		`{"startIndex":8,"format":{"foregroundColor":{"red":1}}},` +
+		`{"startIndex":17,"format":{"italic":true}}]}`
+	if got := string(b); expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestClientUpdateCells(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/v4/spreadsheets/id:batchUpdate", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+
+		b, _ := io.ReadAll(r.Body)
+		body = strings.TrimSpace(string(b))
+		w.Write([]byte(`{"spreadsheetId":"id"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	_, err := c.UpdateCells(context.Background(), "id", 42, Cell{Row: 1, Col: 2}, [][]CellData{
+		{new(RichText).Bold("OK").CellData(), StringValue("x")},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"requests":[{"updateCells":{"start":{"sheetId":42,"rowIndex":1,"columnIndex":2},"rows":[{"values":[` +
+		`{"userEnteredValue":{"stringValue":"OK"},"textFormatRuns":[{"format":{"bold":true}}]},` +
+		`{"userEnteredValue":{"stringValue":"x"}}]}],"fields":"userEnteredValue,textFormatRuns"}}]}`
+	if expected != body {
+		t.Fatalf("expected body:\n%s\nbut got:\n%s", expected, body)
+	}
+}
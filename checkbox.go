@@ -0,0 +1,43 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+)
+
+// SetCheckboxColumn renders the cells of the "columnRange" of the spreadsheet as checkboxes,
+// e.g. "Tasks!D2:D", through a boolean data validation rule.
+// A range without a sheet title refers to the first sheet.
+//
+// A checked checkbox cell is decoded as true and an unchecked (or empty) one as false to a bool struct field,
+// a bool field is written as a checked or unchecked checkbox, so task-tracker sheets map cleanly to structs.
+func (c *Client) SetCheckboxColumn(ctx context.Context, spreadsheetID, columnRange string) (response BatchUpdateResponse, err error) {
+	sheetTitle, r, err := ParseA1(columnRange)
+	if err != nil {
+		err = c.opError(err, "spreadsheets.batchUpdate/setDataValidation", spreadsheetID, columnRange, 0)
+		return
+	}
+
+	r.SheetID, err = c.sheetID(ctx, spreadsheetID, sheetTitle)
+	if err != nil {
+		return
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#setdatavalidationrequest
+	url := c.apiURL(spreadsheetBatchUpdateURL, spreadsheetID)
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{
+		"requests": []interface{}{
+			map[string]interface{}{
+				"setDataValidation": map[string]interface{}{
+					"range": r,
+					"rule": map[string]interface{}{
+						"condition": map[string]string{"type": "BOOLEAN"},
+					},
+				},
+			},
+		},
+	}, &response)
+	err = c.opError(err, "spreadsheets.batchUpdate/setDataValidation", spreadsheetID, columnRange, 0)
+	return
+}
@@ -0,0 +1,37 @@
+package sheets
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientSetCheckboxColumn(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4/spreadsheets/id":
+			w.Write([]byte(`{"spreadsheetId":"id","sheets":[{"properties":{"sheetId":0,"title":"Sheet1"}},{"properties":{"sheetId":8,"title":"Tasks"}}]}`))
+		case "/v4/spreadsheets/id:batchUpdate":
+			b, _ := io.ReadAll(r.Body)
+			body = strings.TrimSpace(string(b))
+			w.Write([]byte(`{"spreadsheetId":"id"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	if _, err := c.SetCheckboxColumn(context.Background(), "id", "Tasks!D2:D"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"requests":[{"setDataValidation":{"range":{"endColumnIndex":4,"sheetId":8,"startColumnIndex":3,"startRowIndex":1},"rule":{"condition":{"type":"BOOLEAN"}}}}]}`
+	if expected != body {
+		t.Fatalf("expected body:\n%s\nbut got:\n%s", expected, body)
+	}
+}
@@ -0,0 +1,165 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type (
+	// ChipRun is a run of a smart chip in the text of a cell, see `CellData.ChipRuns`.
+	ChipRun struct {
+		// StartIndex is the zero-based character index, in UTF-16 code units, of the chip.
+		StartIndex int64 `json:"startIndex,omitempty"`
+		Chip       Chip  `json:"chip"`
+	}
+
+	// Chip is a smart chip, only one of its fields is set.
+	Chip struct {
+		// PersonProperties is set for a people chip.
+		PersonProperties *PersonProperties `json:"personProperties,omitempty"`
+		// RichLinkProperties is set for a rich link chip, e.g. a Drive file chip.
+		RichLinkProperties *RichLinkProperties `json:"richLinkProperties,omitempty"`
+	}
+
+	// PersonProperties holds the properties of a people chip.
+	PersonProperties struct {
+		Email string `json:"email"`
+		// DisplayFormat is "DEFAULT", "LAST_NAME_COMMA_FIRST_NAME" or "EMAIL".
+		DisplayFormat string `json:"displayFormat,omitempty"`
+	}
+
+	// RichLinkProperties holds the properties of a rich link chip.
+	RichLinkProperties struct {
+		URI string `json:"uri"`
+		// MimeType is the MIME type of the linked Drive file, if any, it's read only.
+		MimeType string `json:"mimeType,omitempty"`
+	}
+)
+
+// Emails returns the email addresses of the people chips of the cell.
+func (cell CellData) Emails() []string {
+	var emails []string
+	for _, run := range cell.ChipRuns {
+		if p := run.Chip.PersonProperties; p != nil {
+			emails = append(emails, p.Email)
+		}
+	}
+
+	return emails
+}
+
+// FileIDs returns the Drive file IDs of the rich link chips of the cell,
+// e.g. "ID" of "https://docs.google.com/spreadsheets/d/ID/edit".
+// Links which do not point to a Drive file are skipped.
+func (cell CellData) FileIDs() []string {
+	var ids []string
+	for _, run := range cell.ChipRuns {
+		if link := run.Chip.RichLinkProperties; link != nil {
+			if id := driveFileID(link.URI); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// ChipValue returns the structured value of the cell's smart chips: the emails of its people chips
+// and the Drive file IDs of its file chips, comma-separated, or its formatted value if it has no chips.
+func (cell CellData) ChipValue() string {
+	values := append(cell.Emails(), cell.FileIDs()...)
+	if len(values) == 0 {
+		return cell.FormattedValue
+	}
+
+	return strings.Join(values, ",")
+}
+
+// driveFileID returns the Drive file ID of a Drive or Docs editors URL, or empty if it's not one,
+// e.g. "https://drive.google.com/file/d/ID/view" or "https://drive.google.com/open?id=ID".
+func driveFileID(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Host != "drive.google.com" && u.Host != "docs.google.com") {
+		return ""
+	}
+
+	if id := u.Query().Get("id"); id != "" {
+		return id
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "d" {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
+
+// ReadCells returns the cell data of the "dataRange" of the spreadsheet,
+// i.e. the formatted values and their smart chips. See `CellData.ChipValue` method.
+func (c *Client) ReadCells(ctx context.Context, spreadsheetID, dataRange string) ([][]CellData, error) {
+	if err := ValidateRange(dataRange); err != nil {
+		return nil, c.opError(err, "spreadsheets.get", spreadsheetID, dataRange, 0)
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/get
+	url := c.apiURL(spreadsheetURL, spreadsheetID)
+
+	var response struct {
+		Sheets []struct {
+			Data []struct {
+				RowData []struct {
+					Values []CellData `json:"values"`
+				} `json:"rowData"`
+			} `json:"data"`
+		} `json:"sheets"`
+	}
+	err := c.ReadJSON(ctx, http.MethodGet, url, nil, &response, Query{
+		"ranges": []string{dataRange},
+		"fields": []string{"sheets(data(rowData(values(formattedValue,chipRuns))))"},
+	})
+	if err != nil {
+		return nil, c.opError(err, "spreadsheets.get", spreadsheetID, dataRange, 0)
+	}
+
+	var rows [][]CellData
+	for _, sheet := range response.Sheets {
+		for _, data := range sheet.Data {
+			for _, row := range data.RowData {
+				rows = append(rows, row.Values)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// ReadSpreadsheetChips binds the values of the "dataRange" of the spreadsheet to the "dest",
+// as `ReadSpreadsheet` does, but the cells with smart chips are decoded to their structured values,
+// i.e. the emails of people chips and the Drive file IDs of file chips instead of their display text,
+// so CRM-like sheets can be parsed reliably. See `CellData.ChipValue` method.
+func (c *Client) ReadSpreadsheetChips(ctx context.Context, dest interface{}, spreadsheetID, dataRange string) error {
+	rows, err := c.ReadCells(ctx, spreadsheetID, dataRange)
+	if err != nil {
+		return err
+	}
+
+	valueRange := ValueRange{Range: dataRange, MajorDimension: Rows, Values: make([][]interface{}, len(rows))}
+	for i, row := range rows {
+		values := make([]interface{}, len(row))
+		for j, cell := range row {
+			values[j] = cell.ChipValue()
+		}
+		valueRange.Values[i] = values
+	}
+
+	if c.Decoder != nil {
+		return c.Decoder.Decode(dest, valueRange)
+	}
+
+	return DecodeValueRange(dest, valueRange)
+}
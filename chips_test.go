@@ -0,0 +1,58 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDriveFileID(t *testing.T) {
+	tests := map[string]string{
+		"https://docs.google.com/spreadsheets/d/abc123/edit#gid=0": "abc123",
+		"https://drive.google.com/file/d/xyz/view":                 "xyz",
+		"https://drive.google.com/open?id=qwe":                     "qwe",
+		"https://example.com/d/abc":                                "",
+		"https://docs.google.com/":                                 "",
+	}
+
+	for uri, expected := range tests {
+		if got := driveFileID(uri); expected != got {
+			t.Fatalf("expected file ID of %q to be %q but got %q", uri, expected, got)
+		}
+	}
+}
+
+type testRowContact struct {
+	Name     string
+	Owner    string
+	Contract string
+}
+
+func TestClientReadSpreadsheetChips(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "Contacts!A2:C", r.URL.Query().Get("ranges"); expected != got {
+			t.Errorf("expected ranges %q but got %q", expected, got)
+		}
+
+		w.Write([]byte(`{"sheets":[{"data":[{"rowData":[
+{"values":[{"formattedValue":"ACME"},{"formattedValue":"Gerasimos Maropoulos","chipRuns":[{"chip":{"personProperties":{"email":"kataras@example.com"}}}]},
+ {"formattedValue":"Contract","chipRuns":[{"chip":{"richLinkProperties":{"uri":"https://docs.google.com/document/d/doc1/edit","mimeType":"application/vnd.google-apps.document"}}}]}]},
+{"values":[{"formattedValue":"Other"}]}
+]}]}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	var dest []testRowContact
+	if err := c.ReadSpreadsheetChips(context.Background(), &dest, "id", "Contacts!A2:C"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testRowContact{{"ACME", "kataras@example.com", "doc1"}, {Name: "Other"}}
+	if !reflect.DeepEqual(expected, dest) {
+		t.Fatalf("expected %#v but got %#v", expected, dest)
+	}
+}
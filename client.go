@@ -4,27 +4,171 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 // Client holds the google spreadsheet custom API Client.
 type Client struct {
 	HTTPClient *http.Client
+	// CompressThreshold enables gzip compression of request bodies
+	// whose length is equal or greater than this value (in bytes).
+	// Defaults to zero which means that request bodies are never compressed.
+	CompressThreshold int
+	// MaxURLLength is the maximum length of a request URL,
+	// `Range` splits a batchGet request with many ranges to multiple ones
+	// so that each one of them does not exceed this limit.
+	// Defaults to `DefaultMaxURLLength`.
+	MaxURLLength int
+	// ParallelBatchGet, if true, sends the split batchGet requests of a `Range` call concurrently.
+	ParallelBatchGet bool
+	// Breaker, if not nil, fails fast with `ErrCircuitOpen` while the Sheets API is degraded.
+	// See `NewCircuitBreaker` package-level function.
+	Breaker *CircuitBreaker
+	// UniverseDomain is the universe domain of the Sheets API endpoint,
+	// e.g. a Google Trusted Partner Cloud domain.
+	// Defaults to the credentials universe domain or `DefaultUniverseDomain`.
+	UniverseDomain string
+	// Options are applied to every request, before the per-request ones.
+	// See `WithAPIKey` package-level function.
+	Options []RequestOption
+	// JSON is the codec used to encode request and decode response bodies.
+	// Defaults to `DefaultJSONCodec`.
+	JSON JSONCodec
+	// CoalesceReads, if true, collapses concurrent `Range` (and so `ReadSpreadsheet`) calls
+	// of the same spreadsheet and data ranges into a single request to the server.
+	// The callers share the same result, so they should not modify the returned values.
+	CoalesceReads bool
+	// StrictJSON, if true, fails to decode a response which contains fields
+	// unknown to the response type, so that model drift from API changes can be detected in staging.
+	// It requires a `JSON` codec whose decoder implements the `DisallowUnknownFields()` method,
+	// as the standard one does.
+	StrictJSON bool
+	// MaxResponseSize is the maximum number of bytes of a (decompressed) response body,
+	// reading more fails with an `ErrResponseTooLarge` error.
+	// It protects memory-constrained services from accidentally reading huge sheets.
+	// Defaults to zero which means no limit.
+	MaxResponseSize int64
+	// MaxWriteRows is the maximum number of rows of a single `UpdateSpreadsheet` request,
+	// the values of a larger range are split into sequential requests of consecutive rows.
+	// Defaults to `DefaultMaxWriteRows`, a negative value disables the splitting by rows.
+	MaxWriteRows int
+	// MaxWriteBytes is the maximum (estimated) size of the values of a single `UpdateSpreadsheet` request,
+	// the values of a larger range are split as `MaxWriteRows` does.
+	// Defaults to `DefaultMaxWriteBytes`, a negative value disables the splitting by size.
+	MaxWriteBytes int
+	// Decoder is used by `ReadSpreadsheet` to bind the values to the destination.
+	// Set a Decoder with its own `DecodeCache` to isolate the cached struct types of this Client.
+	// Defaults to the `DecodeValueRange` package-level function's behavior.
+	Decoder *Decoder
+	// InfoCacheTTL is the time the spreadsheets metadata fetched by `SpreadsheetInfoCached` are cached for.
+	// Defaults to `DefaultInfoCacheTTL`, a negative value disables the caching.
+	InfoCacheTTL time.Duration
+
+	reads           flightGroup
+	baseTransport   http.RoundTripper // see `SetTransport`.
+	infoCache       infoCache
+	onQuotaExceeded func(wait time.Duration)
+	onError         func(op string, err *ResourceError)
+	// the universe domain of the authentication credentials, if known.
+	credentialsUniverseDomain string
+}
+
+// maxQuotaRetries is the maximum number of times a request is retried
+// after a quota error when `Client.OnQuotaExceeded` is registered.
+const maxQuotaRetries = 5
+
+// OnQuotaExceeded registers a function which is called when a request fails
+// because of a rate limit or quota error (see `ResourceError.QuotaExceeded`).
+// When registered, instead of failing, the Client waits for the duration the server asked
+// (or an exponential backoff if not specified) and retries the request,
+// the "fn" receives that duration so long-running callers can pause or report.
+// The request fails if the context is done or the quota is still exceeded after a few retries.
+func (c *Client) OnQuotaExceeded(fn func(wait time.Duration)) {
+	c.onQuotaExceeded = fn
 }
 
+// OnError registers a function which is called for every failed API call
+// with the operation, e.g. "values.get <spreadsheetID>!<range>", and the server's error,
+// so the error classes can be counted per spreadsheet without wrapping every call site.
+// It is not called for errors which did not come from the server, e.g. network errors.
+func (c *Client) OnError(fn func(op string, err *ResourceError)) {
+	c.onError = fn
+}
+
+// DefaultMaxURLLength is the default `Client.MaxURLLength` value.
+const DefaultMaxURLLength = 8192
+
 // NewClient creates and returns a new spreadsheet HTTP Client.
 // It accepts `http.RoundTriper` which is used for oauth2 authentication,
-// see `ServiceAccount` and `Token` package-level functions.
+// see `ServiceAccountE` and `TokenE` package-level functions.
+// A nil "authentication" sends unauthenticated requests through the default transport,
+// see `WithAPIKey` package-level function.
+//
+// The Client's universe domain is set to the credentials universe domain, if known.
 func NewClient(authentication http.RoundTripper) *Client {
-	return &Client{
+	c := &Client{
 		HTTPClient: &http.Client{
 			Transport: authentication,
 		},
 	}
+
+	if u, ok := authentication.(interface{ UniverseDomain() string }); ok {
+		c.credentialsUniverseDomain = u.UniverseDomain()
+		c.UniverseDomain = c.credentialsUniverseDomain
+	}
+
+	return c
+}
+
+// SetTokenSource sets the token source which authenticates the Client's requests,
+// it replaces the Client's HTTP transport. See `FromTokenSource` package-level function.
+func (c *Client) SetTokenSource(ts oauth2.TokenSource) {
+	hc := new(http.Client)
+	if c.HTTPClient != nil {
+		*hc = *c.HTTPClient
+	}
+
+	t := FromTokenSource(ts).(*credentialsTransport)
+	if c.baseTransport != nil {
+		t.setBase(c.baseTransport)
+	}
+
+	hc.Transport = t
+	c.HTTPClient = hc
+}
+
+// DefaultUniverseDomain is the universe domain of the Google Cloud public APIs.
+const DefaultUniverseDomain = "googleapis.com"
+
+func (c *Client) universeDomain() string {
+	if c.UniverseDomain != "" {
+		return c.UniverseDomain
+	}
+
+	return DefaultUniverseDomain
+}
+
+// checkUniverseDomain reports an error when the universe domain of the
+// credentials and the Client's endpoint do not agree.
+func (c *Client) checkUniverseDomain() error {
+	if c.credentialsUniverseDomain == "" {
+		return nil
+	}
+
+	if domain := c.universeDomain(); domain != c.credentialsUniverseDomain {
+		return fmt.Errorf("the configured universe domain (%q) does not match the universe domain found in the credentials (%q)", domain, c.credentialsUniverseDomain)
+	}
+
+	return nil
 }
 
 // A RequestOption can be passed on `Do` method to modify a Request.
@@ -43,29 +187,172 @@ func (q Query) Apply(r *http.Request) {
 	r.URL.RawQuery = query.Encode()
 }
 
-type gzipReadCloser struct {
-	gzipReader     io.ReadCloser
-	responseReader io.ReadCloser
+// WithAPIKey returns a `RequestOption` which authenticates a request with an API key
+// instead of OAuth. It can be used to read public ("anyone with the link") spreadsheets.
+//
+// Usage:
+//
+//	client := sheets.NewClient(nil)
+//	client.Options = append(client.Options, sheets.WithAPIKey("API_KEY"))
+func WithAPIKey(key string) RequestOption {
+	return Query{"key": []string{key}}
 }
 
-func (r *gzipReadCloser) Close() (lastErr error) {
-	r.gzipReader.Close()
-	return r.responseReader.Close()
+// maxDrainBytes is the maximum number of bytes read from a response body
+// on close so that the underlying connection can be reused.
+const maxDrainBytes = 64 << 10
+
+// responseBody wraps a response body.
+// It decompresses gzip bodies, reports the context error when the request's context
+// is done while reading and drains the body on close for connection reuse.
+type responseBody struct {
+	ctx        context.Context
+	body       io.ReadCloser // the original response body.
+	gzipReader *gzip.Reader  // nil if the body is not compressed.
+
+	limit int64 // the maximum number of bytes to read, zero means no limit.
+	read  int64 // the number of bytes read so far.
 }
 
-func (r *gzipReadCloser) Read(p []byte) (n int, err error) {
-	return r.gzipReader.Read(p)
+// ErrResponseTooLarge is returned when a response body exceeds the `Client.MaxResponseSize` limit.
+var ErrResponseTooLarge = errors.New("sheets: response too large")
+
+// gzipReaderPool holds the gzip readers of the closed response bodies,
+// they are reset to read the next compressed responses instead of allocating new ones.
+var gzipReaderPool sync.Pool
+
+func newResponseBody(ctx context.Context, body io.ReadCloser, compressed bool, limit int64) (*responseBody, error) {
+	r := &responseBody{ctx: ctx, body: body, limit: limit}
+	if compressed {
+		gr, ok := gzipReaderPool.Get().(*gzip.Reader)
+		if ok {
+			if err := gr.Reset(body); err != nil {
+				gzipReaderPool.Put(gr)
+				return nil, r.contextErr(err)
+			}
+		} else {
+			var err error
+			if gr, err = gzip.NewReader(body); err != nil {
+				return nil, r.contextErr(err)
+			}
+		}
+
+		r.gzipReader = gr
+	}
+
+	return r, nil
+}
+
+func (r *responseBody) Read(p []byte) (n int, err error) {
+	if r.limit > 0 {
+		if r.read >= r.limit {
+			// Allow a single byte more to tell whether the body exceeds the limit or ends exactly at it.
+			var b [1]byte
+			for {
+				probed, probeErr := r.read1(b[:])
+				if probed > 0 {
+					return 0, fmt.Errorf("%w: exceeds the limit of %d bytes", ErrResponseTooLarge, r.limit)
+				}
+				if probeErr != nil {
+					return 0, io.EOF
+				}
+			}
+		}
+
+		if remaining := r.limit - r.read; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+
+	n, err = r.read1(p)
+	r.read += int64(n)
+
+	if err != nil && err != io.EOF {
+		err = r.contextErr(err)
+	}
+
+	return
+}
+
+func (r *responseBody) read1(p []byte) (int, error) {
+	if r.gzipReader != nil {
+		return r.gzipReader.Read(p)
+	}
+
+	return r.body.Read(p)
+}
+
+func (r *responseBody) Close() error {
+	if r.gzipReader != nil {
+		r.gzipReader.Close()
+		gzipReaderPool.Put(r.gzipReader)
+		r.gzipReader = nil // do not read from (or put back) a reader which may be in use by another body.
+	}
+
+	if r.ctx.Err() == nil {
+		io.CopyN(io.Discard, r.body, maxDrainBytes)
+	}
+
+	return r.body.Close()
+}
+
+// contextErr returns the context's error instead of "err" if the context is done.
+func (r *responseBody) contextErr(err error) error {
+	if ctxErr := r.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// gzipBody compresses the contents of "body" to a new buffer.
+func gzipBody(body io.Reader) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := io.Copy(w, body); err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
 }
 
 // Do sends an HTTP request and returns an HTTP response.
 // It respects gzip and some settings specified to google's spreadsheet API.
+// If the context is done while reading the response body, the read returns the context's error.
+// Closing the response body drains it so that the connection can be reused.
 // The last option can be used to modify a request before sent to the server.
 func (c *Client) Do(ctx context.Context, method, url string, body io.Reader, options ...RequestOption) (*http.Response, error) {
+	if err := c.checkUniverseDomain(); err != nil {
+		return nil, err
+	}
+
+	compressed := false
+	if c.CompressThreshold > 0 && body != nil {
+		if b, ok := body.(interface{ Len() int }); ok && b.Len() >= c.CompressThreshold {
+			buf, err := gzipBody(body)
+			if err != nil {
+				return nil, err
+			}
+
+			body = buf
+			compressed = true
+		}
+	}
+
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
 	query := req.URL.Query()
 	query.Set("prettyPrint", "false")
 	req.URL.RawQuery = query.Encode()
@@ -73,71 +360,207 @@ func (c *Client) Do(ctx context.Context, method, url string, body io.Reader, opt
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Accept-Encoding", "gzip")
 
+	for _, opt := range c.Options {
+		opt.Apply(req)
+	}
+
 	for _, opt := range options {
 		opt.Apply(req)
 	}
 
+	if c.Breaker != nil && !c.Breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
 	response, err := c.HTTPClient.Do(req.WithContext(ctx))
 	if err != nil {
 		select {
 		case <-ctx.Done():
 			err = ctx.Err()
+			if c.Breaker != nil {
+				if err == context.DeadlineExceeded {
+					c.Breaker.Failure()
+				} else {
+					c.Breaker.release()
+				}
+			}
 		default:
+			if c.Breaker != nil {
+				c.Breaker.Failure()
+			}
 		}
 		if response != nil && response.Body != nil {
 			response.Body.Close()
 		}
 
-		return nil, err
+		return nil, newTransportError(req, err)
 	}
 
-	if encoding := response.Header.Get("Content-Encoding"); encoding == "gzip" {
-		r, err := gzip.NewReader(response.Body)
-		if err != nil {
-			return nil, err
+	if c.Breaker != nil {
+		if response.StatusCode >= http.StatusInternalServerError {
+			c.Breaker.Failure()
+		} else {
+			c.Breaker.Success()
 		}
-		response.Body = &gzipReadCloser{responseReader: response.Body, gzipReader: r}
 	}
 
-	return response, err
+	if c.MaxResponseSize > 0 && response.ContentLength > c.MaxResponseSize && response.Header.Get("Content-Encoding") != "gzip" {
+		response.Body.Close()
+		return nil, fmt.Errorf("%w: %s %s: content length of %d bytes exceeds the limit of %d bytes",
+			ErrResponseTooLarge, method, url, response.ContentLength, c.MaxResponseSize)
+	}
+
+	respBody, err := newResponseBody(ctx, response.Body, response.Header.Get("Content-Encoding") == "gzip", c.MaxResponseSize)
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	response.Body = respBody
+
+	return response, nil
 }
 
 // ReadJSON fires a request to "url" and binds a JSON response to the "toPtr".
 func (c *Client) ReadJSON(ctx context.Context, method, url string, requestData, toPtr interface{}, options ...RequestOption) error {
-	var requestBody io.Reader
+	var requestBody []byte
 
 	if requestData != nil {
-		buf := new(bytes.Buffer)
-		err := json.NewEncoder(buf).Encode(requestData)
+		b, err := c.codec().Marshal(requestData)
 		if err != nil {
 			return err
 		}
 
-		requestBody = buf
+		requestBody = b
 	}
 
-	resp, err := c.Do(ctx, method, url, requestBody, options...)
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		err := c.readJSON(ctx, method, url, requestBody, toPtr, options...)
+		if err == nil {
+			return nil
+		}
+
+		var resErr *ResourceError
+		if !errors.As(err, &resErr) {
+			return err
+		}
+
+		if resErr.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			// The cached token may be revoked, invalidate it and retry once.
+			if invalidator, ok := c.tokenInvalidator(); ok {
+				invalidator.InvalidateToken()
+				reauthenticated = true
+				continue
+			}
+		}
+
+		if c.onQuotaExceeded == nil || attempt >= maxQuotaRetries || !resErr.QuotaExceeded() {
+			return err
+		}
+
+		wait := resErr.RetryAfter
+		if wait <= 0 {
+			wait = time.Second << attempt
+		}
+
+		c.onQuotaExceeded(wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// authTransport returns the authentication transport of the Client, if any.
+func (c *Client) authTransport() http.RoundTripper {
+	if c.HTTPClient == nil {
+		return nil
+	}
+
+	transport := c.HTTPClient.Transport
+	if t, ok := transport.(*debugTransport); ok {
+		transport = t.base
+	}
+
+	return transport
+}
+
+// tokenInvalidator returns the authentication transport of the Client
+// if it can invalidate its cached token.
+func (c *Client) tokenInvalidator() (interface{ InvalidateToken() }, bool) {
+	invalidator, ok := c.authTransport().(interface{ InvalidateToken() })
+	return invalidator, ok
+}
+
+func (c *Client) readJSON(ctx context.Context, method, url string, requestBody []byte, toPtr interface{}, options ...RequestOption) error {
+	var body io.Reader
+	if requestBody != nil {
+		body = bytes.NewBuffer(requestBody)
+	}
+
+	resp, err := c.Do(ctx, method, url, body, options...)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNoContent {
+		// E.g. a Drive channel stop request, there is nothing to decode.
+		return nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
 		return newResourceError(resp)
 	}
 
-	return json.NewDecoder(resp.Body).Decode(toPtr)
+	dec := c.codec().NewDecoder(resp.Body)
+	if c.StrictJSON {
+		if d, ok := dec.(interface{ DisallowUnknownFields() }); ok {
+			d.DisallowUnknownFields()
+		}
+	}
+
+	if err = dec.Decode(toPtr); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		return err
+	}
+
+	return nil
 }
 
-const spreadsheetURL = "https://sheets.googleapis.com/v4/spreadsheets/%s"
+func (c *Client) codec() JSONCodec {
+	if c.JSON != nil {
+		return c.JSON
+	}
+
+	return DefaultJSONCodec
+}
+
+const spreadsheetURL = "/v4/spreadsheets/%s"
+
+// apiURL returns the full URL of a Sheets API path of the Client's universe domain.
+func (c *Client) apiURL(format string, args ...interface{}) string {
+	return "https://sheets." + c.universeDomain() + fmt.Sprintf(format, args...)
+}
 
 // GetSpreadsheetInfo returns general information about a spreadsheet based on the provided "spreadsheetID".
 func (c *Client) GetSpreadsheetInfo(ctx context.Context, spreadsheetID string) (*Spreadsheet, error) {
-	url := fmt.Sprintf(spreadsheetURL, spreadsheetID)
+	url := c.apiURL(spreadsheetURL, spreadsheetID)
 	sd := &Spreadsheet{}
 	err := c.ReadJSON(ctx, http.MethodGet, url, nil, sd)
 	if err != nil {
-		return nil, err
+		return nil, c.opError(err, "spreadsheets.get", spreadsheetID, "", 0)
 	}
 
 	return sd, nil
@@ -147,33 +570,94 @@ const (
 	spreadsheetValuesURL         = spreadsheetURL + "/values/%s"
 	spreadsheetValuesBatchGetURL = spreadsheetURL + "/values:batchGet"
 	spreadsheetValuesClearURL    = spreadsheetValuesURL + ":clear"
+	spreadsheetValuesAppendURL   = spreadsheetValuesURL + ":append"
 	spreadsheetBatchUpdateURL    = spreadsheetURL + ":batchUpdate"
+	spreadsheetValuesBatchURL    = spreadsheetURL + "/values:batchUpdate"
 )
 
 // Range returns record values of a spreadsheet based on the provided "dataRanges", if more than one data range then it sends a batch request.
+// A batch request of many ranges is split to multiple ones so that their URL length does not exceed the `Client.MaxURLLength`,
+// the results are merged in order.
 // See `ReadSpreadsheet` method too.
 func (c *Client) Range(ctx context.Context, spreadsheetID string, dataRanges ...string) ([]ValueRange, error) {
+	if c.CoalesceReads {
+		return c.reads.do(ctx, coalesceKey(spreadsheetID, dataRanges), func(ctx context.Context) ([]ValueRange, error) {
+			return c.rangeValues(ctx, spreadsheetID, dataRanges...)
+		})
+	}
+
+	return c.rangeValues(ctx, spreadsheetID, dataRanges...)
+}
+
+func (c *Client) rangeValues(ctx context.Context, spreadsheetID string, dataRanges ...string) ([]ValueRange, error) {
+	for _, dataRange := range dataRanges {
+		if err := ValidateRange(dataRange); err != nil {
+			return nil, c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+		}
+	}
+
 	if len(dataRanges) == 1 {
 		// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/get
-		url := fmt.Sprintf(spreadsheetValuesURL, spreadsheetID, dataRanges[0])
+		url := c.apiURL(spreadsheetValuesURL, spreadsheetID, dataRanges[0])
 
 		var payload ValueRange
 		err := c.ReadJSON(ctx, http.MethodGet, url, nil, &payload)
 		if err != nil {
-			return nil, err
+			return nil, c.opError(err, "values.get", spreadsheetID, dataRanges[0], 0)
 		}
 
 		return []ValueRange{payload}, nil
 	}
 
 	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/batchGet
-	url := fmt.Sprintf(spreadsheetValuesBatchGetURL, spreadsheetID)
+	url := c.apiURL(spreadsheetValuesBatchGetURL, spreadsheetID)
+
+	chunks := c.splitRanges(url, dataRanges)
+	if len(chunks) == 1 {
+		valueRanges, err := c.batchGet(ctx, url, chunks[0])
+		return valueRanges, c.opError(err, "values.batchGet", spreadsheetID, strings.Join(dataRanges, ","), 0)
+	}
+
+	results := make([][]ValueRange, len(chunks))
+	errs := make([]error, len(chunks))
+
+	if c.ParallelBatchGet {
+		var wg sync.WaitGroup
+		for i, chunk := range chunks {
+			wg.Add(1)
+			go func(i int, chunk []string) {
+				defer wg.Done()
+				results[i], errs[i] = c.batchGet(ctx, url, chunk)
+			}(i, chunk)
+		}
+		wg.Wait()
+	} else {
+		for i, chunk := range chunks {
+			if results[i], errs[i] = c.batchGet(ctx, url, chunk); errs[i] != nil {
+				break
+			}
+		}
+	}
+
+	var valueRanges []ValueRange
+	for i := range chunks {
+		if errs[i] != nil {
+			return nil, c.opError(errs[i], "values.batchGet", spreadsheetID, strings.Join(chunks[i], ","), 0)
+		}
+
+		valueRanges = append(valueRanges, results[i]...)
+	}
+
+	return valueRanges, nil
+}
+
+func (c *Client) batchGet(ctx context.Context, endpoint string, dataRanges []string) ([]ValueRange, error) {
 	q := Query{"ranges": dataRanges}
 
 	var payload = struct {
 		ValueRanges []ValueRange `json:"valueRanges"`
 	}{}
-	err := c.ReadJSON(ctx, http.MethodGet, url, nil, &payload, q)
+	err := c.ReadJSON(ctx, http.MethodGet, endpoint, nil, &payload, q)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +665,36 @@ func (c *Client) Range(ctx context.Context, spreadsheetID string, dataRanges ...
 	return payload.ValueRanges, nil
 }
 
+// splitRanges splits the "dataRanges" into groups so that
+// the batchGet URL of each group does not exceed the client's max URL length.
+func (c *Client) splitRanges(endpoint string, dataRanges []string) [][]string {
+	maxLength := c.MaxURLLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxURLLength
+	}
+
+	var (
+		chunks [][]string
+		chunk  []string
+		// the base URL plus the "prettyPrint=false" query.
+		baseLength = len(endpoint) + len("?prettyPrint=false")
+		length     = baseLength
+	)
+
+	for _, dataRange := range dataRanges {
+		n := len("&ranges=") + len(url.QueryEscape(dataRange))
+		if len(chunk) > 0 && length+n > maxLength {
+			chunks = append(chunks, chunk)
+			chunk, length = nil, baseLength
+		}
+
+		chunk = append(chunk, dataRange)
+		length += n
+	}
+
+	return append(chunks, chunk)
+}
+
 // ReadSpreadsheet binds record values of a spreadsheet to the "dest".
 // See `Range` method too.
 func (c *Client) ReadSpreadsheet(ctx context.Context, dest interface{}, spreadsheetID string, dataRanges ...string) error {
@@ -189,6 +703,10 @@ func (c *Client) ReadSpreadsheet(ctx context.Context, dest interface{}, spreadsh
 		return err
 	}
 
+	if c.Decoder != nil {
+		return c.Decoder.Decode(dest, valueRanges...)
+	}
+
 	return DecodeValueRange(dest, valueRanges...)
 }
 
@@ -199,14 +717,22 @@ func (c *Client) ClearSpreadsheet(ctx context.Context, spreadsheetID, dataRange
 		dataRange = "A1:Z"
 	}
 
+	if err = ValidateRange(dataRange); err != nil {
+		err = c.opError(err, "values.clear", spreadsheetID, dataRange, 0)
+		return
+	}
+
 	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/clear
-	url := fmt.Sprintf(spreadsheetValuesClearURL, spreadsheetID, dataRange)
+	url := c.apiURL(spreadsheetValuesClearURL, spreadsheetID, dataRange)
 	err = c.ReadJSON(ctx, http.MethodPost, url, nil, &response)
+	err = c.opError(err, "values.clear", spreadsheetID, dataRange, 0)
 	return
 }
 
 // UpdateSpreadsheet updates a spreadsheet of a range of provided "dataRange",
 // if "dataRange" is empty or "*" then it will update all columns specified by "values".
+// Values larger than the `Client.MaxWriteRows` or `Client.MaxWriteBytes` limits are written
+// by sequential requests, see `Client.MaxWriteRows` field.
 func (c *Client) UpdateSpreadsheet(ctx context.Context, spreadsheetID string, values ValueRange) (response UpdateValuesResponse, err error) {
 	if values.Range == "" || values.Range == "*" {
 		values.Range = "A1:Z"
@@ -216,8 +742,21 @@ func (c *Client) UpdateSpreadsheet(ctx context.Context, spreadsheetID string, va
 		values.MajorDimension = Rows
 	}
 
+	if err = ValidateRange(values.Range); err != nil {
+		err = c.opError(err, "values.update", spreadsheetID, values.Range, len(values.Values))
+		return
+	}
+
+	if chunks := c.writeChunks(values); len(chunks) > 1 {
+		return c.updateChunks(ctx, spreadsheetID, chunks)
+	}
+
+	return c.updateValues(ctx, spreadsheetID, values)
+}
+
+func (c *Client) updateValues(ctx context.Context, spreadsheetID string, values ValueRange) (response UpdateValuesResponse, err error) {
 	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/update
-	url := fmt.Sprintf(spreadsheetValuesURL, spreadsheetID, values.Range)
+	url := c.apiURL(spreadsheetValuesURL, spreadsheetID, values.Range)
 
 	q := Query{
 		"valueInputOption":        []string{"RAW"},
@@ -225,16 +764,176 @@ func (c *Client) UpdateSpreadsheet(ctx context.Context, spreadsheetID string, va
 	}
 
 	err = c.ReadJSON(ctx, http.MethodPut, url, values, &response, q)
+	err = c.opError(err, "values.update", spreadsheetID, values.Range, len(values.Values))
+	return
+}
+
+// BatchUpdateValues updates multiple ranges of a spreadsheet in a single request,
+// e.g. scattered cells which would otherwise need one `UpdateSpreadsheet` call each.
+// Each value range must have a non-empty range.
+func (c *Client) BatchUpdateValues(ctx context.Context, spreadsheetID string, valueRanges ...ValueRange) (response BatchUpdateValuesResponse, err error) {
+	rows := 0
+	ranges := make([]string, len(valueRanges))
+	for i := range valueRanges {
+		if valueRanges[i].MajorDimension == "" {
+			valueRanges[i].MajorDimension = Rows
+		}
+
+		if err = ValidateRange(valueRanges[i].Range); err != nil {
+			err = c.opError(err, "values.batchUpdate", spreadsheetID, valueRanges[i].Range, len(valueRanges[i].Values))
+			return
+		}
 
+		ranges[i] = valueRanges[i].Range
+		rows += len(valueRanges[i].Values)
+	}
+
+	if len(valueRanges) == 0 {
+		response.SpreadsheetID = spreadsheetID
+		return
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/batchUpdate
+	url := c.apiURL(spreadsheetValuesBatchURL, spreadsheetID)
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, batchUpdateValuesRequest{
+		ValueInputOption: "RAW",
+		Data:             valueRanges,
+	}, &response)
+	err = c.opError(err, "values.batchUpdate", spreadsheetID, strings.Join(ranges, ","), rows)
 	return
 }
 
+type batchUpdateValuesRequest struct {
+	ValueInputOption string       `json:"valueInputOption"`
+	Data             []ValueRange `json:"data"`
+}
+
+// AppendSpreadsheet appends values to a spreadsheet. The "values.Range" is used to search for a table,
+// the values are appended after the last row of that table.
+// If "values.Range" is empty or "*" then it searches the whole first sheet.
+func (c *Client) AppendSpreadsheet(ctx context.Context, spreadsheetID string, values ValueRange) (response AppendValuesResponse, err error) {
+	if values.Range == "" || values.Range == "*" {
+		values.Range = "A1:Z"
+	}
+
+	if values.MajorDimension == "" {
+		values.MajorDimension = Rows
+	}
+
+	if err = ValidateRange(values.Range); err != nil {
+		err = c.opError(err, "values.append", spreadsheetID, values.Range, len(values.Values))
+		return
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/append
+	url := c.apiURL(spreadsheetValuesAppendURL, spreadsheetID, values.Range)
+
+	q := Query{
+		"valueInputOption":        []string{"RAW"},
+		"insertDataOption":        []string{"INSERT_ROWS"},
+		"includeValuesInResponse": []string{"false"},
+	}
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, values, &response, q)
+	err = c.opError(err, "values.append", spreadsheetID, values.Range, len(values.Values))
+	return
+}
+
+// NextRow returns the one-based number of the first empty row after the table of the sheet with "sheetTitle",
+// so that writers which cannot use the append semantics still know where to write, e.g. "A" + strconv.Itoa(row).
+// It sends an append request without values, which finds the table but does not write anything.
+func (c *Client) NextRow(ctx context.Context, spreadsheetID, sheetTitle string) (int, error) {
+	response, err := c.AppendSpreadsheet(ctx, spreadsheetID, ValueRange{Range: QuoteSheetTitle(sheetTitle) + "!A1"})
+	if err != nil {
+		return 0, err
+	}
+
+	if response.TableRange == "" { // no table, the sheet is empty.
+		return 1, nil
+	}
+
+	_, tableRange, err := ParseA1(response.TableRange)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tableRange.EndRowIndex) + 1, nil
+}
+
+// ColumnRange returns the data range of the column with the "headerName" in the header (first) row
+// of the sheet with "sheetTitle", e.g. "Sheet1!D2:D", so that code keyed on column names
+// survives users inserting or moving columns.
+// It returns an error which matches the `ErrNotFound` if the header row does not contain the "headerName".
+func (c *Client) ColumnRange(ctx context.Context, spreadsheetID, sheetTitle, headerName string) (string, error) {
+	valueRanges, err := c.Range(ctx, spreadsheetID, WholeRow(sheetTitle, 1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(valueRanges) > 0 && len(valueRanges[0].Values) > 0 {
+		for i, cell := range valueRanges[0].Values[0] {
+			if strings.TrimSpace(fmt.Sprint(cell)) == headerName {
+				r := GridRange{StartRowIndex: 1, StartColumnIndex: int64(i), EndColumnIndex: int64(i + 1)}
+				return r.A1(sheetTitle), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("sheets: header %q not found in sheet %q: %w", headerName, sheetTitle, ErrNotFound)
+}
+
 type batchUpdate struct {
 	Requests []batchUpdateRequest `json:"requests,omitempty"`
 }
 
 type batchUpdateRequest struct {
-	AddChart batchUpdateAddChartRequest `json:"addChart,omitempty"`
+	AddChart              *batchUpdateAddChartRequest              `json:"addChart,omitempty"`
+	UpdateSheetProperties *batchUpdateUpdateSheetPropertiesRequest `json:"updateSheetProperties,omitempty"`
+	DeleteDimension       *batchUpdateDeleteDimensionRequest       `json:"deleteDimension,omitempty"`
+	UpdateCells           *batchUpdateUpdateCellsRequest           `json:"updateCells,omitempty"`
+}
+
+type batchUpdateUpdateCellsRequest struct {
+	Start  batchUpdateGridCoordinate `json:"start"`
+	Rows   []batchUpdateRowData      `json:"rows"`
+	Fields string                    `json:"fields"`
+}
+
+type batchUpdateGridCoordinate struct {
+	SheetID     int64 `json:"sheetId"`
+	RowIndex    int   `json:"rowIndex"`
+	ColumnIndex int   `json:"columnIndex"`
+}
+
+type batchUpdateRowData struct {
+	Values []CellData `json:"values"`
+}
+
+type batchUpdateDeleteDimensionRequest struct {
+	Range batchUpdateDimensionRange `json:"range"`
+}
+
+type batchUpdateDimensionRange struct {
+	SheetID    int64  `json:"sheetId"`
+	Dimension  string `json:"dimension"`
+	StartIndex int64  `json:"startIndex"`
+	EndIndex   int64  `json:"endIndex"`
+}
+
+type batchUpdateUpdateSheetPropertiesRequest struct {
+	Properties batchUpdateSheetProperties `json:"properties"`
+	Fields     string                     `json:"fields"`
+}
+
+type batchUpdateSheetProperties struct {
+	SheetID int64                      `json:"sheetId"`
+	Grid    *batchUpdateGridProperties `json:"gridProperties,omitempty"`
+}
+
+type batchUpdateGridProperties struct {
+	RowCount    int `json:"rowCount,omitempty"`
+	ColumnCount int `json:"columnCount,omitempty"`
 }
 
 type batchUpdateAddChartRequest struct {
@@ -244,14 +943,102 @@ type batchUpdateAddChartRequest struct {
 // AddChart creates or updates an existing chart to a spreadsheet.
 func (c *Client) AddChart(ctx context.Context, spreadsheetID string, chart Chart) (response BatchUpdateResponse, err error) {
 	// https://developers.google.com/sheets/api/samples/charts#add_a_column_chart
-	url := fmt.Sprintf(spreadsheetBatchUpdateURL, spreadsheetID)
+	url := c.apiURL(spreadsheetBatchUpdateURL, spreadsheetID)
 
 	err = c.ReadJSON(ctx, http.MethodPost, url, batchUpdate{
 		Requests: []batchUpdateRequest{
-			{AddChart: batchUpdateAddChartRequest{
+			{AddChart: &batchUpdateAddChartRequest{
 				Chart: chart,
 			}},
 		},
 	}, &response)
+	err = c.opError(err, "spreadsheets.batchUpdate/addChart", spreadsheetID, "", 0)
+	return
+}
+
+// ResizeSheet sets the number of rows and columns of the grid of the sheet with "sheetID".
+// A zero "rows" or "columns" value keeps the current value.
+func (c *Client) ResizeSheet(ctx context.Context, spreadsheetID string, sheetID int64, rows, columns int) (response BatchUpdateResponse, err error) {
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#updatesheetpropertiesrequest
+	url := c.apiURL(spreadsheetBatchUpdateURL, spreadsheetID)
+
+	var fields []string
+	if rows > 0 {
+		fields = append(fields, "gridProperties.rowCount")
+	}
+	if columns > 0 {
+		fields = append(fields, "gridProperties.columnCount")
+	}
+	if len(fields) == 0 {
+		return BatchUpdateResponse{SpreadsheetID: spreadsheetID}, nil
+	}
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, batchUpdate{
+		Requests: []batchUpdateRequest{
+			{UpdateSheetProperties: &batchUpdateUpdateSheetPropertiesRequest{
+				Properties: batchUpdateSheetProperties{
+					SheetID: sheetID,
+					Grid:    &batchUpdateGridProperties{RowCount: rows, ColumnCount: columns},
+				},
+				Fields: strings.Join(fields, ","),
+			}},
+		},
+	}, &response)
+	c.InvalidateSpreadsheetInfo(spreadsheetID)
+	err = c.opError(err, "spreadsheets.batchUpdate/updateSheetProperties", spreadsheetID, "", 0)
+	return
+}
+
+// DeleteRows deletes the rows from the zero-based "startIndex" (inclusive) to "endIndex" (exclusive)
+// of the sheet with "sheetID", the rows below them are shifted up.
+func (c *Client) DeleteRows(ctx context.Context, spreadsheetID string, sheetID, startIndex, endIndex int64) (BatchUpdateResponse, error) {
+	return c.deleteRows(ctx, spreadsheetID, sheetID, [][2]int64{{startIndex, endIndex}})
+}
+
+// deleteRows deletes multiple row ranges of a sheet in a single request.
+// The "ranges" are applied in order, so they should be sorted from the bottom to the top of the sheet.
+func (c *Client) deleteRows(ctx context.Context, spreadsheetID string, sheetID int64, ranges [][2]int64) (response BatchUpdateResponse, err error) {
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#deletedimensionrequest
+	url := c.apiURL(spreadsheetBatchUpdateURL, spreadsheetID)
+
+	requests := make([]batchUpdateRequest, len(ranges))
+	for i, r := range ranges {
+		requests[i].DeleteDimension = &batchUpdateDeleteDimensionRequest{
+			Range: batchUpdateDimensionRange{SheetID: sheetID, Dimension: Rows, StartIndex: r[0], EndIndex: r[1]},
+		}
+	}
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, batchUpdate{Requests: requests}, &response)
+	c.InvalidateSpreadsheetInfo(spreadsheetID)
+	err = c.opError(err, "spreadsheets.batchUpdate/deleteDimension", spreadsheetID, "", 0)
+	return
+}
+
+// UpdateCells writes the "rows" of cell data to the sheet with "sheetID" starting at the "start" cell,
+// e.g. rich text values built by `RichText`. Only the cell "fields" are written (e.g. "userEnteredValue,note"),
+// an empty "fields" writes the values and their text format runs, the rest of the cell's format is kept.
+func (c *Client) UpdateCells(ctx context.Context, spreadsheetID string, sheetID int64, start Cell, rows [][]CellData, fields string) (response BatchUpdateResponse, err error) {
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#updatecellsrequest
+	url := c.apiURL(spreadsheetBatchUpdateURL, spreadsheetID)
+
+	if fields == "" {
+		fields = "userEnteredValue,textFormatRuns"
+	}
+
+	rowData := make([]batchUpdateRowData, len(rows))
+	for i, row := range rows {
+		rowData[i].Values = row
+	}
+
+	err = c.ReadJSON(ctx, http.MethodPost, url, batchUpdate{
+		Requests: []batchUpdateRequest{
+			{UpdateCells: &batchUpdateUpdateCellsRequest{
+				Start:  batchUpdateGridCoordinate{SheetID: sheetID, RowIndex: start.Row, ColumnIndex: start.Col},
+				Rows:   rowData,
+				Fields: fields,
+			}},
+		},
+	}, &response)
+	err = c.opError(err, "spreadsheets.batchUpdate/updateCells", spreadsheetID, start.A1(), len(rows))
 	return
 }
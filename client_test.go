@@ -0,0 +1,486 @@
+package sheets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientCompressRequestBody(t *testing.T) {
+	payload := strings.Repeat("sheets", 100)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer gr.Close()
+			body = gr
+		}
+
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("X-Compressed", r.Header.Get("Content-Encoding"))
+		w.Write(b)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		threshold  int
+		compressed bool
+	}{
+		{0, false},
+		{len(payload) + 1, false},
+		{len(payload), true},
+	}
+
+	for i, tt := range tests {
+		c := NewClient(http.DefaultTransport)
+		c.CompressThreshold = tt.threshold
+
+		resp, err := c.Do(context.Background(), http.MethodPost, srv.URL, bytes.NewBufferString(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := string(b); got != payload {
+			t.Fatalf("[%d] expected body to be echoed back but got %q", i, got)
+		}
+
+		if expected, got := tt.compressed, resp.Header.Get("X-Compressed") == "gzip"; expected != got {
+			t.Fatalf("[%d] expected compressed: %v but got %v", i, expected, got)
+		}
+	}
+}
+
+func TestClientDebug(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"range":"A1:B1"}`))
+	}))
+	defer srv.Close()
+
+	buf := new(bytes.Buffer)
+
+	c := NewClient(http.DefaultTransport)
+	c.Debug(buf)
+
+	var payload ValueRange
+	err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload, Query{"key": []string{"secret"}}, requestOptionFunc(func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer secret")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Fatalf("expected credentials to be redacted but got:\n%s", out)
+	}
+	if !strings.Contains(out, `{"range":"A1:B1"}`) {
+		t.Fatalf("expected response body to be dumped but got:\n%s", out)
+	}
+
+	c.Debug(nil)
+	buf.Reset()
+	if err = c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > 0 {
+		t.Fatalf("expected no debug output but got:\n%s", buf.String())
+	}
+}
+
+type requestOptionFunc func(*http.Request)
+
+func (fn requestOptionFunc) Apply(r *http.Request) { fn(r) }
+
+func TestClientRangeSplit(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		var payload struct {
+			ValueRanges []ValueRange `json:"valueRanges"`
+		}
+		for _, dataRange := range r.URL.Query()["ranges"] {
+			payload.ValueRanges = append(payload.ValueRanges, ValueRange{Range: dataRange})
+		}
+		json.NewEncoder(w).Encode(payload)
+	}))
+	defer srv.Close()
+
+	dataRanges := make([]string, 100)
+	for i := range dataRanges {
+		dataRanges[i] = fmt.Sprintf("'Sheet %d'!A1:Z%d", i, i+1)
+	}
+
+	for _, parallel := range []bool{false, true} {
+		atomic.StoreInt32(&requests, 0)
+
+		c := NewClient(rewriteTransport(srv.URL))
+		c.MaxURLLength = 512
+		c.ParallelBatchGet = parallel
+
+		valueRanges, err := c.Range(context.Background(), "test", dataRanges...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := atomic.LoadInt32(&requests); n < 2 {
+			t.Fatalf("expected the batchGet request to be split but got %d requests", n)
+		}
+
+		if expected, got := len(dataRanges), len(valueRanges); expected != got {
+			t.Fatalf("expected %d value ranges but got %d", expected, got)
+		}
+
+		for i, vr := range valueRanges {
+			if expected, got := dataRanges[i], vr.Range; expected != got {
+				t.Fatalf("[%d] expected range %q but got %q", i, expected, got)
+			}
+		}
+	}
+}
+
+// rewriteTransport sends all requests to the "target" server.
+func rewriteTransport(target string) http.RoundTripper {
+	u, _ := url.Parse(target)
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.URL.Scheme, r.URL.Host, r.Host = u.Scheme, u.Host, ""
+		return http.DefaultTransport.RoundTrip(r)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return fn(r) }
+
+func TestClientCoalesceReads(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(`{"range":"A1:B1","values":[["makis"]]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	c.CoalesceReads = true
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.Range(context.Background(), "test", "A1:B1")
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected a single request but got %d", got)
+	}
+}
+
+type countingJSONCodec struct {
+	marshals, decoders int
+}
+
+func (c *countingJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	c.decoders++
+	return json.NewDecoder(r)
+}
+
+func TestClientJSONCodec(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	codec := new(countingJSONCodec)
+	c := NewClient(http.DefaultTransport)
+	c.JSON = codec
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodPost, srv.URL, ValueRange{Range: "A1"}, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if codec.marshals != 1 || codec.decoders != 1 {
+		t.Fatalf("expected the custom codec to be used but got %d marshals and %d decoders", codec.marshals, codec.decoders)
+	}
+	if expected, got := "A1", payload.Range; expected != got {
+		t.Fatalf("expected range %q but got %q", expected, got)
+	}
+}
+
+type universeTransport struct {
+	http.RoundTripper
+	domain string
+}
+
+func (t universeTransport) UniverseDomain() string { return t.domain }
+
+func TestClientUniverseDomain(t *testing.T) {
+	var host string
+	transport := universeTransport{
+		RoundTripper: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			host = r.URL.Host
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Header: make(http.Header), Request: r}, nil
+		}),
+		domain: "example-tpc.goog",
+	}
+
+	c := NewClient(transport)
+	if _, err := c.GetSpreadsheetInfo(context.Background(), "test"); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "sheets.example-tpc.goog", host; expected != got {
+		t.Fatalf("expected host %q but got %q", expected, got)
+	}
+
+	c.UniverseDomain = DefaultUniverseDomain
+	if _, err := c.GetSpreadsheetInfo(context.Background(), "test"); err == nil {
+		t.Fatal("expected an error on universe domain mismatch")
+	}
+}
+
+func TestClientCancelMidRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte(`{"range":"A1","values":[`))
+		gw.Flush()
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	c := NewClient(http.DefaultTransport)
+	var payload ValueRange
+	err := c.ReadJSON(ctx, http.MethodGet, srv.URL, nil, &payload)
+	if err != context.Canceled {
+		t.Fatalf("expected context canceled error but got %v", err)
+	}
+}
+
+func TestClientErrorWrapping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	_, err := c.Range(ctx, "id", "Sheet1!A1:B")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a wrapped deadline exceeded error but got %v", err)
+	}
+
+	if expected, got := "sheets: values.get id!Sheet1!A1:B: ", err.Error(); !strings.HasPrefix(got, expected) {
+		t.Fatalf("expected error to start with %q but got %q", expected, got)
+	}
+
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || !transportErr.DeadlineExceeded || transportErr.Canceled {
+		t.Fatalf("expected a transport error of an exceeded deadline but got %#v", err)
+	}
+
+	srv.Close()
+	_, err = c.Range(context.Background(), "id", "Sheet1!A1:B")
+	if !errors.As(err, &transportErr) || transportErr.ContextDone() {
+		t.Fatalf("expected a transport error of a network failure but got %#v", err)
+	}
+}
+
+func TestClientValidateRange(t *testing.T) {
+	c := NewClient(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request to %s", r.URL)
+		return nil, nil
+	}))
+
+	if _, err := c.Range(context.Background(), "id", "A1:B2", "My Sheet!A1"); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an invalid range error but got %v", err)
+	}
+
+	if _, err := c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "Sheet1!B2:A1"}); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an invalid range error but got %v", err)
+	}
+}
+
+func TestClientStrictJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"range":"A1","newField":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultTransport)
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	c.StrictJSON = true
+	err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload)
+	if err == nil || !strings.Contains(err.Error(), "newField") {
+		t.Fatalf("expected an unknown field error but got %v", err)
+	}
+}
+
+func TestClientMaxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("gzip") != "" {
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			gw.Write([]byte(`{"range":"A1","values":[["` + strings.Repeat("x", 4096) + `"]]}`))
+			gw.Close()
+			return
+		}
+
+		w.Write([]byte(`{"range":"A1","values":[["` + strings.Repeat("x", 4096) + `"]]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultTransport)
+	c.MaxResponseSize = 1024
+
+	for _, endpoint := range []string{srv.URL, srv.URL + "?gzip=1"} {
+		var payload ValueRange
+		err := c.ReadJSON(context.Background(), http.MethodGet, endpoint, nil, &payload)
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Fatalf("[%s] expected a response too large error but got %v", endpoint, err)
+		}
+	}
+
+	c.MaxResponseSize = 8192
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL+"?gzip=1", nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientWithAPIKey(t *testing.T) {
+	var key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.URL.Query().Get("key")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	c.Options = append(c.Options, WithAPIKey("API_KEY"))
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "API_KEY", key; expected != got {
+		t.Fatalf("expected key %q but got %q", expected, got)
+	}
+}
+
+func TestClientGzipReaderReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		if r.URL.Query().Get("invalid") != "" {
+			w.Write([]byte("not gzip"))
+			return
+		}
+
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte(`{"range":"` + r.URL.Query().Get("range") + `"}`))
+		gw.Close()
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultTransport)
+	for i, dataRange := range []string{"A1", "B2", "invalid", "C3", "D4"} {
+		var payload ValueRange
+		err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload, Query{"range": []string{dataRange}, dataRange: []string{"1"}})
+		if dataRange == "invalid" {
+			if err == nil {
+				t.Fatalf("[%d] expected an invalid gzip body error", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if payload.Range != dataRange {
+			t.Fatalf("[%d] expected range %q but got %q", i, dataRange, payload.Range)
+		}
+	}
+}
+
+func TestClientGetSpreadsheetInfoSheetID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The API sends the sheet ID as a number.
+		w.Write([]byte(`{"spreadsheetId":"id","sheets":[{"properties":{"sheetId":0,"title":"Sheet1"}},{"properties":{"sheetId":1234567890,"title":"Users","gridProperties":{"rowCount":1000,"columnCount":26}}}]}`))
+	}))
+	defer srv.Close()
+
+	sd, err := NewClient(rewriteTransport(srv.URL)).GetSpreadsheetInfo(context.Background(), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, ok := sd.GetSheet("Users")
+	if !ok {
+		t.Fatalf("expected the Users sheet but got %#v", sd.Sheets)
+	}
+
+	if expected, got := int64(1234567890), sheet.Properties.ID; expected != got {
+		t.Fatalf("expected sheet ID %d but got %d", expected, got)
+	}
+}
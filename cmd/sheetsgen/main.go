@@ -0,0 +1,332 @@
+// Command sheetsgen generates reflection-free `DecodeRow` and `EncodeRow` methods
+// for struct types, so services which decode millions of rows skip the reflection
+// of `sheets.DecodeValueRange` on their hot paths.
+//
+// The generated methods implement the `sheets.RowDecoder` and `sheets.RowEncoder` interfaces
+// and behave like the reflection-based decoding and encoding: the headers are the exported fields,
+// renamed or skipped by the "sheets" struct tag, in their declaration order.
+// Fields of types other than strings, numbers, bools, time.Time and pointers to them
+// are still decoded and encoded through reflection.
+//
+// Annotate the structs with a "//sheets:generate" comment line:
+//
+//	//go:generate go run github.com/kataras/sheets/cmd/sheetsgen
+//
+//	//sheets:generate
+//	type User struct {
+//		Name  string
+//		Email string `sheets:"E-mail"`
+//		Age   int
+//	}
+//
+// or select them with the -type flag. The methods are written
+// to a "<file>_sheets.go" file next to the source file, which is the $GOFILE of go:generate by default.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const annotation = "//sheets:generate"
+
+func main() {
+	var (
+		typeNames = flag.String("type", "", "comma-separated list of the struct type names, defaults to the annotated ones")
+		output    = flag.String("output", "", "output file name, defaults to <file>_sheets.go")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sheetsgen [flags] [file.go]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	filename := os.Getenv("GOFILE")
+	if flag.NArg() > 0 {
+		filename = flag.Arg(0)
+	}
+	if filename == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		fatal(err)
+	}
+
+	code, err := generate(filename, src, types)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(filename, ".go") + "_sheets.go"
+	}
+
+	if err = os.WriteFile(*output, code, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "sheetsgen: %v\n", err)
+	os.Exit(1)
+}
+
+// field is a header field of a struct.
+type field struct {
+	name   string // the Go field name.
+	header string // the sheet header name.
+	typ    string // the Go type expression, e.g. "*int".
+}
+
+// generate returns the formatted source code of the methods of the "types" structs of "src",
+// or of the annotated ones if "types" is empty.
+func generate(filename string, src []byte, types []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(types))
+	for _, name := range types {
+		selected[strings.TrimSpace(name)] = true
+	}
+
+	var (
+		body     bytes.Buffer
+		found    int
+		needsFmt bool
+	)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			name := typeSpec.Name.Name
+			if len(selected) > 0 {
+				if !selected[name] {
+					continue
+				}
+				delete(selected, name)
+			} else if !isAnnotated(gen.Doc) && !isAnnotated(typeSpec.Doc) {
+				continue
+			}
+
+			if typeSpec.TypeParams != nil {
+				return nil, fmt.Errorf("%s: generic types are not supported", name)
+			}
+
+			fields, err := structFields(fset, structType)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			writeDecodeRow(&body, name, fields)
+			if writeEncodeRow(&body, name, fields) {
+				needsFmt = true
+			}
+			found++
+		}
+	}
+
+	for name := range selected {
+		return nil, fmt.Errorf("struct type %s not found in %s", name, filename)
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("no struct types to generate in %s, annotate them with %q or use the -type flag", filename, annotation)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sheetsgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", file.Name.Name)
+	if needsFmt {
+		buf.WriteString("\"fmt\"\n\n")
+	}
+	buf.WriteString("\"github.com/kataras/sheets\"\n)\n")
+	body.WriteTo(&buf)
+
+	return format.Source(buf.Bytes())
+}
+
+func isAnnotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == annotation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structFields returns the header fields of the struct, as the sheets package resolves them.
+func structFields(fset *token.FileSet, structType *ast.StructType) ([]field, error) {
+	var fields []field
+	for _, f := range structType.Fields.List {
+		var typ bytes.Buffer
+		if err := format.Node(&typ, fset, f.Type); err != nil {
+			return nil, err
+		}
+
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s)
+		}
+
+		names := make([]string, 0, len(f.Names))
+		for _, name := range f.Names {
+			names = append(names, name.Name)
+		}
+		if len(names) == 0 { // an embedded field is named after its type.
+			names = append(names, embeddedName(f.Type))
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+
+			header := tag.Get("sheets")
+			if header == "" {
+				header = name
+			} else if header == "-" {
+				continue
+			}
+
+			fields = append(fields, field{name: name, header: header, typ: typ.String()})
+		}
+	}
+
+	return fields, nil
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return ""
+	}
+}
+
+// cellFuncs are the sheets helpers which decode a cell value to a field type.
+var cellFuncs = map[string]string{
+	"string":    "CellString",
+	"int":       "CellInt",
+	"int8":      "CellInt",
+	"int16":     "CellInt",
+	"int32":     "CellInt",
+	"int64":     "CellInt",
+	"uint":      "CellFloat",
+	"uint8":     "CellFloat",
+	"uint16":    "CellFloat",
+	"uint32":    "CellFloat",
+	"uint64":    "CellFloat",
+	"float32":   "CellFloat",
+	"float64":   "CellFloat",
+	"bool":      "CellBool",
+	"time.Time": "CellTime",
+}
+
+func writeDecodeRow(buf *bytes.Buffer, typeName string, fields []field) {
+	fmt.Fprintf(buf, "\n// DecodeRow implements the `sheets.RowDecoder` interface.\n")
+	fmt.Fprintf(buf, "func (x *%s) DecodeRow(row []interface{}) error {\n", typeName)
+	if len(fields) == 0 {
+		buf.WriteString("return nil\n}\n")
+		return
+	}
+
+	buf.WriteString("for i, value := range row {\nswitch i {\n")
+	for i, f := range fields {
+		fmt.Fprintf(buf, "case %d: // %s\n", i, f.header)
+
+		elem, isPtr := strings.CutPrefix(f.typ, "*")
+		cellFunc, ok := cellFuncs[elem]
+		if !ok {
+			fmt.Fprintf(buf, "sheets.DecodeCell(&x.%s, value)\n", f.name)
+			continue
+		}
+
+		conversion := "v"
+		if elem != "string" && elem != "int64" && elem != "float64" && elem != "bool" && elem != "time.Time" {
+			conversion = elem + "(v)"
+		}
+
+		if isPtr {
+			fmt.Fprintf(buf, "if value == nil || value == \"\" {\nx.%s = nil\n} else ", f.name)
+			if conversion == "v" {
+				fmt.Fprintf(buf, "if v, ok := sheets.%s(value); ok {\nx.%s = &v\n}", cellFunc, f.name)
+			} else {
+				fmt.Fprintf(buf, "if v, ok := sheets.%s(value); ok {\nconverted := %s\nx.%s = &converted\n}", cellFunc, conversion, f.name)
+			}
+		} else {
+			fmt.Fprintf(buf, "if v, ok := sheets.%s(value); ok {\nx.%s = %s\n}", cellFunc, f.name, conversion)
+		}
+		fmt.Fprintf(buf, " else {\nsheets.DecodeCell(&x.%s, value)\n}\n", f.name)
+	}
+	buf.WriteString("}\n}\n\nreturn nil\n}\n")
+}
+
+// writeEncodeRow writes the EncodeRow method, it reports whether the method uses the fmt package.
+func writeEncodeRow(buf *bytes.Buffer, typeName string, fields []field) (needsFmt bool) {
+	fmt.Fprintf(buf, "\n// EncodeRow implements the `sheets.RowEncoder` interface.\n")
+	fmt.Fprintf(buf, "func (x %s) EncodeRow() ([]interface{}, error) {\n", typeName)
+	fmt.Fprintf(buf, "row := make([]interface{}, %d)\n", len(fields))
+
+	for i, f := range fields {
+		elem, isPtr := strings.CutPrefix(f.typ, "*")
+		_, basic := cellFuncs[elem]
+
+		switch {
+		case basic && elem == "time.Time" && !isPtr:
+			needsFmt = true
+			fmt.Fprintf(buf, "if text, err := x.%s.MarshalText(); err != nil {\nreturn nil, fmt.Errorf(\"field %s: %%w\", err)\n} else {\nrow[%d] = string(text)\n}\n", f.name, f.name, i)
+		case basic && elem != "time.Time" && isPtr:
+			fmt.Fprintf(buf, "if x.%s != nil {\nrow[%d] = x.%s\n}\n", f.name, i, f.name)
+		case basic && elem != "time.Time":
+			fmt.Fprintf(buf, "row[%d] = x.%s\n", i, f.name)
+		default:
+			needsFmt = true
+			fmt.Fprintf(buf, "if v, err := sheets.EncodeCell(&x.%s); err != nil {\nreturn nil, fmt.Errorf(\"field %s: %%w\", err)\n} else {\nrow[%d] = v\n}\n", f.name, f.name, i)
+		}
+	}
+
+	buf.WriteString("\nreturn row, nil\n}\n")
+	return
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := os.ReadFile("testdata/user.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := os.ReadFile("testdata/user_sheets.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, types := range [][]string{nil, {"User"}} {
+		got, err := generate("user.go", src, types)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(expected) != string(got) {
+			t.Fatalf("expected generated code:\n%s\nbut got:\n%s", expected, got)
+		}
+	}
+
+	got, err := generate("user.go", src, []string{"NotGenerated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(got); strings.Contains(s, "func (x User)") || strings.Contains(s, `"fmt"`) || !strings.Contains(s, "func (x *NotGenerated) DecodeRow") {
+		t.Fatalf("expected the methods of the NotGenerated type only but got:\n%s", s)
+	}
+
+	if _, err = generate("user.go", src, []string{"Missing"}); err == nil {
+		t.Fatal("expected an error for a missing type")
+	}
+}
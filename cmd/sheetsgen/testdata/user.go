@@ -0,0 +1,23 @@
+package testdata
+
+import (
+	"math/big"
+	"time"
+)
+
+//sheets:generate
+type User struct {
+	Name     string
+	Email    string `sheets:"E-mail"`
+	Age      int
+	Score    *float64
+	Active   bool
+	Joined   time.Time
+	Balance  *big.Int
+	internal string
+	Ignored  string `sheets:"-"`
+}
+
+type NotGenerated struct {
+	Name string
+}
@@ -0,0 +1,69 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// flightGroup collapses concurrent calls of the same key into a single one.
+// Its zero value is ready to use.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done   chan struct{}
+	result []ValueRange
+	err    error
+}
+
+// do executes and returns the results of "fn", making sure that only one execution
+// is in-flight for a given "key" at a time. Duplicate callers wait for the original one
+// to complete and receive the same results.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]ValueRange, error)) ([]ValueRange, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		if isContextError(call.err) && ctx.Err() == nil {
+			// The original caller gave up, this one is still interested.
+			return fn(ctx)
+		}
+
+		return call.result, call.err
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.result, call.err = fn(ctx)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+
+	return call.result, call.err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func coalesceKey(spreadsheetID string, dataRanges []string) string {
+	return spreadsheetID + "\x00" + strings.Join(dataRanges, "\x00")
+}
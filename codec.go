@@ -0,0 +1,32 @@
+package sheets
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONCodec is the JSON encoder and decoder used by the Client
+// to write request and read response bodies.
+// Performance-sensitive users can use a faster implementation
+// (e.g. json-iterator or sonic) for large value payloads.
+//
+// See `Client.JSON` field and `DefaultJSONCodec` package-level variable.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONDecoder reads and decodes JSON values from an input stream.
+// The standard `json.Decoder` implements it.
+type JSONDecoder interface {
+	Decode(v interface{}) error
+}
+
+// DefaultJSONCodec is the default `JSONCodec`, it uses the standard encoding/json package.
+var DefaultJSONCodec JSONCodec = stdJSONCodec{}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (stdJSONCodec) NewDecoder(r io.Reader) JSONDecoder { return json.NewDecoder(r) }
@@ -0,0 +1,157 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const spreadsheetSheetCopyToURL = spreadsheetURL + "/sheets/%d:copyTo"
+
+// CopyRange copies the cells of "srcRange" of the spreadsheet of "srcID", e.g. "Team A!A2:F",
+// to the spreadsheet of "dstID" starting at the "dstStart" cell, e.g. "Master!A10",
+// so per-team sheets can be consolidated into a master spreadsheet.
+//
+// If "includeFormatting" is false then only the values are copied, as they are stored:
+// numbers stay numbers, dates and times are copied as their serial numbers
+// and formulas are replaced by their results, so formulas over the destination (e.g. SUM) keep working.
+// Otherwise the source sheet is copied to the destination spreadsheet,
+// its range is pasted (values, formulas and formats) to the destination and the copied sheet is deleted.
+// A range without a sheet title refers to the first sheet of its spreadsheet.
+func (c *Client) CopyRange(ctx context.Context, srcID, srcRange, dstID, dstStart string, includeFormatting bool) error {
+	if !includeFormatting {
+		if err := ValidateRange(srcRange); err != nil {
+			return c.opError(err, "values.get", srcID, srcRange, 0)
+		}
+
+		// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/get
+		url := c.apiURL(spreadsheetValuesURL, srcID, srcRange)
+		q := Query{
+			"valueRenderOption":    []string{"UNFORMATTED_VALUE"},
+			"dateTimeRenderOption": []string{"SERIAL_NUMBER"},
+		}
+
+		var values ValueRange
+		if err := c.ReadJSON(ctx, http.MethodGet, url, nil, &values, q); err != nil {
+			return c.opError(err, "values.get", srcID, srcRange, 0)
+		}
+
+		if len(values.Values) == 0 {
+			return nil
+		}
+
+		_, err := c.UpdateSpreadsheet(ctx, dstID, ValueRange{
+			Range:          dstStart,
+			MajorDimension: Rows,
+			Values:         values.Values,
+		})
+		return err
+	}
+
+	srcTitle, source, err := ParseA1(srcRange)
+	if err != nil {
+		return c.opError(err, "sheets.copyTo", srcID, srcRange, 0)
+	}
+
+	dstTitle, destination, err := ParseA1(dstStart)
+	if err != nil {
+		return c.opError(err, "spreadsheets.batchUpdate/copyPaste", dstID, dstStart, 0)
+	}
+
+	srcSheetID, err := c.sheetID(ctx, srcID, srcTitle)
+	if err != nil {
+		return err
+	}
+
+	dstSheetID, err := c.sheetID(ctx, dstID, dstTitle)
+	if err != nil {
+		return err
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.sheets/copyTo
+	var copied SheetProperties
+	url := c.apiURL(spreadsheetSheetCopyToURL, srcID, srcSheetID)
+	err = c.ReadJSON(ctx, http.MethodPost, url, map[string]string{"destinationSpreadsheetId": dstID}, &copied)
+	if err != nil {
+		return c.opError(err, "sheets.copyTo", srcID, srcRange, 0)
+	}
+
+	copiedID := copied.ID
+
+	// Bound the source range to the grid of the copied sheet
+	// so the destination range has the same size, otherwise the source is repeated to fill it.
+	source.SheetID = copiedID
+	if source.EndRowIndex == 0 {
+		source.EndRowIndex = int64(copied.Grid.RowCount)
+	}
+	if source.EndColumnIndex == 0 {
+		source.EndColumnIndex = int64(copied.Grid.ColumnCount)
+	}
+
+	destination.SheetID = dstSheetID
+	destination.EndRowIndex = destination.StartRowIndex + source.EndRowIndex - source.StartRowIndex
+	destination.EndColumnIndex = destination.StartColumnIndex + source.EndColumnIndex - source.StartColumnIndex
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#copypasterequest
+	url = c.apiURL(spreadsheetBatchUpdateURL, dstID)
+	deleteSheet := map[string]interface{}{"deleteSheet": map[string]int64{"sheetId": copiedID}}
+
+	defer c.InvalidateSpreadsheetInfo(dstID) // the copied sheet is added and deleted.
+
+	var response BatchUpdateResponse
+	err = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{
+		"requests": []interface{}{
+			map[string]interface{}{
+				"copyPaste": map[string]interface{}{
+					"source":      source,
+					"destination": destination,
+					"pasteType":   "PASTE_NORMAL",
+				},
+			},
+			deleteSheet,
+		},
+	}, &response)
+	if err != nil {
+		// The batch update is atomic, so the copied sheet is still there.
+		_ = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{"requests": []interface{}{deleteSheet}}, &response)
+		return c.opError(err, "spreadsheets.batchUpdate/copyPaste", dstID, dstStart, 0)
+	}
+
+	return nil
+}
+
+// sheetID returns the numeric ID of the sheet of "sheetTitle" of the spreadsheet,
+// or the ID of its first sheet if "sheetTitle" is empty.
+// The cached spreadsheet metadata are used, they are fetched again if the sheet is missing from them.
+func (c *Client) sheetID(ctx context.Context, spreadsheetID, sheetTitle string) (int64, error) {
+	sd, err := c.SpreadsheetInfoCached(ctx, spreadsheetID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ok := sd.GetSheet(sheetTitle); !ok && sheetTitle != "" {
+		c.InvalidateSpreadsheetInfo(spreadsheetID) // the sheet may be added after the metadata were cached.
+		if sd, err = c.SpreadsheetInfoCached(ctx, spreadsheetID); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		sheet Sheet
+		ok    bool
+	)
+	if sheetTitle == "" {
+		ok = len(sd.Sheets) > 0
+		if ok {
+			sheet = sd.Sheets[0]
+		}
+	} else {
+		sheet, ok = sd.GetSheet(sheetTitle)
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("sheets: sheet %q not found in spreadsheet %q: %w", sheetTitle, spreadsheetID, ErrNotFound)
+	}
+
+	return sheet.Properties.ID, nil
+}
@@ -0,0 +1,65 @@
+package sheets
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientCopyRange(t *testing.T) {
+	var batchUpdate, valuesUpdate string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4/spreadsheets/src":
+			w.Write([]byte(`{"spreadsheetId":"src","sheets":[{"properties":{"sheetId":0,"title":"Sheet1"}},{"properties":{"sheetId":3,"title":"Team A"}}]}`))
+		case "/v4/spreadsheets/dst":
+			w.Write([]byte(`{"spreadsheetId":"dst","sheets":[{"properties":{"sheetId":5,"title":"Master"}}]}`))
+		case "/v4/spreadsheets/src/sheets/3:copyTo":
+			body, _ := io.ReadAll(r.Body)
+			if expected, got := `{"destinationSpreadsheetId":"dst"}`, string(body); expected != got {
+				t.Errorf("expected copyTo body %s but got %s", expected, got)
+			}
+			w.Write([]byte(`{"sheetId":11,"title":"Copy of Team A","gridProperties":{"rowCount":100,"columnCount":6}}`))
+		case "/v4/spreadsheets/dst:batchUpdate":
+			body, _ := io.ReadAll(r.Body)
+			batchUpdate = string(body)
+			w.Write([]byte(`{"spreadsheetId":"dst"}`))
+		case "/v4/spreadsheets/src/values/'Team A'!A2:F3":
+			if q := r.URL.Query(); q.Get("valueRenderOption") != "UNFORMATTED_VALUE" || q.Get("dateTimeRenderOption") != "SERIAL_NUMBER" {
+				t.Errorf("expected the values to be read unformatted but got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"range":"'Team A'!A2:F3","majorDimension":"ROWS","values":[["a",1234.5,45293],["b",2]]}`))
+		case "/v4/spreadsheets/dst/values/Master!B10":
+			body, _ := io.ReadAll(r.Body)
+			valuesUpdate = string(body)
+			w.Write([]byte(`{"spreadsheetId":"dst"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	if err := c.CopyRange(ctx, "src", "'Team A'!A2:F", "dst", "Master!B10", true); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"requests":[{"copyPaste":{"destination":{"endColumnIndex":7,"endRowIndex":108,"sheetId":5,"startColumnIndex":1,"startRowIndex":9},` +
+		`"pasteType":"PASTE_NORMAL","source":{"endColumnIndex":6,"endRowIndex":100,"sheetId":11,"startRowIndex":1}}},{"deleteSheet":{"sheetId":11}}]}`
+	if strings.TrimSpace(batchUpdate) != expected {
+		t.Fatalf("expected batch update:\n%s\nbut got:\n%s", expected, batchUpdate)
+	}
+
+	if err := c.CopyRange(ctx, "src", "'Team A'!A2:F3", "dst", "Master!B10", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(valuesUpdate, `"values":[["a",1234.5,45293],["b",2]]`) {
+		t.Fatalf("expected the values to be written but got %s", valuesUpdate)
+	}
+}
@@ -0,0 +1,297 @@
+package sheets
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the values of the range to "w" in CSV format, one record per row,
+// quoting the fields as necessary. Short rows are padded with empty fields
+// so all records have the same number of fields.
+func (r ValueRange) WriteCSV(w io.Writer) error {
+	columns := 0
+	for _, row := range r.Values {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	record := make([]string, columns)
+	for _, row := range r.Values {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = formatCell(row[i])
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCell returns the text of a cell value as it's read from the API.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ExportCSV writes all the values of the sheet with "sheetTitle" to "w" in CSV format.
+// See `ValueRange.WriteCSV` method.
+func (c *Client) ExportCSV(ctx context.Context, spreadsheetID, sheetTitle string, w io.Writer) error {
+	valueRanges, err := c.Range(ctx, spreadsheetID, QuoteSheetTitle(sheetTitle))
+	if err != nil {
+		return err
+	}
+
+	for _, valueRange := range valueRanges {
+		if err = valueRange.WriteCSV(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SheetReader reads the rows of a spreadsheet range as records of strings,
+// like the standard `csv.Reader`, so existing CSV-based code can read from a sheet with minimal changes.
+// The range values are fetched on the first read.
+//
+// See `NewSheetReader` package-level function.
+type SheetReader struct {
+	ctx           context.Context
+	client        *Client
+	spreadsheetID string
+	dataRange     string
+
+	rows    [][]interface{}
+	fetched bool
+	err     error
+}
+
+// NewSheetReader returns a new `SheetReader` which reads the "dataRange" of the spreadsheet.
+func NewSheetReader(ctx context.Context, client *Client, spreadsheetID, dataRange string) *SheetReader {
+	return &SheetReader{
+		ctx:           ctx,
+		client:        client,
+		spreadsheetID: spreadsheetID,
+		dataRange:     dataRange,
+	}
+}
+
+// Read reads one record (a row) from the sheet. It returns `io.EOF` when there are no more rows.
+// Unlike the `csv.Reader`, records may have a different number of fields,
+// empty trailing cells are not returned by the API.
+func (r *SheetReader) Read() ([]string, error) {
+	if !r.fetched {
+		r.fetched = true
+
+		valueRanges, err := r.client.Range(r.ctx, r.spreadsheetID, r.dataRange)
+		if err != nil {
+			r.err = err
+		} else if len(valueRanges) > 0 {
+			r.rows = valueRanges[0].Values
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if len(r.rows) == 0 {
+		return nil, io.EOF
+	}
+
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = formatCell(value)
+	}
+
+	return record, nil
+}
+
+// ReadAll reads all the remaining records from the sheet.
+func (r *SheetReader) ReadAll() ([][]string, error) {
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+}
+
+// DefaultSheetWriterBatchSize is the default number of records a `SheetWriter` buffers before it appends them to the sheet.
+const DefaultSheetWriterBatchSize = 500
+
+// SheetWriter writes records of strings to a sheet, like the standard `csv.Writer`.
+// Records are buffered and appended after the table of the range in batches of `BatchSize` records.
+// Values are written as they are (RAW), e.g. "42" is stored as text.
+//
+// See `NewSheetWriter` package-level function.
+type SheetWriter struct {
+	// BatchSize is the number of records buffered before they are appended to the sheet.
+	// Defaults to `DefaultSheetWriterBatchSize`.
+	BatchSize int
+
+	ctx           context.Context
+	client        *Client
+	spreadsheetID string
+	dataRange     string
+
+	buf [][]interface{}
+	err error
+}
+
+// NewSheetWriter returns a new `SheetWriter` which appends records after the table of the "dataRange" of the spreadsheet,
+// e.g. "Sheet1!A1".
+func NewSheetWriter(ctx context.Context, client *Client, spreadsheetID, dataRange string) *SheetWriter {
+	return &SheetWriter{
+		BatchSize:     DefaultSheetWriterBatchSize,
+		ctx:           ctx,
+		client:        client,
+		spreadsheetID: spreadsheetID,
+		dataRange:     dataRange,
+	}
+}
+
+// Write buffers a single record. If the buffer is full, the buffered records are appended to the sheet.
+// It returns the error of a previous append, if any.
+func (w *SheetWriter) Write(record []string) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	row := make([]interface{}, len(record))
+	for i, field := range record {
+		row[i] = field
+	}
+	w.buf = append(w.buf, row)
+
+	if w.BatchSize <= 0 || len(w.buf) >= w.BatchSize {
+		w.Flush()
+	}
+
+	return w.err
+}
+
+// WriteAll writes multiple records and then calls `Flush`, returning any error from the flush.
+func (w *SheetWriter) WriteAll(records [][]string) error {
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.err
+}
+
+// Flush appends any buffered records to the sheet.
+// To check if an error occurred during the Flush, call `Error`.
+func (w *SheetWriter) Flush() {
+	if w.err != nil || len(w.buf) == 0 {
+		return
+	}
+
+	_, w.err = w.client.AppendSpreadsheet(w.ctx, w.spreadsheetID, ValueRange{
+		Range:          w.dataRange,
+		MajorDimension: Rows,
+		Values:         w.buf,
+	})
+	w.buf = nil
+}
+
+// Error reports any error that has occurred during a previous `Write` or `Flush`.
+func (w *SheetWriter) Error() error {
+	return w.err
+}
+
+// AppendFromCSV reads CSV records from "r" and appends them after the table of the sheet with "sheetTitle"
+// in successive append requests of "batchRows" rows (defaults to `DefaultSheetWriterBatchSize`).
+// The input is streamed, only one batch is kept in memory, and the next batch is read
+// after the previous one is appended, so arbitrarily large imports do not require loading the file in memory.
+// The optional "onProgress" is called after each batch with the total number of rows appended so far.
+// It returns the number of rows appended, which is valid even on error, e.g. to resume an import.
+func (c *Client) AppendFromCSV(ctx context.Context, spreadsheetID, sheetTitle string, r io.Reader, batchRows int, onProgress func(rows int)) (int, error) {
+	if batchRows <= 0 {
+		batchRows = DefaultSheetWriterBatchSize
+	}
+
+	var (
+		cr    = csv.NewReader(r)
+		batch = make([][]interface{}, 0, batchRows)
+		total int
+	)
+	cr.FieldsPerRecord = -1
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		_, err := c.AppendSpreadsheet(ctx, spreadsheetID, ValueRange{
+			Range:          QuoteSheetTitle(sheetTitle) + "!A1",
+			MajorDimension: Rows,
+			Values:         batch,
+		})
+		if err != nil {
+			return err
+		}
+
+		total += len(batch)
+		batch = make([][]interface{}, 0, batchRows)
+		if onProgress != nil {
+			onProgress(total)
+		}
+
+		return nil
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return total, flush()
+		}
+		if err != nil {
+			return total, err
+		}
+
+		row := make([]interface{}, len(record))
+		for i, field := range record {
+			row[i] = field
+		}
+
+		if batch = append(batch, row); len(batch) >= batchRows {
+			if err = flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package sheets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValueRangeWriteCSV(t *testing.T) {
+	valueRange := ValueRange{
+		Values: [][]interface{}{
+			{"Name", "Age", "Note"},
+			{"makis", float64(27), "says \"hi\", often"},
+			{"giwrgos", 30.5, true},
+			{"efi"},
+		},
+	}
+
+	var b strings.Builder
+	if err := valueRange.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Name,Age,Note\nmakis,27,\"says \"\"hi\"\", often\"\ngiwrgos,30.5,true\nefi,,\n"
+	if got := b.String(); expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
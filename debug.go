@@ -0,0 +1,155 @@
+package sheets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+// redactedHeaders is a list of the headers that their values
+// are hidden from the debug output.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Goog-Api-Key"}
+
+// redactedQuery is a list of the URL query parameters that their values
+// are hidden from the debug output.
+var redactedQuery = []string{"key", "access_token"}
+
+const redacted = "REDACTED"
+
+// Debug enables dumping of all HTTP requests and responses to "w",
+// authentication headers and keys are redacted.
+// Useful to diagnose range and field errors without a proxy.
+// Pass a nil writer to disable it.
+func (c *Client) Debug(w io.Writer) {
+	var base http.RoundTripper
+	if c.HTTPClient != nil {
+		base = c.HTTPClient.Transport
+	}
+
+	if t, ok := base.(*debugTransport); ok {
+		base = t.base
+	}
+
+	// Do not modify a possible shared http client.
+	hc := new(http.Client)
+	if c.HTTPClient != nil {
+		*hc = *c.HTTPClient
+	}
+
+	if w == nil {
+		hc.Transport = base
+	} else {
+		hc.Transport = &debugTransport{base: base, w: w}
+	}
+
+	c.HTTPClient = hc
+}
+
+type debugTransport struct {
+	base http.RoundTripper
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *debugTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var reqBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = b
+		r.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	t.dump(dumpRequest(r, reqBody))
+
+	resp, err := base.RoundTrip(r)
+	if err != nil {
+		t.dump([]byte(fmt.Sprintf("<<< %s %s: %v\n\n", r.Method, r.URL.Redacted(), err)))
+		return nil, err
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	t.dump(dumpResponse(resp, b))
+	return resp, nil
+}
+
+func (t *debugTransport) dump(b []byte) {
+	t.mu.Lock()
+	t.w.Write(b)
+	t.mu.Unlock()
+}
+
+func dumpRequest(r *http.Request, body []byte) []byte {
+	r = r.Clone(r.Context())
+	redactHeader(r.Header)
+
+	query := r.URL.Query()
+	for _, key := range redactedQuery {
+		if query.Has(key) {
+			query.Set(key, redacted)
+		}
+	}
+	r.URL.RawQuery = query.Encode()
+
+	b, err := httputil.DumpRequestOut(r, false)
+	if err != nil {
+		return []byte(fmt.Sprintf(">>> %s %s: %v\n\n", r.Method, r.URL.Redacted(), err))
+	}
+
+	return append(append([]byte(">>>\n"), b...), dumpBody(body, r.Header.Get("Content-Encoding"))...)
+}
+
+func dumpResponse(resp *http.Response, body []byte) []byte {
+	c := *resp
+	c.Header = resp.Header.Clone()
+	redactHeader(c.Header)
+
+	b, err := httputil.DumpResponse(&c, false)
+	if err != nil {
+		return []byte(fmt.Sprintf("<<< %s: %v\n\n", resp.Status, err))
+	}
+
+	return append(append([]byte("<<<\n"), b...), dumpBody(body, c.Header.Get("Content-Encoding"))...)
+}
+
+func dumpBody(body []byte, encoding string) []byte {
+	if encoding == "gzip" && len(body) > 0 {
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err == nil {
+			if b, err := io.ReadAll(gr); err == nil {
+				body = b
+			}
+			gr.Close()
+		}
+	}
+
+	return append(body, '\n', '\n')
+}
+
+func redactHeader(h http.Header) {
+	for _, key := range redactedHeaders {
+		if _, ok := h[http.CanonicalHeaderKey(key)]; ok {
+			h.Set(key, redacted)
+		}
+	}
+}
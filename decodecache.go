@@ -0,0 +1,80 @@
+package sheets
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DecodeCache is a cache of the struct types metadata (headers and custom decoders)
+// used to decode value ranges, so the reflection work is done once per type.
+// A DecodeCache is safe for concurrent use.
+//
+// See `Decoder.Cache` field, `NewDecodeCache` and `ClearDecodeCache` package-level functions.
+type DecodeCache struct {
+	maxSize int
+
+	mu      sync.RWMutex
+	entries map[reflect.Type]*metadata
+	order   []reflect.Type // insertion order, used to evict the oldest entries.
+}
+
+// defaultDecodeCache is the package-level cache used by `DecodeValueRange`.
+var defaultDecodeCache = NewDecodeCache(0)
+
+// NewDecodeCache returns a new decode cache, isolated from the package-level one,
+// e.g. for a Client (see `Client.Decoder` field) or a plugin which loads and unloads types.
+// A positive "maxSize" bounds the number of cached types, the oldest entries are evicted first.
+func NewDecodeCache(maxSize int) *DecodeCache {
+	return &DecodeCache{
+		maxSize: maxSize,
+		entries: make(map[reflect.Type]*metadata),
+	}
+}
+
+// ClearDecodeCache drops all the entries of the package-level decode cache.
+func ClearDecodeCache() {
+	defaultDecodeCache.Clear()
+}
+
+// Clear drops all the cached entries.
+func (c *DecodeCache) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[reflect.Type]*metadata)
+	c.order = nil
+	c.mu.Unlock()
+}
+
+// Len returns the number of cached types.
+func (c *DecodeCache) Len() int {
+	c.mu.RLock()
+	n := len(c.entries)
+	c.mu.RUnlock()
+	return n
+}
+
+// metadata returns the metadata of the struct "typ", it is computed and cached if necessary.
+func (c *DecodeCache) metadata(typ reflect.Type) *metadata {
+	c.mu.RLock()
+	meta, ok := c.entries[typ]
+	c.mu.RUnlock()
+	if ok {
+		return meta
+	}
+
+	meta = newMetadata(typ)
+
+	c.mu.Lock()
+	if _, ok = c.entries[typ]; !ok {
+		if c.maxSize > 0 && len(c.order) >= c.maxSize {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+
+		c.order = append(c.order, typ)
+	}
+	c.entries[typ] = meta
+	c.mu.Unlock()
+
+	return meta
+}
@@ -0,0 +1,39 @@
+package sheets
+
+import "testing"
+
+func TestDecodeCache(t *testing.T) {
+	cache := NewDecodeCache(1)
+	d := &Decoder{Cache: cache}
+
+	var rows []testRow
+	if err := d.Decode(&rows, ValueRange{Values: [][]interface{}{{"makis"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 1, cache.Len(); expected != got {
+		t.Fatalf("expected %d cached types but got %d", expected, got)
+	}
+
+	var pointers []testRowPointers
+	if err := d.Decode(&pointers, ValueRange{Values: [][]interface{}{{"makis"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 1, cache.Len(); expected != got {
+		t.Fatalf("expected %d cached types after eviction but got %d", expected, got)
+	}
+
+	cache.Clear()
+	if expected, got := 0, cache.Len(); expected != got {
+		t.Fatalf("expected %d cached types after clear but got %d", expected, got)
+	}
+
+	DecodeValueRange(&rows, ValueRange{Values: [][]interface{}{{"makis"}}})
+	if defaultDecodeCache.Len() == 0 {
+		t.Fatal("expected the package-level cache to be used")
+	}
+
+	ClearDecodeCache()
+	if expected, got := 0, defaultDecodeCache.Len(); expected != got {
+		t.Fatalf("expected %d cached types after clear but got %d", expected, got)
+	}
+}
@@ -0,0 +1,209 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChangeType is the type of a `CellChange`.
+type ChangeType string
+
+const (
+	// CellAdded is the type of a change of an empty cell to a non-empty one.
+	CellAdded ChangeType = "added"
+	// CellRemoved is the type of a change of a non-empty cell to an empty one.
+	CellRemoved ChangeType = "removed"
+	// CellChanged is the type of a change of a non-empty cell to a different value.
+	CellChanged ChangeType = "changed"
+)
+
+// CellChange is a change of a cell between two value ranges, see `Diff` package-level function.
+type CellChange struct {
+	// Sheet is the sheet title of the cell, empty if the ranges do not include one.
+	Sheet string
+	// Cell is the address of the cell in the sheet.
+	Cell Cell
+	Type ChangeType
+	// Old and New are the cell values before and after the change, nil for an empty cell.
+	Old, New interface{}
+}
+
+// A1 returns the A1 notation of the changed cell, e.g. "Sheet1!B2".
+func (c CellChange) A1() string {
+	if c.Sheet == "" {
+		return c.Cell.A1()
+	}
+
+	return QuoteSheetTitle(c.Sheet) + "!" + c.Cell.A1()
+}
+
+// String returns a human-readable text of the change, e.g. `Sheet1!B2: changed "a" -> "b"`.
+func (c CellChange) String() string {
+	switch c.Type {
+	case CellAdded:
+		return fmt.Sprintf("%s: added %q", c.A1(), formatCell(c.New))
+	case CellRemoved:
+		return fmt.Sprintf("%s: removed %q", c.A1(), formatCell(c.Old))
+	default:
+		return fmt.Sprintf("%s: changed %q -> %q", c.A1(), formatCell(c.Old), formatCell(c.New))
+	}
+}
+
+// Diff reports the added, removed and changed cells from "a" to "b", in row-major order,
+// e.g. to review what an automated job is about to change before it's written.
+// Both ranges should start at the same cell, the cell coordinates are computed from the start of "a"'s range
+// (or "b"'s if "a" has no range), e.g. "Sheet1!B2:D". Cells are compared by their text and empty strings are empty cells.
+func Diff(a, b ValueRange) []CellChange {
+	dataRange := a.Range
+	if dataRange == "" {
+		dataRange = b.Range
+	}
+
+	var (
+		sheetTitle string
+		origin     GridRange
+	)
+	if dataRange != "" {
+		sheetTitle, origin, _ = ParseA1(dataRange)
+	}
+
+	rows := len(a.Values)
+	if len(b.Values) > rows {
+		rows = len(b.Values)
+	}
+
+	var changes []CellChange
+	for i := 0; i < rows; i++ {
+		var rowA, rowB []interface{}
+		if i < len(a.Values) {
+			rowA = a.Values[i]
+		}
+		if i < len(b.Values) {
+			rowB = b.Values[i]
+		}
+
+		columns := len(rowA)
+		if len(rowB) > columns {
+			columns = len(rowB)
+		}
+
+		for j := 0; j < columns; j++ {
+			old, new := cellAt(rowA, j), cellAt(rowB, j)
+			oldText, newText := formatCell(old), formatCell(new)
+			if oldText == newText {
+				continue
+			}
+
+			change := CellChange{
+				Sheet: sheetTitle,
+				Cell:  Cell{Row: int(origin.StartRowIndex) + i, Col: int(origin.StartColumnIndex) + j},
+				Old:   old,
+				New:   new,
+			}
+
+			switch {
+			case oldText == "":
+				change.Type, change.Old = CellAdded, nil
+			case newText == "":
+				change.Type, change.New = CellRemoved, nil
+			default:
+				change.Type = CellChanged
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// DiffSpreadsheets reports the cell changes from the spreadsheet of "aID" to the spreadsheet of "bID"
+// on the "dataRanges", e.g. a copy which an automated job has modified.
+// If no "dataRanges" are provided then all the sheets of both spreadsheets are compared by their titles.
+// See `Diff` package-level function.
+func (c *Client) DiffSpreadsheets(ctx context.Context, aID, bID string, dataRanges ...string) ([]CellChange, error) {
+	if len(dataRanges) > 0 {
+		a, err := c.Range(ctx, aID, dataRanges...)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := c.Range(ctx, bID, dataRanges...)
+		if err != nil {
+			return nil, err
+		}
+
+		var changes []CellChange
+		for i := range a {
+			if i < len(b) {
+				b[i].Range = a[i].Range
+				changes = append(changes, Diff(a[i], b[i])...)
+			}
+		}
+
+		return changes, nil
+	}
+
+	a, titles, err := c.sheetValues(ctx, aID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	b, titles, err := c.sheetValues(ctx, bID, titles)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []CellChange
+	for _, title := range titles {
+		// Both ranges start at "A1" of the sheet.
+		valueRangeA, valueRangeB := a[title], b[title]
+		valueRangeA.Range = QuoteSheetTitle(title)
+		changes = append(changes, Diff(valueRangeA, valueRangeB)...)
+	}
+
+	return changes, nil
+}
+
+// sheetValues reads all the values of all the sheets of the spreadsheet, by sheet title.
+// The sheet titles are appended to the "titles", if missing, in order.
+func (c *Client) sheetValues(ctx context.Context, spreadsheetID string, titles []string) (map[string]ValueRange, []string, error) {
+	sd, err := c.GetSpreadsheetInfo(ctx, spreadsheetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataRanges := make([]string, len(sd.Sheets))
+	for i, sheet := range sd.Sheets {
+		dataRanges[i] = sheet.RangeAll()
+
+		found := false
+		for _, title := range titles {
+			if title == sheet.Properties.Title {
+				found = true
+				break
+			}
+		}
+		if !found {
+			titles = append(titles, sheet.Properties.Title)
+		}
+	}
+
+	values := make(map[string]ValueRange, len(sd.Sheets))
+	if len(dataRanges) == 0 {
+		return values, titles, nil
+	}
+
+	valueRanges, err := c.Range(ctx, spreadsheetID, dataRanges...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, valueRange := range valueRanges {
+		if i < len(sd.Sheets) {
+			values[sd.Sheets[i].Properties.Title] = valueRange
+		}
+	}
+
+	return values, titles, nil
+}
@@ -0,0 +1,44 @@
+package sheets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := ValueRange{
+		Range: "Sheet1!B2:D4",
+		Values: [][]interface{}{
+			{"Name", "Age"},
+			{"kataras", 27.0, "x"},
+			{"makis", "30"},
+		},
+	}
+	b := ValueRange{
+		Values: [][]interface{}{
+			{"Name", "Age"},
+			{"kataras", "27", ""},
+			{"makis", "31", "new"},
+			{"", ""},
+		},
+	}
+
+	expected := []CellChange{
+		{Sheet: "Sheet1", Cell: Cell{Row: 2, Col: 3}, Type: CellRemoved, Old: "x"},
+		{Sheet: "Sheet1", Cell: Cell{Row: 3, Col: 2}, Type: CellChanged, Old: "30", New: "31"},
+		{Sheet: "Sheet1", Cell: Cell{Row: 3, Col: 3}, Type: CellAdded, New: "new"},
+	}
+
+	got := Diff(a, b)
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected changes:\n%#v\nbut got:\n%#v", expected, got)
+	}
+
+	if expected, got := `Sheet1!C4: changed "30" -> "31"`, got[1].String(); expected != got {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	if changes := Diff(a, a); len(changes) != 0 {
+		t.Fatalf("expected no changes but got %v", changes)
+	}
+}
@@ -0,0 +1,354 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Drive export MIME types, see `Client.Export` method.
+const (
+	// MimeTypeXLSX is the MIME type of a Microsoft Excel (.xlsx) file.
+	MimeTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	// MimeTypeODS is the MIME type of an OpenDocument spreadsheet (.ods) file.
+	MimeTypeODS = "application/x-vnd.oasis.opendocument.spreadsheet"
+	// MimeTypePDF is the MIME type of a PDF file.
+	MimeTypePDF = "application/pdf"
+	// MimeTypeCSV is the MIME type of a CSV file, only the first sheet is exported.
+	MimeTypeCSV = "text/csv"
+)
+
+const (
+	driveFilesURL       = "/drive/v3/files"
+	driveFileURL        = driveFilesURL + "/%s"
+	driveExportURL      = driveFileURL + "/export"
+	driveCopyURL        = driveFileURL + "/copy"
+	drivePermissionsURL = driveFileURL + "/permissions"
+)
+
+// MimeTypeSpreadsheet is the Drive MIME type of a Google Sheets spreadsheet.
+const MimeTypeSpreadsheet = "application/vnd.google-apps.spreadsheet"
+
+// DriveFile holds the Drive metadata of a spreadsheet (or any other Drive file).
+type DriveFile struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	MimeType     string    `json:"mimeType"`
+	Parents      []string  `json:"parents,omitempty"`
+	ModifiedTime time.Time `json:"modifiedTime"`
+	WebViewLink  string    `json:"webViewLink,omitempty"`
+	Trashed      bool      `json:"trashed,omitempty"`
+}
+
+// driveFileRequest is the request body of the Drive files create, copy and update requests.
+type driveFileRequest struct {
+	Name     string   `json:"name,omitempty"`
+	MimeType string   `json:"mimeType,omitempty"`
+	Parents  []string `json:"parents,omitempty"`
+	Trashed  bool     `json:"trashed,omitempty"`
+}
+
+// driveFileFields are the fields of a `DriveFile`, requested through the "fields" query parameter.
+const driveFileFields = "id,name,mimeType,parents,modifiedTime,webViewLink,trashed"
+
+// driveQuery returns the query of a Drive files request, it enables shared drives support.
+func driveQuery(fields string) Query {
+	return Query{
+		"fields":            []string{fields},
+		"supportsAllDrives": []string{"true"},
+	}
+}
+
+// driveURL returns the full URL of a Drive API path of the Client's universe domain.
+func (c *Client) driveURL(format string, args ...interface{}) string {
+	return "https://www." + c.universeDomain() + fmt.Sprintf(format, args...)
+}
+
+// accept is a `RequestOption` which overrides the default "application/json" Accept header,
+// used by the requests which download files.
+type accept string
+
+func (a accept) Apply(r *http.Request) {
+	r.Header.Set("Accept", string(a))
+}
+
+// Export writes the spreadsheet, converted to the "mimeType" (e.g. `MimeTypeXLSX`), to "w"
+// through the Drive files.export endpoint. The Client must be authorized with a Drive scope,
+// e.g. `ScopeDriveReadOnly` or `ScopeDriveFile`. Exported files are limited to 10MB by the API.
+func (c *Client) Export(ctx context.Context, spreadsheetID, mimeType string, w io.Writer) error {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/export
+	url := c.driveURL(driveExportURL, spreadsheetID)
+	err := c.download(ctx, url, w, Query{"mimeType": []string{mimeType}})
+	return c.opError(err, "files.export", spreadsheetID, "", 0)
+}
+
+// ExportXLSX writes the spreadsheet to "w" as a Microsoft Excel (.xlsx) file,
+// e.g. to snapshot spreadsheets for backups. See `Client.Export` method.
+func (c *Client) ExportXLSX(ctx context.Context, spreadsheetID string, w io.Writer) error {
+	return c.Export(ctx, spreadsheetID, MimeTypeXLSX, w)
+}
+
+// CreateSpreadsheetIn creates a new, empty, spreadsheet with "title" inside the Drive folder of "folderID".
+// An empty "folderID" creates it in the root folder ("My Drive").
+// The Client must be authorized with a Drive scope, e.g. `ScopeDriveFile`.
+func (c *Client) CreateSpreadsheetIn(ctx context.Context, folderID, title string) (*DriveFile, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/create
+	url := c.driveURL(driveFilesURL)
+
+	file := driveFileRequest{Name: title, MimeType: MimeTypeSpreadsheet}
+	if folderID != "" {
+		file.Parents = []string{folderID}
+	}
+
+	response := new(DriveFile)
+	err := c.ReadJSON(ctx, http.MethodPost, url, file, response, driveQuery(driveFileFields))
+	if err != nil {
+		return nil, c.opError(err, "files.create", "", "", 0)
+	}
+
+	return response, nil
+}
+
+// CopySpreadsheet copies the spreadsheet of "spreadsheetID" to a new one with "title"
+// inside the Drive folder of "folderID". An empty "folderID" keeps the parent folder of the original.
+// It returns the new spreadsheet's Drive metadata, its ID is the new spreadsheet ID.
+func (c *Client) CopySpreadsheet(ctx context.Context, spreadsheetID, title, folderID string) (*DriveFile, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/copy
+	url := c.driveURL(driveCopyURL, spreadsheetID)
+
+	file := driveFileRequest{Name: title}
+	if folderID != "" {
+		file.Parents = []string{folderID}
+	}
+
+	response := new(DriveFile)
+	err := c.ReadJSON(ctx, http.MethodPost, url, file, response, driveQuery(driveFileFields))
+	if err != nil {
+		return nil, c.opError(err, "files.copy", spreadsheetID, "", 0)
+	}
+
+	return response, nil
+}
+
+// MoveToFolder moves the spreadsheet of "spreadsheetID" to the Drive folder of "folderID",
+// it's removed from all of its current folders.
+func (c *Client) MoveToFolder(ctx context.Context, spreadsheetID, folderID string) error {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/update
+	url := c.driveURL(driveFileURL, spreadsheetID)
+
+	var file DriveFile
+	err := c.ReadJSON(ctx, http.MethodGet, url, nil, &file, driveQuery("parents"))
+	if err != nil {
+		return c.opError(err, "files.get", spreadsheetID, "", 0)
+	}
+
+	q := driveQuery("id")
+	q["addParents"] = []string{folderID}
+	if len(file.Parents) > 0 {
+		q["removeParents"] = []string{strings.Join(file.Parents, ",")}
+	}
+
+	err = c.ReadJSON(ctx, http.MethodPatch, url, driveFileRequest{}, &file, q)
+	return c.opError(err, "files.update", spreadsheetID, "", 0)
+}
+
+// Trash moves the spreadsheet of "spreadsheetID" to the Drive trash,
+// it can be restored from there for 30 days before it's deleted permanently.
+func (c *Client) Trash(ctx context.Context, spreadsheetID string) error {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/update
+	url := c.driveURL(driveFileURL, spreadsheetID)
+
+	var file DriveFile
+	err := c.ReadJSON(ctx, http.MethodPatch, url, driveFileRequest{Trashed: true}, &file, driveQuery("id,trashed"))
+	return c.opError(err, "files.update", spreadsheetID, "", 0)
+}
+
+// ListSpreadsheets returns a page of the spreadsheets inside the Drive folder of "folderID",
+// trashed ones are excluded, so batch jobs can discover all workbooks under a folder.
+// The "pageToken" is empty for the first page, the next page token is returned until the last page.
+//
+// Usage:
+//
+//	var pageToken string
+//	for {
+//		files, next, err := client.ListSpreadsheets(ctx, folderID, pageToken)
+//		[...]
+//		if pageToken = next; pageToken == "" {
+//			break
+//		}
+//	}
+func (c *Client) ListSpreadsheets(ctx context.Context, folderID, pageToken string) ([]DriveFile, string, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/list
+	url := c.driveURL(driveFilesURL)
+
+	folderID = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(folderID)
+
+	q := driveQuery("nextPageToken,files(" + driveFileFields + ")")
+	q["q"] = []string{fmt.Sprintf("'%s' in parents and mimeType = '%s' and trashed = false", folderID, MimeTypeSpreadsheet)}
+	q["includeItemsFromAllDrives"] = []string{"true"}
+	q["orderBy"] = []string{"name"}
+	if pageToken != "" {
+		q["pageToken"] = []string{pageToken}
+	}
+
+	var response struct {
+		NextPageToken string      `json:"nextPageToken"`
+		Files         []DriveFile `json:"files"`
+	}
+	if err := c.ReadJSON(ctx, http.MethodGet, url, nil, &response, q); err != nil {
+		return nil, "", c.opError(err, "files.list", "", "", 0)
+	}
+
+	return response.Files, response.NextPageToken, nil
+}
+
+// Permission roles, see `Client.ShareSpreadsheet` method.
+const (
+	// RoleReader can view the spreadsheet.
+	RoleReader = "reader"
+	// RoleCommenter can view and comment on the spreadsheet.
+	RoleCommenter = "commenter"
+	// RoleWriter can edit the spreadsheet.
+	RoleWriter = "writer"
+)
+
+// Permission is a Drive permission of a spreadsheet, it grants access to a user, a group, a domain or anyone.
+type Permission struct {
+	ID           string `json:"id,omitempty"`
+	Type         string `json:"type"` // "user", "group", "domain" or "anyone".
+	Role         string `json:"role"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Domain       string `json:"domain,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+}
+
+const permissionFields = "id,type,role,emailAddress,domain,displayName"
+
+// ShareSpreadsheet grants the user with "email" (e.g. the service account's email) access to the spreadsheet
+// with the "role", e.g. `RoleWriter`, so provisioning code does not require a manual "Share" click.
+// Drive sends a notification email to the user.
+func (c *Client) ShareSpreadsheet(ctx context.Context, spreadsheetID, email, role string) (*Permission, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/permissions/create
+	url := c.driveURL(drivePermissionsURL, spreadsheetID)
+
+	response := new(Permission)
+	err := c.ReadJSON(ctx, http.MethodPost, url, Permission{Type: "user", Role: role, EmailAddress: email}, response, driveQuery(permissionFields))
+	if err != nil {
+		return nil, c.opError(err, "permissions.create", spreadsheetID, "", 0)
+	}
+
+	return response, nil
+}
+
+// ListPermissions returns all the permissions of the spreadsheet.
+func (c *Client) ListPermissions(ctx context.Context, spreadsheetID string) ([]Permission, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/permissions/list
+	url := c.driveURL(drivePermissionsURL, spreadsheetID)
+
+	var (
+		permissions []Permission
+		pageToken   string
+	)
+
+	for {
+		q := driveQuery("nextPageToken,permissions(" + permissionFields + ")")
+		if pageToken != "" {
+			q["pageToken"] = []string{pageToken}
+		}
+
+		var response struct {
+			NextPageToken string       `json:"nextPageToken"`
+			Permissions   []Permission `json:"permissions"`
+		}
+		if err := c.ReadJSON(ctx, http.MethodGet, url, nil, &response, q); err != nil {
+			return nil, c.opError(err, "permissions.list", spreadsheetID, "", 0)
+		}
+
+		permissions = append(permissions, response.Permissions...)
+		if pageToken = response.NextPageToken; pageToken == "" {
+			return permissions, nil
+		}
+	}
+}
+
+// download fires a GET request to "url" and copies the response body to "w".
+func (c *Client) download(ctx context.Context, url string, w io.Writer, options ...RequestOption) error {
+	resp, err := c.Do(ctx, http.MethodGet, url, nil, append(options, accept("*/*"))...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		return newResourceError(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// docsExportURL is the documented export URL of the spreadsheets editor,
+// unlike the Drive files.export endpoint it can export a single sheet and accepts page setup options.
+const docsExportURL = "https://docs.google.com/spreadsheets/d/%s/export"
+
+// PDFOptions holds the page setup options of a PDF export.
+// See `Client.ExportPDF` and `Client.ExportSheetPDF` methods.
+type PDFOptions struct {
+	// Landscape sets the page orientation to landscape, defaults to portrait.
+	Landscape bool
+	// FitToWidth scales the content to fit the page width.
+	FitToWidth bool
+	// PaperSize is the paper size, e.g. "A4", "letter" or "legal". Defaults to "letter".
+	PaperSize string
+	// Gridlines shows the cell gridlines.
+	Gridlines bool
+	// HideSheetNames hides the sheet names from the page headers.
+	HideSheetNames bool
+}
+
+func (opts *PDFOptions) query() Query {
+	if opts == nil {
+		opts = new(PDFOptions)
+	}
+
+	q := Query{
+		"format":     []string{"pdf"},
+		"portrait":   []string{strconv.FormatBool(!opts.Landscape)},
+		"fitw":       []string{strconv.FormatBool(opts.FitToWidth)},
+		"gridlines":  []string{strconv.FormatBool(opts.Gridlines)},
+		"sheetnames": []string{strconv.FormatBool(!opts.HideSheetNames)},
+	}
+
+	if opts.PaperSize != "" {
+		q["size"] = []string{opts.PaperSize}
+	}
+
+	return q
+}
+
+// ExportPDF writes all the sheets of the spreadsheet to "w" as a PDF file, e.g. to email generated reports.
+// The "opts" can be nil for the default page setup.
+// The Client must be authorized with a Drive scope, e.g. `ScopeDriveReadOnly` or `ScopeDriveFile`.
+func (c *Client) ExportPDF(ctx context.Context, spreadsheetID string, w io.Writer, opts *PDFOptions) error {
+	url := fmt.Sprintf(docsExportURL, spreadsheetID)
+	err := c.download(ctx, url, w, opts.query())
+	return c.opError(err, "export/pdf", spreadsheetID, "", 0)
+}
+
+// ExportSheetPDF is like `ExportPDF` but it writes only the sheet with "sheetID" to "w".
+func (c *Client) ExportSheetPDF(ctx context.Context, spreadsheetID string, sheetID int64, w io.Writer, opts *PDFOptions) error {
+	url := fmt.Sprintf(docsExportURL, spreadsheetID)
+	q := opts.query()
+	q["gid"] = []string{strconv.FormatInt(sheetID, 10)}
+
+	err := c.download(ctx, url, w, q)
+	return c.opError(err, "export/pdf", spreadsheetID, "", 0)
+}
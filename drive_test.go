@@ -0,0 +1,232 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientExportXLSX(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/drive/v3/files/id/export", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+		if expected, got := MimeTypeXLSX, r.URL.Query().Get("mimeType"); expected != got {
+			t.Errorf("expected mime type %q but got %q", expected, got)
+		}
+		if expected, got := "*/*", r.Header.Get("Accept"); expected != got {
+			t.Errorf("expected accept header %q but got %q", expected, got)
+		}
+
+		if r.URL.Query().Get("fail") == "" {
+			w.Write([]byte("PK\x03\x04xlsx"))
+			return
+		}
+
+		http.Error(w, `{"error":{"code":404,"status":"NOT_FOUND"}}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var host string
+	c := NewClient(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		host = r.URL.Host
+		return rewriteTransport(srv.URL).RoundTrip(r)
+	}))
+
+	var b bytes.Buffer
+	if err := c.ExportXLSX(context.Background(), "id", &b); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "www.googleapis.com", host; expected != got {
+		t.Fatalf("expected host %q but got %q", expected, got)
+	}
+	if expected, got := "PK\x03\x04xlsx", b.String(); expected != got {
+		t.Fatalf("expected body %q but got %q", expected, got)
+	}
+
+	c.Options = append(c.Options, Query{"fail": []string{"1"}})
+	if err := c.ExportXLSX(context.Background(), "id", &b); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a not found error but got: %v", err)
+	}
+}
+
+func TestClientExportSheetPDF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/spreadsheets/d/id/export", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+
+		q := r.URL.Query()
+		for key, expected := range map[string]string{"format": "pdf", "gid": "42", "portrait": "false", "fitw": "true", "size": "A4", "gridlines": "false"} {
+			if got := q.Get(key); expected != got {
+				t.Errorf("expected %q query value %q but got %q", key, expected, got)
+			}
+		}
+
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer srv.Close()
+
+	var host string
+	c := NewClient(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		host = r.URL.Host
+		return rewriteTransport(srv.URL).RoundTrip(r)
+	}))
+
+	var b bytes.Buffer
+	err := c.ExportSheetPDF(context.Background(), "id", 42, &b, &PDFOptions{Landscape: true, FitToWidth: true, PaperSize: "A4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "docs.google.com", host; expected != got {
+		t.Fatalf("expected host %q but got %q", expected, got)
+	}
+	if expected, got := "%PDF-1.4", b.String(); expected != got {
+		t.Fatalf("expected body %q but got %q", expected, got)
+	}
+}
+
+func TestClientDriveFiles(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		q := r.URL.Query()
+		requests = append(requests, fmt.Sprintf("%s %s %s add=%s remove=%s", r.Method, r.URL.Path, bytes.TrimSpace(body), q.Get("addParents"), q.Get("removeParents")))
+
+		if q.Get("supportsAllDrives") != "true" {
+			t.Errorf("expected shared drives support")
+		}
+
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"id":"id","parents":["old1","old2"]}`))
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"id":"new","name":"Report","mimeType":"application/vnd.google-apps.spreadsheet","parents":["folder"],"modifiedTime":"2024-01-02T03:04:05Z"}`))
+		default:
+			w.Write([]byte(`{"id":"id"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	file, err := c.CreateSpreadsheetIn(ctx, "folder", "Report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "new" || file.ModifiedTime.Year() != 2024 || file.Parents[0] != "folder" {
+		t.Fatalf("unexpected file: %#v", file)
+	}
+
+	if _, err = c.CopySpreadsheet(ctx, "id", "Report copy", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.MoveToFolder(ctx, "id", "folder"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Trash(ctx, "id"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`POST /drive/v3/files {"name":"Report","mimeType":"application/vnd.google-apps.spreadsheet","parents":["folder"]} add= remove=`,
+		`POST /drive/v3/files/id/copy {"name":"Report copy"} add= remove=`,
+		`GET /drive/v3/files/id  add= remove=`,
+		`PATCH /drive/v3/files/id {} add=folder remove=old1,old2`,
+		`PATCH /drive/v3/files/id {"trashed":true} add= remove=`,
+	}
+	if !reflect.DeepEqual(expected, requests) {
+		t.Fatalf("expected requests:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), strings.Join(requests, "\n"))
+	}
+}
+
+func TestClientPermissions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			if expected, got := `{"type":"user","role":"writer","emailAddress":"makis@example.com"}`, string(body); expected != got {
+				t.Errorf("expected body %s but got %s", expected, got)
+			}
+
+			w.Write([]byte(`{"id":"p1","type":"user","role":"writer","emailAddress":"makis@example.com"}`))
+			return
+		}
+
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"nextPageToken":"next","permissions":[{"id":"p0","type":"user","role":"owner"}]}`))
+			return
+		}
+
+		w.Write([]byte(`{"permissions":[{"id":"p1","type":"user","role":"writer"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	permission, err := c.ShareSpreadsheet(ctx, "id", "makis@example.com", RoleWriter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permission.ID != "p1" {
+		t.Fatalf("unexpected permission: %#v", permission)
+	}
+
+	permissions, err := c.ListPermissions(ctx, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(permissions) != 2 || permissions[0].Role != "owner" || permissions[1].ID != "p1" {
+		t.Fatalf("unexpected permissions: %#v", permissions)
+	}
+}
+
+func TestClientListSpreadsheets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if expected, got := `'o\'neil' in parents and mimeType = 'application/vnd.google-apps.spreadsheet' and trashed = false`, q.Get("q"); expected != got {
+			t.Errorf("expected query %s but got %s", expected, got)
+		}
+
+		if q.Get("pageToken") == "" {
+			w.Write([]byte(`{"nextPageToken":"next","files":[{"id":"a","name":"Customer A","modifiedTime":"2024-01-02T03:04:05Z"}]}`))
+			return
+		}
+
+		w.Write([]byte(`{"files":[{"id":"b","name":"Customer B"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	var ids []string
+	pageToken := ""
+	for {
+		files, next, err := c.ListSpreadsheets(context.Background(), "o'neil", pageToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, file := range files {
+			ids = append(ids, file.ID)
+		}
+
+		if pageToken = next; pageToken == "" {
+			break
+		}
+	}
+
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(expected, ids) {
+		t.Fatalf("expected files %v but got %v", expected, ids)
+	}
+}
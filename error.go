@@ -1,14 +1,41 @@
 package sheets
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Sentinel errors which `errors.Is` matches against the `ResourceError` values returned by the Client.
+// Usage:
+//
+//	if errors.Is(err, sheets.ErrNotFound) {
+//		[...]
+//	}
+var (
+	// ErrNotFound reports that the spreadsheet, sheet or range does not exist.
+	ErrNotFound = errors.New("sheets: not found")
+	// ErrPermissionDenied reports that the caller has no access to the resource
+	// or the granted scopes are insufficient.
+	ErrPermissionDenied = errors.New("sheets: permission denied")
+	// ErrRateLimited reports that a rate limit or quota was exceeded, see `ResourceError.RetryAfter` too.
+	ErrRateLimited = errors.New("sheets: rate limited")
+	// ErrInvalidRange reports that the range cannot be parsed or exceeds the grid limits of the sheet.
+	ErrInvalidRange = errors.New("sheets: invalid range")
 )
 
 // ResourceError is a Client type error.
 // It returns from Client's method when server replies with an error.
 // It holds the HTTP Method, URL, Status Code and the actual error message came from server.
+// When the server replies in the Google API error format, the Code, Status, Reason and Details fields are filled.
 //
 // See `IsResourceError` and `IsStatusError` too.
 type ResourceError struct {
@@ -16,25 +43,424 @@ type ResourceError struct {
 	URL        string
 	StatusCode int
 	Message    string
+	// Code is the "error.code" field of the Google API error, usually equal to the StatusCode.
+	Code int
+	// Status is the canonical error code, e.g. "PERMISSION_DENIED", "NOT_FOUND", "RESOURCE_EXHAUSTED".
+	Status string
+	// Reason is the reason of the first error detail which holds one, e.g. "RATE_LIMIT_EXCEEDED".
+	Reason string
+	// Details are the error details, e.g. "google.rpc.ErrorInfo" and "google.rpc.RetryInfo".
+	Details []ErrorDetail
+	// RetryAfter is the duration the server asked the client to wait before retrying the request.
+	// It is parsed from the "Retry-After" header or the "RetryInfo" error details.
+	// Zero when not specified.
+	RetryAfter time.Duration
+
+	quotaExceeded bool
+	// err is the error occurred while reading the response body, if any.
+	err error
+}
+
+// ErrorDetail is a detail of a Google API error.
+// See `ResourceError.Details` field.
+type ErrorDetail struct {
+	// Type is the type URL of the detail, e.g. "type.googleapis.com/google.rpc.ErrorInfo".
+	Type string `json:"@type"`
+	// Reason and Domain are set on "google.rpc.ErrorInfo" details.
+	Reason   string            `json:"reason,omitempty"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// RetryDelay is set on "google.rpc.RetryInfo" details, e.g. "1.5s".
+	RetryDelay string `json:"retryDelay,omitempty"`
+}
+
+// maxErrorBodyBytes is the maximum number of bytes of an error response body
+// kept in the `ResourceError.Message` field.
+const maxErrorBodyBytes = 64 << 10
+
+// truncatedSuffix is appended to the `ResourceError.Message` field when the error response body is too large.
+const truncatedSuffix = "... (truncated)"
+
+// newStatusError returns the error of an unexpected "resp" status code of a non-Sheets API request,
+// e.g. "token info: 400: <body>", the body is limited to `maxErrorBodyBytes`.
+func newStatusError(prefix string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return fmt.Errorf("%s: %d: %s", prefix, resp.StatusCode, body)
 }
 
 func newResourceError(resp *http.Response) *ResourceError {
-	cause := "unspecified"
+	var (
+		cause   = "unspecified"
+		readErr error
+	)
 
 	if resp.Body != nil {
-		b, err := ioutil.ReadAll(resp.Body)
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes+1))
+		readErr = err
 		if err == nil {
 			cause = string(b)
+			if len(b) > maxErrorBodyBytes {
+				cause = string(b[:maxErrorBodyBytes]) + truncatedSuffix
+				// Drain the remainder so that the underlying connection can be reused.
+				io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+			}
 		}
 	}
 
 	endpoint := resp.Request.URL.String()
-	return &ResourceError{
+	e := &ResourceError{
 		Method:     resp.Request.Method,
 		URL:        endpoint,
 		StatusCode: resp.StatusCode,
 		Message:    cause,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		err:        readErr,
+	}
+
+	e.parseDetails()
+	return e
+}
+
+// parseRetryAfter parses a "Retry-After" header value,
+// which can be a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// parseDetails parses the error message which is sent by the server in the Google API error format
+// into the structured fields and the rate-limit information.
+func (e *ResourceError) parseDetails() {
+	var payload struct {
+		Error struct {
+			Code    int           `json:"code"`
+			Status  string        `json:"status"`
+			Details []ErrorDetail `json:"details"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+
+	if e.StatusCode == http.StatusTooManyRequests {
+		e.quotaExceeded = true
+	}
+
+	if err := json.Unmarshal([]byte(e.Message), &payload); err != nil {
+		return
+	}
+
+	e.Code = payload.Error.Code
+	e.Status = payload.Error.Status
+	e.Details = payload.Error.Details
+
+	if e.Status == "RESOURCE_EXHAUSTED" {
+		e.quotaExceeded = true
+	}
+
+	for _, detail := range e.Details {
+		if e.Reason == "" {
+			e.Reason = detail.Reason
+		}
+
+		if isRateLimitReason(detail.Reason) {
+			e.quotaExceeded = true
+		}
+
+		if detail.RetryDelay != "" && e.RetryAfter == 0 {
+			if d, err := time.ParseDuration(detail.RetryDelay); err == nil && d > 0 {
+				e.RetryAfter = d
+			}
+		}
+	}
+
+	for _, item := range payload.Error.Errors {
+		if e.Reason == "" {
+			e.Reason = item.Reason
+		}
+
+		if isRateLimitReason(item.Reason) {
+			e.quotaExceeded = true
+		}
+	}
+}
+
+func isRateLimitReason(reason string) bool {
+	switch reason {
+	case "RATE_LIMIT_EXCEEDED", "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+		return true
+	default:
+		return false
+	}
+}
+
+// QuotaExceeded reports whether the request failed because of a rate limit or quota error.
+// See `Client.OnQuotaExceeded` method too.
+func (e *ResourceError) QuotaExceeded() bool {
+	return e.quotaExceeded
+}
+
+// ErrorReason is a typed, normalized, reason of a `ResourceError`.
+// See `ResourceError.ErrorReason` method.
+type ErrorReason string
+
+// The error reasons, derived from the error details' reason,
+// the canonical status or the HTTP status code of the response, in that order.
+const (
+	ReasonUnknown            ErrorReason = ""
+	ReasonRateLimitExceeded  ErrorReason = "RATE_LIMIT_EXCEEDED"
+	ReasonQuotaExceeded      ErrorReason = "QUOTA_EXCEEDED"
+	ReasonScopeInsufficient  ErrorReason = "ACCESS_TOKEN_SCOPE_INSUFFICIENT"
+	ReasonAPIKeyInvalid      ErrorReason = "API_KEY_INVALID"
+	ReasonServiceDisabled    ErrorReason = "SERVICE_DISABLED"
+	ReasonInvalidArgument    ErrorReason = "INVALID_ARGUMENT"
+	ReasonFailedPrecondition ErrorReason = "FAILED_PRECONDITION"
+	ReasonUnauthenticated    ErrorReason = "UNAUTHENTICATED"
+	ReasonPermissionDenied   ErrorReason = "PERMISSION_DENIED"
+	ReasonNotFound           ErrorReason = "NOT_FOUND"
+	ReasonAlreadyExists      ErrorReason = "ALREADY_EXISTS"
+	ReasonAborted            ErrorReason = "ABORTED"
+	ReasonResourceExhausted  ErrorReason = "RESOURCE_EXHAUSTED"
+	ReasonDeadlineExceeded   ErrorReason = "DEADLINE_EXCEEDED"
+	ReasonInternal           ErrorReason = "INTERNAL"
+	ReasonUnavailable        ErrorReason = "UNAVAILABLE"
+)
+
+// ErrorReason returns the typed reason of the error, so callers can switch on it.
+// Usage:
+//
+//	switch resErr.ErrorReason() {
+//	case sheets.ReasonRateLimitExceeded, sheets.ReasonQuotaExceeded:
+//	case sheets.ReasonNotFound:
+//	}
+func (e *ResourceError) ErrorReason() ErrorReason {
+	switch e.Reason {
+	case "RATE_LIMIT_EXCEEDED", "rateLimitExceeded", "userRateLimitExceeded":
+		return ReasonRateLimitExceeded
+	case "quotaExceeded":
+		return ReasonQuotaExceeded
+	case "ACCESS_TOKEN_SCOPE_INSUFFICIENT", "insufficientPermissions":
+		return ReasonScopeInsufficient
+	case "API_KEY_INVALID", "keyInvalid":
+		return ReasonAPIKeyInvalid
+	case "SERVICE_DISABLED", "accessNotConfigured":
+		return ReasonServiceDisabled
+	}
+
+	if e.Status != "" {
+		return ErrorReason(e.Status)
+	}
+
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ReasonInvalidArgument
+	case http.StatusUnauthorized:
+		return ReasonUnauthenticated
+	case http.StatusForbidden:
+		return ReasonPermissionDenied
+	case http.StatusNotFound:
+		return ReasonNotFound
+	case http.StatusConflict:
+		return ReasonAborted
+	case http.StatusTooManyRequests:
+		return ReasonResourceExhausted
+	case http.StatusInternalServerError:
+		return ReasonInternal
+	case http.StatusServiceUnavailable:
+		return ReasonUnavailable
+	case http.StatusGatewayTimeout:
+		return ReasonDeadlineExceeded
+	default:
+		return ReasonUnknown
+	}
+}
+
+// Retryable reports whether the request is safe to retry, e.g. after a rate limit (429)
+// or a transient server error (500, 502, 503, 504).
+// Client errors, such as 400, 403 and 404, are permanent.
+// See `IsRetryable` package-level function too.
+func (e *ResourceError) Retryable() bool {
+	if e.quotaExceeded {
+		return true
+	}
+
+	switch e.StatusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Temporary reports whether the error is temporary, it's an alias of `Retryable`.
+func (e *ResourceError) Temporary() bool {
+	return e.Retryable()
+}
+
+// IsRetryable reports whether "err" is safe to retry.
+// It handles `ResourceError` values (see `ResourceError.Retryable`)
+// and transient network errors, such as connection resets and timeouts.
+// It returns false for canceled contexts.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var resErr *ResourceError
+	if errors.As(err, &resErr) {
+		return resErr.Retryable()
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Unwrap returns the error occurred while reading the response body, if any.
+// It implements the standard `errors.Unwrap` internal interface.
+func (e *ResourceError) Unwrap() error {
+	return e.err
+}
+
+// TransportError is returned when a request could not reach the server or its response could not be received,
+// e.g. on connection failures or when the request's context is done.
+// It tells apart the cancellation and the deadline of the request's context from network failures,
+// so retry logic and alerting can treat them differently.
+type TransportError struct {
+	Method string
+	// URL is the request URL without its query, which may hold an API key.
+	URL string
+	// Canceled reports whether the request's context was canceled.
+	Canceled bool
+	// DeadlineExceeded reports whether the request's context deadline was exceeded.
+	DeadlineExceeded bool
+	// Err is the context's error or the network error.
+	Err error
+}
+
+func newTransportError(req *http.Request, err error) *TransportError {
+	endpoint := *req.URL
+	endpoint.RawQuery = ""
+
+	return &TransportError{
+		Method:           req.Method,
+		URL:              endpoint.String(),
+		Canceled:         errors.Is(err, context.Canceled),
+		DeadlineExceeded: errors.Is(err, context.DeadlineExceeded),
+		Err:              err,
+	}
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("transport error [%s: %s]: %v", e.Method, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// ContextDone reports whether the request failed because its context is done,
+// as opposed to a network failure.
+func (e *TransportError) ContextDone() bool {
+	return e.Canceled || e.DeadlineExceeded
+}
+
+// OpError is returned from the Client's methods when a Sheets API operation fails.
+// It annotates the underlying error with the operation, the spreadsheet, the range and the number of rows written,
+// so logs of jobs which process many spreadsheets identify the failing document.
+// Use `errors.As` to extract it and `errors.Is` or `errors.As` to check the underlying error.
+type OpError struct {
+	// Op is the operation, e.g. "values.get", "values.update" or "spreadsheets.batchUpdate/addChart".
+	Op            string
+	SpreadsheetID string
+	// Range is the range of the operation, if any.
+	// Multiple ranges of a batch operation are separated by comma.
+	Range string
+	// Rows is the number of rows written by the operation, if any.
+	Rows int
+	// Err is the underlying error, e.g. a `ResourceError`, a `TransportError` or an `InvalidRangeError`.
+	Err error
+}
+
+// location returns the operation and its spreadsheet and range, e.g. "values.get <spreadsheetID>!<range>".
+func (e *OpError) location() string {
+	location := e.Op + " " + e.SpreadsheetID
+	if e.Range != "" {
+		location += "!" + e.Range
 	}
+
+	return location
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *OpError) Error() string {
+	location := e.location()
+	if e.Rows > 0 {
+		location += fmt.Sprintf(" (%d rows)", e.Rows)
+	}
+
+	return fmt.Sprintf("sheets: %s: %v", location, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// opError annotates a non-nil "err" with the Sheets API operation which failed, see `OpError`.
+// It calls the `Client.OnError` hook, if registered, when the error came from the server.
+func (c *Client) opError(err error, op, spreadsheetID, dataRange string, rows int) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &OpError{
+		Op:            op,
+		SpreadsheetID: spreadsheetID,
+		Range:         dataRange,
+		Rows:          rows,
+		Err:           err,
+	}
+
+	if c.onError != nil {
+		var resErr *ResourceError
+		if errors.As(err, &resErr) {
+			c.onError(e.location(), resErr)
+		}
+	}
+
+	return e
 }
 
 // Error implements a Go error and returns a human-readable error text.
@@ -42,7 +468,7 @@ func (e *ResourceError) Error() string {
 	return fmt.Sprintf("resource error [%s: %s]: %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
 }
 
-// IsStatusError reports whether a "target" error is type of `ResourceError` and the status code is the provided "statusCode" one.
+// IsStatusError reports whether a "target" error is, or wraps, a `ResourceError` and the status code is the provided "statusCode" one.
 // Usage:
 // resErr, ok := IsStatusError(http.StatusNotFound, err)
 //
@@ -52,12 +478,8 @@ func (e *ResourceError) Error() string {
 //
 // See `IsResourceError` too.
 func IsStatusError(statusCode int, target error) (*ResourceError, bool) {
-	if target == nil {
-		return nil, false
-	}
-
-	t, ok := target.(*ResourceError)
-	if !ok {
+	var t *ResourceError
+	if !errors.As(target, &t) {
 		return nil, false
 	}
 
@@ -72,8 +494,8 @@ func IsResourceError(e *ResourceError, target error) bool {
 		return e == nil
 	}
 
-	t, ok := target.(*ResourceError)
-	if !ok {
+	var t *ResourceError
+	if !errors.As(target, &t) {
 		return false
 	}
 
@@ -84,7 +506,29 @@ func IsResourceError(e *ResourceError, target error) bool {
 }
 
 // Is implements the standard`errors.Is` internal interface.
-// It's equivalent of the `IsResourceError` package-level function.
+// It's equivalent of the `IsResourceError` package-level function
+// and it matches the `ErrNotFound`, `ErrPermissionDenied`, `ErrRateLimited` and `ErrInvalidRange` sentinel errors.
 func (e *ResourceError) Is(target error) bool { // implements Go 1.13 errors.Is internal interface.
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Status == "NOT_FOUND"
+	case ErrPermissionDenied:
+		return (e.StatusCode == http.StatusForbidden || e.Status == "PERMISSION_DENIED") && !e.quotaExceeded
+	case ErrRateLimited:
+		return e.quotaExceeded
+	case ErrInvalidRange:
+		return e.isInvalidRange()
+	}
+
 	return IsResourceError(e, target)
 }
+
+// isInvalidRange reports whether the server rejected the range of the request.
+func (e *ResourceError) isInvalidRange() bool {
+	if e.StatusCode != http.StatusBadRequest && e.Status != "INVALID_ARGUMENT" {
+		return false
+	}
+
+	message := strings.ToLower(e.Message)
+	return strings.Contains(message, "unable to parse range") || strings.Contains(message, "exceeds grid limits")
+}
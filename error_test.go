@@ -0,0 +1,268 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestResourceErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		header     string
+		body       string
+		statusCode int
+		retryAfter time.Duration
+		quota      bool
+	}{
+		{"", `{}`, http.StatusNotFound, 0, false},
+		{"30", `{}`, http.StatusTooManyRequests, 30 * time.Second, true},
+		{"", `{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"1.5s"}]}}`, http.StatusTooManyRequests, 1500 * time.Millisecond, true},
+		{"", `{"error":{"code":403,"errors":[{"reason":"rateLimitExceeded"}]}}`, http.StatusForbidden, 0, true},
+		{"", `{"error":{"code":403,"status":"PERMISSION_DENIED"}}`, http.StatusForbidden, 0, false},
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		if tt.header != "" {
+			rec.Header().Set("Retry-After", tt.header)
+		}
+		rec.WriteHeader(tt.statusCode)
+		rec.WriteString(tt.body)
+
+		resp := rec.Result()
+		resp.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+		err := newResourceError(resp)
+		if expected, got := tt.retryAfter, err.RetryAfter; expected != got {
+			t.Fatalf("[%d] expected retry after %s but got %s", i, expected, got)
+		}
+		if expected, got := tt.quota, err.QuotaExceeded(); expected != got {
+			t.Fatalf("[%d] expected quota exceeded %v but got %v", i, expected, got)
+		}
+	}
+}
+
+func TestResourceErrorDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusForbidden)
+	rec.WriteString(`{"error":{"code":403,"message":"The caller does not have permission","status":"PERMISSION_DENIED","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"ACCESS_TOKEN_SCOPE_INSUFFICIENT","domain":"googleapis.com","metadata":{"service":"sheets.googleapis.com"}}]}}`)
+
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := newResourceError(resp)
+	if expected, got := 403, err.Code; expected != got {
+		t.Fatalf("expected code %d but got %d", expected, got)
+	}
+	if expected, got := "PERMISSION_DENIED", err.Status; expected != got {
+		t.Fatalf("expected status %q but got %q", expected, got)
+	}
+	if expected, got := "ACCESS_TOKEN_SCOPE_INSUFFICIENT", err.Reason; expected != got {
+		t.Fatalf("expected reason %q but got %q", expected, got)
+	}
+	if len(err.Details) != 1 || err.Details[0].Metadata["service"] != "sheets.googleapis.com" {
+		t.Fatalf("unexpected details: %#v", err.Details)
+	}
+}
+
+func TestResourceErrorTruncatedBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusBadGateway)
+	rec.WriteString("<html>" + strings.Repeat("x", 2*maxErrorBodyBytes) + "</html>")
+
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := newResourceError(resp)
+	if expected, got := maxErrorBodyBytes+len(truncatedSuffix), len(err.Message); expected != got {
+		t.Fatalf("expected message length %d but got %d", expected, got)
+	}
+	if !strings.HasSuffix(err.Message, truncatedSuffix) {
+		t.Fatalf("expected message to be marked as truncated")
+	}
+}
+
+func TestResourceErrorSentinels(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+		sentinel   error
+	}{
+		{http.StatusNotFound, `{"error":{"code":404,"status":"NOT_FOUND"}}`, ErrNotFound},
+		{http.StatusForbidden, `{"error":{"code":403,"status":"PERMISSION_DENIED"}}`, ErrPermissionDenied},
+		{http.StatusTooManyRequests, `{"error":{"code":429,"status":"RESOURCE_EXHAUSTED"}}`, ErrRateLimited},
+		{http.StatusBadRequest, `{"error":{"code":400,"message":"Unable to parse range: Sheet1!A1:","status":"INVALID_ARGUMENT"}}`, ErrInvalidRange},
+	}
+
+	sentinels := []error{ErrNotFound, ErrPermissionDenied, ErrRateLimited, ErrInvalidRange}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(tt.statusCode)
+		rec.WriteString(tt.body)
+
+		resp := rec.Result()
+		resp.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+		err := fmt.Errorf("wrapped: %w", newResourceError(resp))
+		for _, sentinel := range sentinels {
+			if expected, got := sentinel == tt.sentinel, errors.Is(err, sentinel); expected != got {
+				t.Fatalf("[%d] expected errors.Is(%v) to be %v but got %v", i, sentinel, expected, got)
+			}
+		}
+
+		var resErr *ResourceError
+		if !errors.As(err, &resErr) || resErr.StatusCode != tt.statusCode {
+			t.Fatalf("[%d] expected errors.As to extract the resource error", i)
+		}
+		if _, ok := IsStatusError(tt.statusCode, err); !ok {
+			t.Fatalf("[%d] expected IsStatusError to match the wrapped error", i)
+		}
+	}
+}
+
+func TestResourceErrorReason(t *testing.T) {
+	tests := []struct {
+		err    *ResourceError
+		reason ErrorReason
+	}{
+		{&ResourceError{StatusCode: 429, Status: "RESOURCE_EXHAUSTED", Reason: "RATE_LIMIT_EXCEEDED"}, ReasonRateLimitExceeded},
+		{&ResourceError{StatusCode: 403, Reason: "userRateLimitExceeded"}, ReasonRateLimitExceeded},
+		{&ResourceError{StatusCode: 403, Status: "PERMISSION_DENIED", Reason: "ACCESS_TOKEN_SCOPE_INSUFFICIENT"}, ReasonScopeInsufficient},
+		{&ResourceError{StatusCode: 404, Status: "NOT_FOUND"}, ReasonNotFound},
+		{&ResourceError{StatusCode: 400, Status: "FAILED_PRECONDITION"}, ReasonFailedPrecondition},
+		{&ResourceError{StatusCode: 503}, ReasonUnavailable},
+		{&ResourceError{StatusCode: 418}, ReasonUnknown},
+	}
+
+	for i, tt := range tests {
+		if expected, got := tt.reason, tt.err.ErrorReason(); expected != got {
+			t.Fatalf("[%d] expected reason %q but got %q", i, expected, got)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{&ResourceError{StatusCode: http.StatusTooManyRequests}, true},
+		{&ResourceError{StatusCode: http.StatusInternalServerError}, true},
+		{&ResourceError{StatusCode: http.StatusBadGateway}, true},
+		{&ResourceError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&ResourceError{StatusCode: http.StatusForbidden, quotaExceeded: true}, true},
+		{&ResourceError{StatusCode: http.StatusBadRequest}, false},
+		{&ResourceError{StatusCode: http.StatusForbidden}, false},
+		{&ResourceError{StatusCode: http.StatusNotFound}, false},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, true},
+		{errors.New("json: syntax error"), false},
+		{nil, false},
+	}
+
+	for i, tt := range tests {
+		if expected, got := tt.retryable, IsRetryable(tt.err); expected != got {
+			t.Fatalf("[%d] expected retryable %v but got %v for %v", i, expected, got, tt.err)
+		}
+	}
+}
+
+func TestClientOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	var ops []string
+	c.OnError(func(op string, err *ResourceError) {
+		if err.ErrorReason() != ReasonNotFound {
+			t.Errorf("unexpected error reason: %s", err.ErrorReason())
+		}
+		ops = append(ops, op)
+	})
+
+	c.Range(context.Background(), "id", "A1:B")
+	c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "Sheet1!A1"})
+	c.Range(context.Background(), "id", "Invalid Range!A1") // not a server error.
+
+	if expected, got := "values.get id!A1:B,values.update id!Sheet1!A1", strings.Join(ops, ","); expected != got {
+		t.Fatalf("expected operations %q but got %q", expected, got)
+	}
+}
+
+func TestClientOpError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":403,"status":"PERMISSION_DENIED"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	_, err := c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "Sheet1!A1:B", Values: [][]interface{}{{"a"}, {"b"}}})
+
+	var opErr *OpError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("expected an operation error but got %v", err)
+	}
+	if opErr.Op != "values.update" || opErr.SpreadsheetID != "id" || opErr.Range != "Sheet1!A1:B" || opErr.Rows != 2 {
+		t.Fatalf("unexpected operation error: %#v", opErr)
+	}
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected the operation error to wrap a permission denied error")
+	}
+	if expected, got := "sheets: values.update id!Sheet1!A1:B (2 rows): resource error", err.Error(); !strings.HasPrefix(got, expected) {
+		t.Fatalf("expected error to start with %q but got %q", expected, got)
+	}
+
+	_, err = c.Range(context.Background(), "id", "A1", "Bad Title!A1")
+	if !errors.As(err, &opErr) || opErr.Range != "Bad Title!A1" || !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an operation error of an invalid range but got %v", err)
+	}
+}
+
+func TestClientOnQuotaExceeded(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"details":[{"retryDelay":"0.01s"}]}}`))
+			return
+		}
+
+		w.Write([]byte(`{"range":"A1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultTransport)
+
+	var waits []time.Duration
+	c.OnQuotaExceeded(func(wait time.Duration) {
+		waits = append(waits, wait)
+	})
+
+	var payload ValueRange
+	if err := c.ReadJSON(context.Background(), http.MethodGet, srv.URL, nil, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 2, len(waits); expected != got {
+		t.Fatalf("expected %d quota callbacks but got %d", expected, got)
+	}
+	if expected, got := "A1", payload.Range; expected != got {
+		t.Fatalf("expected range %q but got %q", expected, got)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/kataras/sheets
 
-go 1.23
+go 1.24
 
 require golang.org/x/oauth2 v0.23.0
 
@@ -0,0 +1,114 @@
+package sheets
+
+// Offset returns a copy of the range moved by "rows" and "columns", which can be negative.
+// Unbounded ends stay unbounded. Indexes are clamped to the first row and column, which shrinks the range,
+// the caller should not move a range before the first row or column.
+func (r GridRange) Offset(rows, columns int64) GridRange {
+	r.StartRowIndex, r.EndRowIndex = offsetIndexes(r.StartRowIndex, r.EndRowIndex, rows)
+	r.StartColumnIndex, r.EndColumnIndex = offsetIndexes(r.StartColumnIndex, r.EndColumnIndex, columns)
+	return r
+}
+
+func offsetIndexes(start, end, n int64) (int64, int64) {
+	if end > 0 {
+		end += n
+		if end < 1 {
+			end = 1
+		}
+	}
+
+	start += n
+	if start < 0 {
+		start = 0
+	}
+
+	return start, end
+}
+
+// Resize returns a copy of the range with "rows" rows and "columns" columns from its start.
+// A zero "rows" or "columns" makes that dimension unbounded.
+func (r GridRange) Resize(rows, columns int64) GridRange {
+	r.EndRowIndex, r.EndColumnIndex = 0, 0
+	if rows > 0 {
+		r.EndRowIndex = r.StartRowIndex + rows
+	}
+	if columns > 0 {
+		r.EndColumnIndex = r.StartColumnIndex + columns
+	}
+
+	return r
+}
+
+// Intersect returns the range which is common to "r" and "other".
+// It reports false if the ranges are on different sheets or do not overlap.
+func (r GridRange) Intersect(other GridRange) (GridRange, bool) {
+	if r.SheetID != other.SheetID {
+		return GridRange{}, false
+	}
+
+	var ok bool
+	intersection := GridRange{SheetID: r.SheetID}
+
+	intersection.StartRowIndex, intersection.EndRowIndex, ok = intersectIndexes(r.StartRowIndex, r.EndRowIndex, other.StartRowIndex, other.EndRowIndex)
+	if !ok {
+		return GridRange{}, false
+	}
+
+	intersection.StartColumnIndex, intersection.EndColumnIndex, ok = intersectIndexes(r.StartColumnIndex, r.EndColumnIndex, other.StartColumnIndex, other.EndColumnIndex)
+	if !ok {
+		return GridRange{}, false
+	}
+
+	return intersection, true
+}
+
+func intersectIndexes(start1, end1, start2, end2 int64) (start, end int64, ok bool) {
+	start = start1
+	if start2 > start {
+		start = start2
+	}
+
+	// a zero end index is unbounded.
+	switch {
+	case end1 == 0:
+		end = end2
+	case end2 == 0:
+		end = end1
+	case end1 < end2:
+		end = end1
+	default:
+		end = end2
+	}
+
+	return start, end, end == 0 || start < end
+}
+
+// Contains reports whether "other" is entirely within "r".
+func (r GridRange) Contains(other GridRange) bool {
+	return r.SheetID == other.SheetID &&
+		containsIndexes(r.StartRowIndex, r.EndRowIndex, other.StartRowIndex, other.EndRowIndex) &&
+		containsIndexes(r.StartColumnIndex, r.EndColumnIndex, other.StartColumnIndex, other.EndColumnIndex)
+}
+
+func containsIndexes(start, end, otherStart, otherEnd int64) bool {
+	if otherStart < start {
+		return false
+	}
+
+	if end == 0 { // unbounded.
+		return true
+	}
+
+	return otherEnd > 0 && otherEnd <= end
+}
+
+// ContainsCell reports whether the cell at the zero-based "row" and "column" indexes is within "r".
+func (r GridRange) ContainsCell(row, column int64) bool {
+	return r.Contains(GridRange{
+		SheetID:          r.SheetID,
+		StartRowIndex:    row,
+		EndRowIndex:      row + 1,
+		StartColumnIndex: column,
+		EndColumnIndex:   column + 1,
+	})
+}
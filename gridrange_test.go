@@ -0,0 +1,57 @@
+package sheets
+
+import "testing"
+
+func mustParseA1(t *testing.T, dataRange string) GridRange {
+	t.Helper()
+
+	_, gridRange, err := ParseA1(dataRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gridRange
+}
+
+func TestGridRangeArithmetic(t *testing.T) {
+	r := mustParseA1(t, "B2:D10")
+
+	if expected, got := "C12:E20", r.Offset(10, 1).A1(""); expected != got {
+		t.Fatalf("expected offset range %q but got %q", expected, got)
+	}
+	if expected, got := "A1:A5", r.Offset(-5, -5).A1(""); expected != got {
+		t.Fatalf("expected clamped offset range %q but got %q", expected, got)
+	}
+	if expected, got := "B2:C4", r.Resize(3, 2).A1(""); expected != got {
+		t.Fatalf("expected resized range %q but got %q", expected, got)
+	}
+	if expected, got := "B2:C", r.Resize(0, 2).A1(""); expected != got {
+		t.Fatalf("expected resized unbounded range %q but got %q", expected, got)
+	}
+
+	intersection, ok := r.Intersect(mustParseA1(t, "C5:Z"))
+	if expected, got := "C5:D10", intersection.A1(""); !ok || expected != got {
+		t.Fatalf("expected intersection %q but got %q (%v)", expected, got, ok)
+	}
+
+	if _, ok = r.Intersect(mustParseA1(t, "E1:F2")); ok {
+		t.Fatal("expected no intersection")
+	}
+
+	other := mustParseA1(t, "C3:D4")
+	other.SheetID = 1
+	if _, ok = r.Intersect(other); ok {
+		t.Fatal("expected no intersection of ranges on different sheets")
+	}
+
+	if !r.Contains(mustParseA1(t, "C3:D4")) || r.Contains(mustParseA1(t, "C3:E4")) || r.Contains(mustParseA1(t, "C3:D")) {
+		t.Fatal("unexpected containment result")
+	}
+	if !mustParseA1(t, "A:D").Contains(mustParseA1(t, "C3:D")) {
+		t.Fatal("expected an unbounded range to contain an unbounded range")
+	}
+
+	if !r.ContainsCell(1, 1) || r.ContainsCell(0, 1) || r.ContainsCell(9, 4) {
+		t.Fatal("unexpected cell containment result")
+	}
+}
@@ -0,0 +1,146 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// gvizURL is the Google Visualization API query endpoint of a spreadsheet.
+const gvizURL = "https://docs.google.com/spreadsheets/d/%s/gviz/tq"
+
+// QueryColumn describes a column of a `QueryResult`.
+type QueryColumn struct {
+	// ID is the column's letter, e.g. "A", or the id of a computed column.
+	ID string `json:"id"`
+	// Label is the column's label, usually the header cell of the sheet, e.g. "Name" or "sum Age".
+	Label string `json:"label"`
+	// Type is the column's type: "string", "number", "boolean", "date", "datetime" or "timeofday".
+	Type string `json:"type"`
+}
+
+// QueryResult is the result of a Google Visualization API query.
+// Its `ValueRange` holds the data rows, without the header row,
+// so it can be passed on `DecodeValueRange` to bind the rows to structs.
+type QueryResult struct {
+	Columns []QueryColumn
+	ValueRange
+}
+
+// QueryError is returned when the Google Visualization API rejects a query, e.g. an invalid "tq".
+type QueryError struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Detail  string `json:"detailed_message"`
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *QueryError) Error() string {
+	msg := e.Message
+	if e.Detail != "" {
+		msg = e.Detail
+	}
+
+	return fmt.Sprintf("sheets: query: %s: %s", e.Reason, msg)
+}
+
+// Query runs the Google Visualization API query language "tq", e.g. "select A, sum(C) group by A",
+// against the sheet of "sheetGID" (its sheet ID), so the filtering and aggregation happen on the server side
+// instead of downloading whole sheets. The Client must be authorized with a Drive scope,
+// e.g. `ScopeDriveReadOnly`, unless the spreadsheet is public.
+//
+// Usage:
+//
+//	result, err := client.Query(ctx, spreadsheetID, 0, "select A, C where C > 18")
+//	var people []Person
+//	err = sheets.DecodeValueRange(&people, result.ValueRange)
+//
+// See https://developers.google.com/chart/interactive/docs/querylanguage.
+func (c *Client) Query(ctx context.Context, spreadsheetID string, sheetGID int64, tq string) (*QueryResult, error) {
+	url := fmt.Sprintf(gvizURL, spreadsheetID)
+	q := Query{
+		"tq":  []string{tq},
+		"gid": []string{strconv.FormatInt(sheetGID, 10)},
+	}
+
+	var b bytes.Buffer
+	if err := c.download(ctx, url, &b, q); err != nil {
+		return nil, c.opError(err, "gviz.query", spreadsheetID, "", 0)
+	}
+
+	result, err := c.parseQueryResponse(b.Bytes())
+	return result, c.opError(err, "gviz.query", spreadsheetID, "", 0)
+}
+
+type gvizResponse struct {
+	Status string       `json:"status"`
+	Errors []QueryError `json:"errors"`
+	Table  struct {
+		Cols []QueryColumn `json:"cols"`
+		Rows []struct {
+			C []*gvizCell `json:"c"`
+		} `json:"rows"`
+	} `json:"table"`
+}
+
+type gvizCell struct {
+	V interface{} `json:"v"`
+	F string      `json:"f"`
+}
+
+// parseQueryResponse parses a Google Visualization API response, e.g.
+// "/*O_o*/ google.visualization.Query.setResponse({...});" or the plain JSON object.
+func (c *Client) parseQueryResponse(body []byte) (*QueryResult, error) {
+	if start := bytes.IndexByte(body, '('); start >= 0 && bytes.IndexByte(body, '{') > start {
+		end := bytes.LastIndexByte(body, ')')
+		if end < start {
+			return nil, fmt.Errorf("sheets: query: invalid response")
+		}
+
+		body = body[start+1 : end]
+	}
+
+	var response gvizResponse
+	if err := c.codec().NewDecoder(bytes.NewReader(body)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("sheets: query: %w", err)
+	}
+
+	if response.Status == "error" {
+		if len(response.Errors) > 0 {
+			return nil, &response.Errors[0]
+		}
+
+		return nil, &QueryError{Reason: "unknown", Message: "query failed"}
+	}
+
+	result := &QueryResult{
+		Columns:    response.Table.Cols,
+		ValueRange: ValueRange{MajorDimension: Rows},
+	}
+
+	result.Values = make([][]interface{}, 0, len(response.Table.Rows))
+	for _, row := range response.Table.Rows {
+		values := make([]interface{}, len(row.C))
+		for i, cell := range row.C {
+			if cell == nil {
+				continue
+			}
+
+			values[i] = cell.V
+			if i < len(result.Columns) {
+				switch result.Columns[i].Type {
+				case "date", "datetime", "timeofday":
+					// The value is a "Date(2024,0,2)" literal, the formatted value is the one shown in the sheet.
+					if cell.F != "" {
+						values[i] = cell.F
+					}
+				}
+			}
+		}
+
+		result.Values = append(result.Values, values)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/spreadsheets/d/id/gviz/tq", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+
+		if r.URL.Query().Get("tq") == "invalid" {
+			w.Write([]byte(`/*O_o*/
+google.visualization.Query.setResponse({"version":"0.6","status":"error","errors":[{"reason":"invalid_query","message":"INVALID_QUERY","detailed_message":"Invalid query: PARSE_ERROR"}]});`))
+			return
+		}
+
+		if expected, got := "42", r.URL.Query().Get("gid"); expected != got {
+			t.Errorf("expected gid %q but got %q", expected, got)
+		}
+
+		w.Write([]byte(`/*O_o*/
+google.visualization.Query.setResponse({"version":"0.6","status":"ok","table":{"cols":[
+{"id":"A","label":"Name","type":"string"},{"id":"B","label":"Age","type":"number"},{"id":"C","label":"Birthday","type":"date"}],
+"rows":[{"c":[{"v":"makis"},{"v":27.0,"f":"27"},{"v":"Date(1996,0,2)","f":"1996-01-02"}]},{"c":[{"v":"efi"},null,null]}]}});`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	result, err := c.Query(ctx, "id", 42, "select A, B, C")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Age", result.Columns[1].Label; expected != got {
+		t.Fatalf("expected column label %q but got %q", expected, got)
+	}
+
+	expected := [][]interface{}{{"makis", 27.0, "1996-01-02"}, {"efi", nil, nil}}
+	if !reflect.DeepEqual(expected, result.Values) {
+		t.Fatalf("expected values %v but got %v", expected, result.Values)
+	}
+
+	var rows []testRowPointers
+	if err = DecodeValueRange(&rows, result.ValueRange); err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Birthday == nil || rows[0].Birthday.Year() != 1996 || rows[1].Age != nil {
+		t.Fatalf("unexpected decoded rows: %#v", rows)
+	}
+
+	_, err = c.Query(ctx, "id", 42, "invalid")
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || queryErr.Reason != "invalid_query" {
+		t.Fatalf("expected a query error but got: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package sheets
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AppendIdempotent appends "values" to a spreadsheet like `AppendSpreadsheet` does
+// but it retries the request, up to "maxRetries" times, when its outcome is unknown,
+// e.g. on network errors, timeouts and server errors.
+//
+// Before each retry it checks whether a previous attempt was already applied
+// by comparing a checksum of the "values" with a checksum of the last rows of the table,
+// so a retried append does not produce duplicate rows.
+// Include a client-generated request ID as a cell of each row (e.g. an order ID column)
+// to make sure that identical rows appended by different calls are not mistaken as a previous attempt.
+func (c *Client) AppendIdempotent(ctx context.Context, spreadsheetID string, values ValueRange, maxRetries int) (AppendValuesResponse, error) {
+	if len(values.Values) == 0 {
+		return AppendValuesResponse{SpreadsheetID: spreadsheetID}, nil
+	}
+
+	checksum := rowsChecksum(values.Values)
+
+	var (
+		response AppendValuesResponse
+		err      error
+	)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			applied, checkErr := c.appendApplied(ctx, spreadsheetID, values, checksum)
+			if checkErr == nil && applied {
+				return AppendValuesResponse{
+					SpreadsheetID: spreadsheetID,
+					Updates: UpdateValuesResponse{
+						SpreadsheetID: spreadsheetID,
+						UpdatedRows:   len(values.Values),
+					},
+				}, nil
+			}
+		}
+
+		response, err = c.AppendSpreadsheet(ctx, spreadsheetID, values)
+		if err == nil || attempt >= maxRetries || !isOutcomeUnknown(ctx, err) {
+			return response, err
+		}
+
+		timer := time.NewTimer(time.Duration(attempt+1) * 500 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, err
+		case <-timer.C:
+		}
+	}
+}
+
+// appendApplied reports whether the last rows of the table of "values.Range"
+// match the "checksum" of the values to be appended.
+// The "values.Range" may be a single cell, e.g. "Sheet1!A1", so the table's range is found first,
+// through an append request without values which does not write anything (see `NextRow`).
+// The cells are read as they were written, i.e. the formulas instead of their results
+// and the dates as text instead of serial numbers.
+func (c *Client) appendApplied(ctx context.Context, spreadsheetID string, values ValueRange, checksum [sha256.Size]byte) (bool, error) {
+	table, err := c.AppendSpreadsheet(ctx, spreadsheetID, ValueRange{Range: values.Range, MajorDimension: values.MajorDimension})
+	if err != nil {
+		return false, err
+	}
+
+	if table.TableRange == "" { // no table, the sheet is empty.
+		return false, nil
+	}
+
+	url := c.apiURL(spreadsheetValuesURL, spreadsheetID, table.TableRange)
+	q := Query{
+		"valueRenderOption":    []string{"FORMULA"},
+		"dateTimeRenderOption": []string{"FORMATTED_STRING"},
+	}
+
+	var payload ValueRange
+	if err = c.ReadJSON(ctx, http.MethodGet, url, nil, &payload, q); err != nil {
+		return false, err
+	}
+
+	n := len(values.Values)
+	if len(payload.Values) < n {
+		return false, nil
+	}
+
+	return rowsChecksum(payload.Values[len(payload.Values)-n:]) == checksum, nil
+}
+
+// isOutcomeUnknown reports whether a write request with the "err" result
+// may or may not have been applied by the server.
+func isOutcomeUnknown(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrInvalidRange) {
+		return false
+	}
+
+	var resErr *ResourceError
+	if errors.As(err, &resErr) {
+		return resErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return !errors.Is(err, ErrCircuitOpen)
+}
+
+// rowsChecksum returns a checksum of the text representation of the "rows".
+// Trailing empty cells are ignored, as the server does not return them.
+func rowsChecksum(rows [][]interface{}) [sha256.Size]byte {
+	h := sha256.New()
+	for _, row := range rows {
+		n := len(row)
+		for n > 0 && (row[n-1] == nil || row[n-1] == "") {
+			n--
+		}
+
+		for _, cell := range row[:n] {
+			if cell == nil {
+				cell = ""
+			}
+			fmt.Fprintf(h, "%v\x1f", cell)
+		}
+		h.Write([]byte{'\x1e'})
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,147 @@
+package sheets_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kataras/sheets"
+	"github.com/kataras/sheets/sheetstest"
+)
+
+// lostResponseTransport forwards the requests but drops the response of the first append one.
+type lostResponseTransport struct {
+	base http.RoundTripper
+	lost bool
+}
+
+func (t *lostResponseTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if err == nil && !t.lost && strings.HasSuffix(r.URL.Path, ":append") {
+		t.lost = true
+		resp.Body.Close()
+		return nil, errors.New("connection reset")
+	}
+
+	return resp, err
+}
+
+func TestAppendIdempotent(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.SetValues("test", "Sheet1", [][]interface{}{{"order-1", 10}})
+
+	c := sheets.NewClient(&lostResponseTransport{base: srv.Transport()})
+
+	_, err := c.AppendIdempotent(context.Background(), "test", sheets.ValueRange{
+		Values: [][]interface{}{{"order-2", 20}},
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]interface{}{{"order-1", 10}, {"order-2", float64(20)}}
+	if got := srv.Values("test", "Sheet1"); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected values %v but got %v", expected, got)
+	}
+}
+
+func TestAppendIdempotentSingleCellRange(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Orders")
+	srv.SetValues("test", "Orders", [][]interface{}{{"ID", "Amount"}, {"order-1", 10}})
+
+	c := sheets.NewClient(&lostResponseTransport{base: srv.Transport()})
+
+	_, err := c.AppendIdempotent(context.Background(), "test", sheets.ValueRange{
+		Range:  "Orders!A1",
+		Values: [][]interface{}{{"order-2", 20}, {"order-3", 30}},
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]interface{}{{"ID", "Amount"}, {"order-1", 10}, {"order-2", float64(20)}, {"order-3", float64(30)}}
+	if got := srv.Values("test", "Orders"); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected values %v but got %v", expected, got)
+	}
+}
+
+// renderTransport renders the formulas and the dates of the values responses as the Sheets API does,
+// unless they are requested as written, through the "FORMULA" value render option.
+type renderTransport struct {
+	base http.RoundTripper
+}
+
+func (t *renderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if err != nil || r.Method != http.MethodGet || r.URL.Query().Get("valueRenderOption") == "FORMULA" {
+		return resp, err
+	}
+
+	body := io.Reader(resp.Body)
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if body, err = gzip.NewReader(resp.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	var payload sheets.ValueRange
+	err = json.NewDecoder(body).Decode(&payload)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range payload.Values {
+		for j, cell := range row {
+			switch cell {
+			case "=SUM(B1:B2)":
+				row[j] = float64(30)
+			case "2024-01-01":
+				row[j] = float64(45292)
+			}
+		}
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	resp.ContentLength = int64(len(b))
+	return resp, nil
+}
+
+func TestAppendIdempotentFormulaAndDate(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.SetValues("test", "Sheet1", [][]interface{}{{"order-1", "2023-12-31", 10}})
+
+	c := sheets.NewClient(&lostResponseTransport{base: &renderTransport{base: srv.Transport()}})
+
+	_, err := c.AppendIdempotent(context.Background(), "test", sheets.ValueRange{
+		Range:  "Sheet1!A1",
+		Values: [][]interface{}{{"order-2", "2024-01-01", "=SUM(B1:B2)"}},
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := srv.Values("test", "Sheet1"); len(got) != 2 {
+		t.Fatalf("expected the retried append to be detected but got values %v", got)
+	}
+}
@@ -0,0 +1,249 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// ImageMode is the sizing mode of an in-cell image, see `CellImage`.
+type ImageMode int
+
+// The image modes of the IMAGE formula.
+const (
+	// ImageFit resizes the image to fit inside the cell, keeping its aspect ratio.
+	ImageFit ImageMode = 1
+	// ImageStretch stretches the image to fill the cell.
+	ImageStretch ImageMode = 2
+	// ImageOriginal keeps the original size of the image, it may be cropped.
+	ImageOriginal ImageMode = 3
+	// ImageCustom sizes the image to the `CellImage.Height` and `CellImage.Width`.
+	ImageCustom ImageMode = 4
+)
+
+// CellImage is an in-cell image of the IMAGE formula, e.g. `=IMAGE("https://example.com/p.png")`.
+// Images inserted through the Sheets UI as cell images are not exposed by the API, only the formula ones.
+//
+// See `Client.ReadImages` and `Client.InsertImage` methods.
+type CellImage struct {
+	// Cell is the address of the image's cell, it's set by `Client.ReadImages`.
+	Cell Cell
+	// URL is the public URL of the image.
+	URL string
+	// Mode is the sizing mode, zero means `ImageFit`.
+	Mode ImageMode
+	// Height and Width, in pixels, are used by the `ImageCustom` mode.
+	Height, Width int
+}
+
+// Formula returns the IMAGE formula of the image.
+func (img CellImage) Formula() string {
+	var b strings.Builder
+	b.WriteString(`=IMAGE("`)
+	b.WriteString(strings.ReplaceAll(img.URL, `"`, `""`))
+	b.WriteString(`"`)
+
+	switch {
+	case img.Mode == ImageCustom:
+		fmt.Fprintf(&b, ", %d, %d, %d", img.Mode, img.Height, img.Width)
+	case img.Mode > ImageFit:
+		fmt.Fprintf(&b, ", %d", img.Mode)
+	}
+
+	b.WriteString(")")
+	return b.String()
+}
+
+// ParseImageFormula parses an IMAGE "formula", e.g. `=IMAGE("https://example.com/p.png", 4, 50, 100)`.
+// It reports false if "formula" is not an IMAGE formula of a literal URL.
+func ParseImageFormula(formula string) (CellImage, bool) {
+	const prefix = "=IMAGE("
+
+	formula = strings.TrimSpace(formula)
+	if len(formula) < len(prefix) || !strings.EqualFold(formula[:len(prefix)], prefix) || !strings.HasSuffix(formula, ")") {
+		return CellImage{}, false
+	}
+
+	args := strings.TrimSpace(formula[len(prefix) : len(formula)-1])
+	if !strings.HasPrefix(args, `"`) {
+		return CellImage{}, false
+	}
+
+	// The URL is a quoted string, a double quote is escaped by doubling it.
+	var (
+		img CellImage
+		end = -1
+	)
+	for i := 1; i < len(args); i++ {
+		if args[i] != '"' {
+			continue
+		}
+		if i+1 < len(args) && args[i+1] == '"' {
+			i++
+			continue
+		}
+
+		end = i
+		break
+	}
+	if end == -1 {
+		return CellImage{}, false
+	}
+
+	img.URL = strings.ReplaceAll(args[1:end], `""`, `"`)
+
+	rest := strings.TrimSpace(args[end+1:])
+	if rest == "" {
+		return img, true
+	}
+	if !strings.HasPrefix(rest, ",") {
+		return CellImage{}, false
+	}
+
+	var numbers []int
+	for _, arg := range strings.Split(rest[1:], ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			return CellImage{}, false
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	img.Mode = ImageMode(numbers[0])
+	if len(numbers) > 1 {
+		img.Height = numbers[1]
+	}
+	if len(numbers) > 2 {
+		img.Width = numbers[2]
+	}
+
+	return img, true
+}
+
+// ReadImages returns the in-cell images of the IMAGE formulas of the "dataRange" of the spreadsheet,
+// in row-major order, e.g. the product images of a catalog sheet.
+func (c *Client) ReadImages(ctx context.Context, spreadsheetID, dataRange string) ([]CellImage, error) {
+	if err := ValidateRange(dataRange); err != nil {
+		return nil, c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+
+	// A named range has no origin, its cells are relative to "A1".
+	_, origin, _ := ParseA1(dataRange)
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/get
+	url := c.apiURL(spreadsheetValuesURL, spreadsheetID, dataRange)
+
+	var valueRange ValueRange
+	err := c.ReadJSON(ctx, http.MethodGet, url, nil, &valueRange, Query{"valueRenderOption": []string{"FORMULA"}})
+	if err != nil {
+		return nil, c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+
+	var images []CellImage
+	for i, row := range valueRange.Values {
+		for j, value := range row {
+			formula, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			if img, ok := ParseImageFormula(formula); ok {
+				img.Cell = Cell{Row: int(origin.StartRowIndex) + i, Col: int(origin.StartColumnIndex) + j}
+				images = append(images, img)
+			}
+		}
+	}
+
+	return images, nil
+}
+
+const driveUploadURL = "/upload/drive/v3/files"
+
+// contentType is a `RequestOption` which sets the Content-Type header of a request.
+type contentType string
+
+func (t contentType) Apply(r *http.Request) {
+	r.Header.Set("Content-Type", string(t))
+}
+
+// InsertImage uploads the "image" of "mimeType" (e.g. "image/png") to Drive as "name",
+// shares it with anyone with the link, as the IMAGE formula requires a public URL,
+// and writes its IMAGE formula to the "cell" of the spreadsheet, e.g. "Products!D2".
+// A cell without a sheet title refers to the first sheet.
+// The Client must be authorized with a Drive scope, e.g. `ScopeDriveFile`.
+// It returns the uploaded image's Drive metadata.
+func (c *Client) InsertImage(ctx context.Context, spreadsheetID, cell string, image io.Reader, name, mimeType string) (*DriveFile, error) {
+	sheetTitle, r, err := ParseA1(cell)
+	if err != nil {
+		return nil, c.opError(err, "spreadsheets.batchUpdate/updateCells", spreadsheetID, cell, 0)
+	}
+
+	sheetID, err := c.sheetID(ctx, spreadsheetID, sheetTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	// https://developers.google.com/drive/api/guides/manage-uploads#multipart
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	metadata, err := json.Marshal(driveFileRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return nil, err
+	}
+	part.Write(metadata)
+
+	part, err = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {mimeType}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(part, image); err != nil {
+		return nil, err
+	}
+
+	if err = mw.Close(); err != nil {
+		return nil, err
+	}
+
+	q := driveQuery(driveFileFields)
+	q["uploadType"] = []string{"multipart"}
+
+	file := new(DriveFile)
+	url := "https://www." + c.universeDomain() + driveUploadURL
+	err = c.readJSON(ctx, http.MethodPost, url, body.Bytes(), file, q, contentType("multipart/related; boundary="+mw.Boundary()))
+	if err != nil {
+		return nil, c.opError(err, "files.create", "", "", 0)
+	}
+
+	// https://developers.google.com/drive/api/reference/rest/v3/permissions/create
+	var permission Permission
+	err = c.ReadJSON(ctx, http.MethodPost, c.driveURL(drivePermissionsURL, file.ID), Permission{Type: "anyone", Role: RoleReader}, &permission, driveQuery(permissionFields))
+	if err != nil {
+		return file, c.opError(err, "permissions.create", file.ID, "", 0)
+	}
+
+	img := CellImage{URL: "https://drive.google.com/uc?export=view&id=" + file.ID}
+	formula := img.Formula()
+
+	_, err = c.UpdateCells(ctx, spreadsheetID, sheetID, Cell{Row: int(r.StartRowIndex), Col: int(r.StartColumnIndex)}, [][]CellData{
+		{{UserEnteredValue: &ExtendedValue{FormulaValue: &formula}}},
+	}, "userEnteredValue")
+	if err != nil {
+		return file, err
+	}
+
+	return file, nil
+}
@@ -0,0 +1,128 @@
+package sheets
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImageFormula(t *testing.T) {
+	tests := []struct {
+		img     CellImage
+		formula string
+	}{
+		{CellImage{URL: "https://example.com/p.png"}, `=IMAGE("https://example.com/p.png")`},
+		{CellImage{URL: `https://example.com/"q".png`, Mode: ImageStretch}, `=IMAGE("https://example.com/""q"".png", 2)`},
+		{CellImage{URL: "https://example.com/p.png", Mode: ImageCustom, Height: 50, Width: 100}, `=IMAGE("https://example.com/p.png", 4, 50, 100)`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.img.Formula(); tt.formula != got {
+			t.Fatalf("expected formula %s but got %s", tt.formula, got)
+		}
+
+		img, ok := ParseImageFormula(tt.formula)
+		if !ok || !reflect.DeepEqual(tt.img, img) {
+			t.Fatalf("expected %s to be parsed as %#v but got %#v", tt.formula, tt.img, img)
+		}
+	}
+
+	for _, formula := range []string{"=SUM(A1:A2)", "=IMAGE(A1)", `=IMAGE("x", mode)`, "text"} {
+		if _, ok := ParseImageFormula(formula); ok {
+			t.Fatalf("expected %s to not be parsed as an image", formula)
+		}
+	}
+}
+
+func TestClientReadImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "FORMULA", r.URL.Query().Get("valueRenderOption"); expected != got {
+			t.Errorf("expected value render option %q but got %q", expected, got)
+		}
+
+		w.Write([]byte(`{"range":"Products!B2:C3","values":[["Chair","=image(\"https://example.com/chair.png\")"],["Desk",1]]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	images, err := c.ReadImages(context.Background(), "id", "Products!B2:C3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []CellImage{{Cell: Cell{Row: 1, Col: 2}, URL: "https://example.com/chair.png"}}
+	if !reflect.DeepEqual(expected, images) {
+		t.Fatalf("expected images %#v but got %#v", expected, images)
+	}
+}
+
+func TestClientInsertImage(t *testing.T) {
+	var updateCells string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4/spreadsheets/id":
+			w.Write([]byte(`{"spreadsheetId":"id","sheets":[{"properties":{"sheetId":3,"title":"Products"}}]}`))
+		case "/upload/drive/v3/files":
+			if expected, got := "multipart", r.URL.Query().Get("uploadType"); expected != got {
+				t.Errorf("expected upload type %q but got %q", expected, got)
+			}
+
+			_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mr := multipart.NewReader(r.Body, params["boundary"])
+			var parts []string
+			for {
+				part, err := mr.NextPart()
+				if err != nil {
+					break
+				}
+				b, _ := io.ReadAll(part)
+				parts = append(parts, part.Header.Get("Content-Type")+" "+string(b))
+			}
+
+			expected := []string{`application/json; charset=UTF-8 {"name":"chair.png"}`, "image/png PNG"}
+			if !reflect.DeepEqual(expected, parts) {
+				t.Errorf("expected parts %q but got %q", expected, parts)
+			}
+
+			w.Write([]byte(`{"id":"img","name":"chair.png"}`))
+		case "/drive/v3/files/img/permissions":
+			b, _ := io.ReadAll(r.Body)
+			if expected, got := `{"type":"anyone","role":"reader"}`, strings.TrimSpace(string(b)); expected != got {
+				t.Errorf("expected permission %s but got %s", expected, got)
+			}
+			w.Write([]byte(`{"id":"anyoneWithLink"}`))
+		case "/v4/spreadsheets/id:batchUpdate":
+			b, _ := io.ReadAll(r.Body)
+			updateCells = string(b)
+			w.Write([]byte(`{"spreadsheetId":"id"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	file, err := c.InsertImage(context.Background(), "id", "Products!D2", strings.NewReader("PNG"), "chair.png", "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "img", file.ID; expected != got {
+		t.Fatalf("expected file ID %q but got %q", expected, got)
+	}
+
+	expected := `"start":{"sheetId":3,"rowIndex":1,"columnIndex":3},"rows":[{"values":[{"userEnteredValue":{"formulaValue":"=IMAGE(\"https://drive.google.com/uc?export=view\u0026id=img\")"}}]}],"fields":"userEnteredValue"`
+	if !strings.Contains(updateCells, expected) {
+		t.Fatalf("expected update cells request to contain:\n%s\nbut got:\n%s", expected, updateCells)
+	}
+}
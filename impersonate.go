@@ -0,0 +1,121 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// ScopeCloudPlatform is the oauth2 scope required by the source credentials of `Impersonate`.
+const ScopeCloudPlatform = "https://www.googleapis.com/auth/cloud-platform"
+
+var iamCredentialsURL = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+
+// Impersonate is an oauth2 authentication function which
+// can be passed on the `New` package-level function.
+//
+// It generates short-lived access tokens of the "targetServiceAccount" (e.g. the sheet-owning service account)
+// through the IAM Credentials API, so a low-privilege runtime identity can act as it without distributing its key.
+// The "source" is the authenticated RoundTripper of the runtime identity which must have the
+// "Service Account Token Creator" role on the target (or the last delegate), if nil then the
+// Application Default Credentials are used. The optional "delegates" is the chain of service accounts
+// which grant the token creator role to the next one of the chain.
+//
+// The "scopes" default to `ScopeReadOnly`.
+func Impersonate(ctx context.Context, source http.RoundTripper, targetServiceAccount string, scopes []string, delegates ...string) (http.RoundTripper, error) {
+	if targetServiceAccount == "" {
+		return nil, fmt.Errorf("impersonate: empty target service account")
+	}
+
+	if source == nil {
+		client, err := google.DefaultClient(ctx, ScopeCloudPlatform)
+		if err != nil {
+			return nil, fmt.Errorf("impersonate: unable to find default credentials: %w", err)
+		}
+
+		source = client.Transport
+	}
+
+	if len(scopes) == 0 {
+		scopes = []string{ScopeReadOnly}
+	}
+
+	ts := &impersonateTokenSource{
+		ctx:       ctx,
+		client:    &http.Client{Transport: source},
+		target:    targetServiceAccount,
+		scopes:    scopes,
+		delegates: delegates,
+	}
+
+	// The token requests are sent through the "source" transport.
+	return newCredentialsTransport(ctx, func(context.Context) oauth2.TokenSource {
+		return oauth2.ReuseTokenSource(nil, ts)
+	}, ""), nil
+}
+
+type impersonateTokenSource struct {
+	ctx       context.Context
+	client    *http.Client
+	target    string
+	scopes    []string
+	delegates []string
+}
+
+// Token implements the `oauth2.TokenSource` interface.
+func (ts *impersonateTokenSource) Token() (*oauth2.Token, error) {
+	// https://cloud.google.com/iam/docs/reference/credentials/rest/v1/projects.serviceAccounts/generateAccessToken
+	payload := struct {
+		Delegates []string `json:"delegates,omitempty"`
+		Scope     []string `json:"scope"`
+		Lifetime  string   `json:"lifetime"`
+	}{
+		Scope:    ts.scopes,
+		Lifetime: "3600s",
+	}
+
+	for _, delegate := range ts.delegates {
+		payload.Delegates = append(payload.Delegates, "projects/-/serviceAccounts/"+delegate)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ts.ctx, http.MethodPost, fmt.Sprintf(iamCredentialsURL, ts.target), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("impersonate: unable to generate access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("impersonate: unable to generate access token", resp)
+	}
+
+	var result struct {
+		AccessToken string    `json:"accessToken"`
+		ExpireTime  time.Time `json:"expireTime"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("impersonate: unable to parse access token: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: result.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      result.ExpireTime,
+	}, nil
+}
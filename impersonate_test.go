@@ -0,0 +1,65 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImpersonate(t *testing.T) {
+	var tokenRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":generateAccessToken") {
+			tokenRequests++
+
+			var payload struct {
+				Delegates []string `json:"delegates"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if expected, got := "projects/-/serviceAccounts/delegate@test", strings.Join(payload.Delegates, ","); expected != got {
+				t.Errorf("expected delegates %q but got %q", expected, got)
+			}
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"accessToken": "impersonated",
+				"expireTime":  time.Now().Add(time.Hour),
+			})
+			return
+		}
+
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	defer func(original string) { iamCredentialsURL = original }(iamCredentialsURL)
+	iamCredentialsURL = srv.URL + "/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+
+	transport, err := Impersonate(context.Background(), http.DefaultTransport, "owner@test", nil, "delegate@test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: transport}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := new(strings.Builder)
+		_, _ = io.Copy(b, resp.Body)
+		resp.Body.Close()
+
+		if expected, got := "Bearer impersonated", b.String(); expected != got {
+			t.Fatalf("expected authorization %q but got %q", expected, got)
+		}
+	}
+
+	if tokenRequests != 1 {
+		t.Fatalf("expected the token to be reused but got %d token requests", tokenRequests)
+	}
+}
@@ -0,0 +1,87 @@
+package sheets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultInfoCacheTTL is the default `Client.InfoCacheTTL` value.
+const DefaultInfoCacheTTL = 5 * time.Minute
+
+// infoCache is a cache of spreadsheets metadata, see `Client.SpreadsheetInfoCached`.
+// Its zero value is ready to use.
+type infoCache struct {
+	mu      sync.Mutex
+	entries map[string]infoCacheEntry
+}
+
+type infoCacheEntry struct {
+	info    *Spreadsheet
+	expires time.Time
+}
+
+func (c *infoCache) get(spreadsheetID string) (*Spreadsheet, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[spreadsheetID]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, spreadsheetID)
+		return nil, false
+	}
+
+	return entry.info, true
+}
+
+func (c *infoCache) set(spreadsheetID string, info *Spreadsheet, ttl time.Duration) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]infoCacheEntry)
+	}
+	c.entries[spreadsheetID] = infoCacheEntry{info: info, expires: time.Now().Add(ttl)}
+	c.mu.Unlock()
+}
+
+func (c *infoCache) delete(spreadsheetID string) {
+	c.mu.Lock()
+	delete(c.entries, spreadsheetID)
+	c.mu.Unlock()
+}
+
+// SpreadsheetInfoCached returns the metadata of a spreadsheet, like `GetSpreadsheetInfo` does,
+// but it caches them for the `InfoCacheTTL` so resolving sheet titles and IDs does not cost a request every time.
+// The structural batch updates (e.g. `ResizeSheet`, `DeleteRows`, `Restore`) sent by this Client invalidate the cached entry,
+// changes made by others are seen after the TTL, or earlier with `InvalidateSpreadsheetInfo`.
+// The callers share the returned value, so they should not modify it.
+func (c *Client) SpreadsheetInfoCached(ctx context.Context, spreadsheetID string) (*Spreadsheet, error) {
+	if sd, ok := c.infoCache.get(spreadsheetID); ok {
+		return sd, nil
+	}
+
+	sd, err := c.GetSpreadsheetInfo(ctx, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.InfoCacheTTL
+	if ttl == 0 {
+		ttl = DefaultInfoCacheTTL
+	}
+
+	if ttl > 0 {
+		c.infoCache.set(spreadsheetID, sd, ttl)
+	}
+
+	return sd, nil
+}
+
+// InvalidateSpreadsheetInfo drops the cached metadata of a spreadsheet,
+// the next `SpreadsheetInfoCached` call fetches them again.
+func (c *Client) InvalidateSpreadsheetInfo(spreadsheetID string) {
+	c.infoCache.delete(spreadsheetID)
+}
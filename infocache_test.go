@@ -0,0 +1,71 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientSpreadsheetInfoCached(t *testing.T) {
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":batchUpdate") {
+			w.Write([]byte(`{"spreadsheetId":"id"}`))
+			return
+		}
+
+		gets++
+		w.Write([]byte(`{"spreadsheetId":"id","sheets":[{"properties":{"sheetId":42,"title":"Users"}}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		sd, err := c.SpreadsheetInfoCached(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := sd.GetSheet("Users"); !ok {
+			t.Fatalf("expected the Users sheet but got %#v", sd.Sheets)
+		}
+	}
+
+	if expected := 1; gets != expected {
+		t.Fatalf("expected %d spreadsheet requests but got %d", expected, gets)
+	}
+
+	if _, err := c.ResizeSheet(ctx, "id", 42, 100, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.sheetID(ctx, "id", "Users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := 2; gets != expected {
+		t.Fatalf("expected the structural update to invalidate the cache, %d spreadsheet requests but got %d", expected, gets)
+	}
+
+	// Missing sheets are looked up again.
+	if _, err := c.sheetID(ctx, "id", "Missing"); err == nil {
+		t.Fatal("expected a not found error")
+	}
+
+	if expected := 3; gets != expected {
+		t.Fatalf("expected a missing sheet to refresh the cache, %d spreadsheet requests but got %d", expected, gets)
+	}
+
+	c.InfoCacheTTL = -1
+	c.InvalidateSpreadsheetInfo("id")
+	c.SpreadsheetInfoCached(ctx, "id")
+	c.SpreadsheetInfoCached(ctx, "id")
+
+	if expected := 5; gets != expected {
+		t.Fatalf("expected a negative TTL to disable the cache, %d spreadsheet requests but got %d", expected, gets)
+	}
+}
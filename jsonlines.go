@@ -0,0 +1,72 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// WriteJSONLines decodes each row of "rangeValues" to a value of the "elem" struct type (e.g. User{} or &User{})
+// and writes it to "w" as a JSON object, one per line (JSON Lines), e.g. to feed BigQuery loads or jq pipelines.
+// Rows are written as they are decoded, a single struct value is reused, so the decoded rows are never buffered.
+// With `Decoder.CollectErrors` the rows that fail to be decoded are skipped and a `DecodeErrors` is returned at the end.
+func (d *Decoder) WriteJSONLines(w io.Writer, elem interface{}, rangeValues ...ValueRange) error {
+	typ := reflect.TypeOf(elem)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("not a struct")
+	}
+
+	var (
+		errs DecodeErrors
+		meta = d.metadata(typ)
+		enc  = json.NewEncoder(w)
+		v    = reflect.New(typ)
+		zero = reflect.Zero(typ)
+	)
+
+	for _, rangeValue := range rangeValues {
+		for i, row := range rangeValue.Values {
+			v.Elem().Set(zero)
+			if err := d.decodeValue(rangeValue, i, row, meta, v); err != nil {
+				if !d.CollectErrors {
+					return err
+				}
+
+				errs = append(errs, asDecodeError(rangeValue, i, err))
+				continue
+			}
+
+			if err := enc.Encode(v.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ExportJSONLines reads the "dataRanges" of the spreadsheet and writes each row, decoded to a value
+// of the "elem" struct type, to "w" as a JSON object per line. It uses the `Client.Decoder`, if any.
+// See `Decoder.WriteJSONLines` method.
+func (c *Client) ExportJSONLines(ctx context.Context, w io.Writer, elem interface{}, spreadsheetID string, dataRanges ...string) error {
+	valueRanges, err := c.Range(ctx, spreadsheetID, dataRanges...)
+	if err != nil {
+		return err
+	}
+
+	d := c.Decoder
+	if d == nil {
+		d = new(Decoder)
+	}
+
+	return d.WriteJSONLines(w, elem, valueRanges...)
+}
@@ -0,0 +1,45 @@
+package sheets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecoderWriteJSONLines(t *testing.T) {
+	rangeValues := []ValueRange{
+		{Range: "Sheet1!A1:B2", Values: [][]interface{}{{"makis", "27"}, {"giwrgos"}}},
+		{Range: "Sheet1!A3:B3", Values: [][]interface{}{{"efi", 30.0}}},
+	}
+
+	var b strings.Builder
+	if err := new(Decoder).WriteJSONLines(&b, &testRowFailingDecoder{}, rangeValues...); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second row has no age, it should not keep the age of the previous row.
+	expected := `{"Name":"makis","Age":27}
+{"Name":"giwrgos","Age":0}
+{"Name":"efi","Age":30}
+`
+	if got := b.String(); expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	b.Reset()
+	rangeValues[0].Values[0][1] = "n/a"
+	d := &Decoder{CollectErrors: true}
+	err := d.WriteJSONLines(&b, testRowFailingDecoder{}, rangeValues...)
+
+	var decodeErrs DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs) != 1 {
+		t.Fatalf("expected one decode error but got: %v", err)
+	}
+	if expected, got := 2, strings.Count(b.String(), "\n"); expected != got {
+		t.Fatalf("expected %d lines but got %d", expected, got)
+	}
+
+	if err := new(Decoder).WriteJSONLines(&b, []testRow{}); err == nil {
+		t.Fatal("expected an error for a non struct type")
+	}
+}
@@ -0,0 +1,103 @@
+package sheets
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ClientPool holds multiple authenticated Clients (e.g. different service accounts or user tokens)
+// and routes requests to them by key, e.g. per-customer credentials.
+// It can also spread the requests to all of its Clients in round-robin fashion,
+// so that their quota is used evenly.
+//
+// It is safe for concurrent use.
+type ClientPool struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+	keys    []string // in insertion order.
+
+	next uint64
+}
+
+// NewClientPool returns a new empty ClientPool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{clients: make(map[string]*Client)}
+}
+
+// Add registers or replaces the Client of "key".
+func (p *ClientPool) Add(key string, c *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.clients[key]; !ok {
+		p.keys = append(p.keys, key)
+	}
+	p.clients[key] = c
+}
+
+// Remove removes the Client of "key".
+func (p *ClientPool) Remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.clients[key]; !ok {
+		return
+	}
+
+	delete(p.clients, key)
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns the Client of "key".
+func (p *ClientPool) Get(key string) (*Client, bool) {
+	p.mu.RLock()
+	c, ok := p.clients[key]
+	p.mu.RUnlock()
+	return c, ok
+}
+
+// Lookup is like `Get` but it returns an error if the "key" is not registered.
+func (p *ClientPool) Lookup(key string) (*Client, error) {
+	c, ok := p.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("client pool: no client for key %q", key)
+	}
+
+	return c, nil
+}
+
+// Next returns the next Client in round-robin order.
+// It returns nil if the pool is empty.
+func (p *ClientPool) Next() *Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.keys) == 0 {
+		return nil
+	}
+
+	i := atomic.AddUint64(&p.next, 1) - 1
+	return p.clients[p.keys[i%uint64(len(p.keys))]]
+}
+
+// Keys returns the registered keys in insertion order.
+func (p *ClientPool) Keys() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]string(nil), p.keys...)
+}
+
+// Len returns the number of the registered Clients.
+func (p *ClientPool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.keys)
+}
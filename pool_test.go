@@ -0,0 +1,38 @@
+package sheets
+
+import "testing"
+
+func TestClientPool(t *testing.T) {
+	p := NewClientPool()
+	if p.Next() != nil {
+		t.Fatal("expected nil client from an empty pool")
+	}
+
+	a, b, c := NewClient(nil), NewClient(nil), NewClient(nil)
+	p.Add("a", a)
+	p.Add("b", b)
+	p.Add("c", c)
+
+	if got, ok := p.Get("b"); !ok || got != b {
+		t.Fatal("expected client b")
+	}
+
+	if _, err := p.Lookup("missing"); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+
+	expected := []*Client{a, b, c, a, b}
+	for i, e := range expected {
+		if got := p.Next(); got != e {
+			t.Fatalf("[%d] unexpected round-robin client", i)
+		}
+	}
+
+	p.Remove("b")
+	if expected, got := 2, p.Len(); expected != got {
+		t.Fatalf("expected %d clients but got %d", expected, got)
+	}
+	if _, ok := p.Get("b"); ok {
+		t.Fatal("expected client b to be removed")
+	}
+}
@@ -0,0 +1,167 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ReadColumns binds the data rows of the sheet with "sheetTitle" to "dest", like `ReadSpreadsheet` does,
+// but it fetches only the columns mapped to the struct fields, so the bandwidth and the decoding work
+// scale with the struct instead of the sheet's width, e.g. a struct of 4 fields of a 30 columns sheet.
+//
+// The first row of the sheet is the header row, the struct fields are mapped to the columns by their header names,
+// so the columns can be in any order (see `Client.Sync` method too). The mapped columns are fetched
+// as one narrow range per group of adjacent columns, in a single batch request.
+// It returns an error which matches the `ErrNotFound` if the header row does not contain a struct header.
+func (c *Client) ReadColumns(ctx context.Context, dest interface{}, spreadsheetID, sheetTitle string) error {
+	typ := reflect.TypeOf(dest)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("sheets: read columns: not a pointer to a struct or a slice of structs")
+	}
+
+	decoder := c.Decoder
+	if decoder == nil {
+		decoder = new(Decoder)
+	}
+
+	headers := decoder.metadata(typ).headers
+	if len(headers) == 0 {
+		return nil
+	}
+
+	headerRow, err := c.Range(ctx, spreadsheetID, WholeRow(sheetTitle, 1))
+	if err != nil {
+		return err
+	}
+
+	// columns maps the struct headers to the (zero-based) columns of the sheet.
+	columns := make([]int, len(headers))
+	for i, h := range headers {
+		columns[i] = -1
+		if len(headerRow) > 0 && len(headerRow[0].Values) > 0 {
+			for j, cell := range headerRow[0].Values[0] {
+				if strings.TrimSpace(formatCell(cell)) == h.Name {
+					columns[i] = j
+					break
+				}
+			}
+		}
+
+		if columns[i] < 0 {
+			return fmt.Errorf("sheets: header %q not found in sheet %q: %w", h.Name, sheetTitle, ErrNotFound)
+		}
+	}
+
+	groups := columnGroups(columns)
+	dataRanges := make([]string, len(groups))
+	for g, group := range groups {
+		r := GridRange{StartRowIndex: 1, StartColumnIndex: int64(group[0]), EndColumnIndex: int64(group[1])}
+		dataRanges[g] = r.A1(sheetTitle)
+	}
+
+	valueRanges, err := c.Range(ctx, spreadsheetID, dataRanges...)
+	if err != nil {
+		return err
+	}
+
+	if len(valueRanges) != len(groups) {
+		return fmt.Errorf("sheets: read columns: expected %d value ranges but got %d", len(groups), len(valueRanges))
+	}
+
+	dataRange := QuoteSheetTitle(sheetTitle) + "!A2"
+	err = decoder.Decode(dest, ValueRange{
+		Range:          dataRange,
+		MajorDimension: Rows,
+		Values:         projectRows(columns, groups, valueRanges),
+	})
+
+	// Report the sheet cells of the decode errors, instead of the cells of the projected rows.
+	var decodeErrs DecodeErrors
+	if errors.As(err, &decodeErrs) {
+		for _, decodeErr := range decodeErrs {
+			decodeErr.Cell = projectedCell(dataRange, columns, decodeErr)
+		}
+	} else if decodeErr := new(DecodeError); errors.As(err, &decodeErr) {
+		decodeErr.Cell = projectedCell(dataRange, columns, decodeErr)
+	}
+
+	return err
+}
+
+// columnGroups returns the ranges, [start, end), of the adjacent "columns".
+func columnGroups(columns []int) [][2]int {
+	sorted := append([]int(nil), columns...)
+	sort.Ints(sorted)
+
+	var groups [][2]int
+	for _, col := range sorted {
+		if n := len(groups); n > 0 && col <= groups[n-1][1] {
+			if col == groups[n-1][1] {
+				groups[n-1][1]++
+			}
+			continue
+		}
+
+		groups = append(groups, [2]int{col, col + 1})
+	}
+
+	return groups
+}
+
+// projectRows merges the "valueRanges" of the column "groups" to rows of the cells of the "columns", in order.
+func projectRows(columns []int, groups [][2]int, valueRanges []ValueRange) [][]interface{} {
+	n := 0
+	for _, valueRange := range valueRanges {
+		if len(valueRange.Values) > n {
+			n = len(valueRange.Values)
+		}
+	}
+
+	// group holds the index of the group of each column.
+	group := make([]int, len(columns))
+	for i, col := range columns {
+		group[i] = sort.Search(len(groups), func(g int) bool { return groups[g][1] > col })
+	}
+
+	rows := make([][]interface{}, n)
+	cells := make([]interface{}, n*len(columns)) // the rows share a single backing array.
+	for r := range rows {
+		row := cells[r*len(columns) : (r+1)*len(columns) : (r+1)*len(columns)]
+
+		length := 0
+		for i, col := range columns {
+			g := group[i]
+			var cell interface{}
+			if values := valueRanges[g].Values; r < len(values) {
+				cell = cellAt(values[r], col-groups[g][0])
+			}
+
+			if cell == nil {
+				cell = "" // an empty cell, as the API sends them before the last non-empty one.
+			} else if cell != "" {
+				length = i + 1
+			}
+			row[i] = cell
+		}
+
+		rows[r] = row[:length] // trailing empty cells are omitted, as the API does.
+	}
+
+	return rows
+}
+
+// projectedCell returns the sheet cell of the "decodeErr" of the projected rows of "dataRange".
+func projectedCell(dataRange string, columns []int, decodeErr *DecodeError) string {
+	if decodeErr.Column < 0 || decodeErr.Column >= len(columns) {
+		return decodeErr.Cell
+	}
+
+	return cellOf(dataRange, decodeErr.Row, columns[decodeErr.Column])
+}
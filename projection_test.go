@@ -0,0 +1,100 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientReadColumns(t *testing.T) {
+	columns := map[string][][]interface{}{
+		"Users!1:1":  {{"ID", "Name", "Notes", "Age", "Email"}},
+		"Users!B2:B": {{"makis"}, {"giwrgos"}, {}, {"efi"}},
+		"Users!D2:E": {{27.0, "makis@example.com"}, {"n/a"}, {}, {30.0, "efi@example.com"}},
+		"Users!D2:D": {{27.0}, {"n/a"}, {}, {30.0}},
+	}
+
+	var batchRanges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/values:batchGet") {
+			batchRanges = r.URL.Query()["ranges"]
+
+			var payload struct {
+				ValueRanges []ValueRange `json:"valueRanges"`
+			}
+			for _, dataRange := range batchRanges {
+				payload.ValueRanges = append(payload.ValueRanges, ValueRange{Range: dataRange, Values: columns[dataRange]})
+			}
+			json.NewEncoder(w).Encode(payload)
+			return
+		}
+
+		dataRange := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/id/values/")
+		json.NewEncoder(w).Encode(ValueRange{Range: dataRange, Values: columns[dataRange]})
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	type user struct {
+		Email string `sheets:"Email"`
+		Name  string `sheets:"Name"`
+		Age   int    `sheets:"Age"`
+	}
+
+	var users []user
+	if err := c.ReadColumns(context.Background(), &users, "id", "Users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"Users!B2:B", "Users!D2:E"}; !reflect.DeepEqual(expected, batchRanges) {
+		t.Fatalf("expected the mapped columns ranges %q but got %q", expected, batchRanges)
+	}
+
+	expected := []user{
+		{Email: "makis@example.com", Name: "makis", Age: 27},
+		{Name: "giwrgos"},
+		{},
+		{Email: "efi@example.com", Name: "efi", Age: 30},
+	}
+	if !reflect.DeepEqual(expected, users) {
+		t.Fatalf("expected %#v but got %#v", expected, users)
+	}
+
+	// The decode errors report the sheet cells.
+	c.Decoder = &Decoder{CollectErrors: true}
+	var failing []testRowFailingDecoder
+	err := c.ReadColumns(context.Background(), &failing, "id", "Users")
+
+	var decodeErrs DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs) != 1 {
+		t.Fatalf("expected a single decode error but got %v", err)
+	}
+	if expected, got := "Users!D3", decodeErrs[0].Cell; expected != got {
+		t.Fatalf("expected the decode error of the sheet cell %q but got %q", expected, got)
+	}
+	if expected := []string{"Users!B2:B", "Users!D2:D"}; !reflect.DeepEqual(expected, batchRanges) {
+		t.Fatalf("expected the mapped columns ranges %q but got %q", expected, batchRanges)
+	}
+	if len(failing) != 3 {
+		t.Fatalf("expected the failed row to be skipped but got %v", failing)
+	}
+
+	type missing struct {
+		Phone string `sheets:"Phone"`
+	}
+	if err = c.ReadColumns(context.Background(), &[]missing{}, "id", "Users"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a not found error but got %v", err)
+	}
+}
+
+func TestColumnGroups(t *testing.T) {
+	if expected, got := [][2]int{{0, 3}, {5, 6}, {8, 10}}, columnGroups([]int{9, 1, 5, 0, 8, 2}); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected groups %v but got %v", expected, got)
+	}
+}
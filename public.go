@@ -0,0 +1,72 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// PublicValues fetches all the values of the sheet of "sheetGID" (its sheet ID) of a public spreadsheet,
+// i.e. published to the web or shared with "anyone with the link", through its CSV export endpoint.
+// It requires no OAuth at all, the Client can be created with `NewClient(nil)`.
+// All values are strings, as shown in the sheet, the first row is usually the header one.
+//
+// Usage:
+//
+//	client := sheets.NewClient(nil)
+//	valueRange, err := client.PublicValues(ctx, spreadsheetID, 0)
+func (c *Client) PublicValues(ctx context.Context, spreadsheetID string, sheetGID int64) (ValueRange, error) {
+	url := fmt.Sprintf(docsExportURL, spreadsheetID)
+	q := Query{
+		"format": []string{"csv"},
+		"gid":    []string{strconv.FormatInt(sheetGID, 10)},
+	}
+
+	var b bytes.Buffer
+	if err := c.download(ctx, url, &b, q); err != nil {
+		return ValueRange{}, c.opError(err, "export/csv", spreadsheetID, "", 0)
+	}
+
+	valueRange := ValueRange{MajorDimension: Rows}
+
+	r := csv.NewReader(&b)
+	r.FieldsPerRecord = -1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return valueRange, nil
+		}
+		if err != nil {
+			return ValueRange{}, c.opError(err, "export/csv", spreadsheetID, "", 0)
+		}
+
+		row := make([]interface{}, len(record))
+		for i, field := range record {
+			row[i] = field
+		}
+		valueRange.Values = append(valueRange.Values, row)
+	}
+}
+
+// ReadPublicSpreadsheet binds the rows of the sheet of "sheetGID" of a public spreadsheet to the "dest",
+// the first (header) row is skipped. It uses the `Client.Decoder`, if any.
+// See `Client.PublicValues` method.
+func (c *Client) ReadPublicSpreadsheet(ctx context.Context, dest interface{}, spreadsheetID string, sheetGID int64) error {
+	valueRange, err := c.PublicValues(ctx, spreadsheetID, sheetGID)
+	if err != nil {
+		return err
+	}
+
+	if len(valueRange.Values) > 0 {
+		valueRange.Values = valueRange.Values[1:]
+	}
+
+	if c.Decoder != nil {
+		return c.Decoder.Decode(dest, valueRange)
+	}
+
+	return DecodeValueRange(dest, valueRange)
+}
@@ -0,0 +1,35 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientReadPublicSpreadsheet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no authorization header")
+		}
+		if expected, got := "csv", r.URL.Query().Get("format"); expected != got {
+			t.Errorf("expected format %q but got %q", expected, got)
+		}
+
+		w.Write([]byte("Name,Age\nmakis,27\n\"o'neil, jr\",30\n"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	var rows []testRowFailingDecoder
+	if err := c.ReadPublicSpreadsheet(context.Background(), &rows, "id", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testRowFailingDecoder{{Name: "makis", Age: 27}, {Name: "o'neil, jr", Age: 30}}
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("expected rows %v but got %v", expected, rows)
+	}
+}
@@ -0,0 +1,146 @@
+package sheets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// R1C1Ref is a cell reference in R1C1 notation, e.g. "R2C3" (absolute),
+// "R[-1]C[2]" (relative) or "RC" (the current cell).
+// It's mostly useful to build formulas with relative references, e.g. for a repeated cell formula.
+//
+// See `ParseR1C1` and `A1ToR1C1` package-level functions.
+type R1C1Ref struct {
+	// Row is the one-based row of an absolute reference or the row offset of a relative one.
+	Row int
+	// Column is the one-based column of an absolute reference or the column offset of a relative one.
+	Column int
+	// RowRelative and ColumnRelative report whether the row and the column are relative to the current cell.
+	RowRelative    bool
+	ColumnRelative bool
+}
+
+// ParseR1C1 parses an R1C1 notation cell reference, e.g. "R2C3", "R[-1]C[2]", "R2C[0]" or "RC".
+func ParseR1C1(ref string) (R1C1Ref, error) {
+	var r R1C1Ref
+
+	s := strings.ToUpper(ref)
+	if !strings.HasPrefix(s, "R") {
+		return r, fmt.Errorf("sheets: invalid R1C1 reference %q: missing row", ref)
+	}
+
+	i := strings.IndexByte(s, 'C')
+	if i < 0 {
+		return r, fmt.Errorf("sheets: invalid R1C1 reference %q: missing column", ref)
+	}
+
+	var err error
+	if r.Row, r.RowRelative, err = parseR1C1Part(s[1:i]); err != nil {
+		return r, fmt.Errorf("sheets: invalid R1C1 reference %q: row: %w", ref, err)
+	}
+
+	if r.Column, r.ColumnRelative, err = parseR1C1Part(s[i+1:]); err != nil {
+		return r, fmt.Errorf("sheets: invalid R1C1 reference %q: column: %w", ref, err)
+	}
+
+	return r, nil
+}
+
+// parseR1C1Part parses the row or column part of an R1C1 reference,
+// e.g. "2" (absolute), "[-1]" (relative) or "" (relative, the current one).
+func parseR1C1Part(s string) (int, bool, error) {
+	if s == "" {
+		return 0, true, nil
+	}
+
+	if s[0] == '[' {
+		if s[len(s)-1] != ']' {
+			return 0, false, fmt.Errorf("missing closing bracket")
+		}
+
+		n, err := strconv.Atoi(s[1 : len(s)-1])
+		return n, true, err
+	}
+
+	n, err := strconv.Atoi(s)
+	if err == nil && n <= 0 {
+		err = fmt.Errorf("must be greater than zero")
+	}
+
+	return n, false, err
+}
+
+// String returns the R1C1 notation of the reference, e.g. "R[-1]C[2]".
+func (r R1C1Ref) String() string {
+	return "R" + formatR1C1Part(r.Row, r.RowRelative) + "C" + formatR1C1Part(r.Column, r.ColumnRelative)
+}
+
+func formatR1C1Part(n int, relative bool) string {
+	switch {
+	case !relative:
+		return strconv.Itoa(n)
+	case n == 0:
+		return ""
+	default:
+		return "[" + strconv.Itoa(n) + "]"
+	}
+}
+
+// A1 returns the A1 notation of the reference from the cell at the zero-based "originRow" and "originColumn" indexes,
+// absolute rows and columns are prefixed with "$", e.g. "R[-1]C2" from the "C3" cell is "$B2".
+func (r R1C1Ref) A1(originRow, originColumn int64) string {
+	row, col := strconv.Itoa(r.Row), IndexToColumn(r.Column-1)
+	if r.RowRelative {
+		row = strconv.FormatInt(originRow+int64(r.Row)+1, 10)
+	} else {
+		row = "$" + row
+	}
+
+	if r.ColumnRelative {
+		col = IndexToColumn(int(originColumn) + r.Column)
+	} else {
+		col = "$" + col
+	}
+
+	return col + row
+}
+
+// A1ToR1C1 converts an A1 notation cell, e.g. "B2" or "$B$2", to an R1C1 reference
+// from the cell at the zero-based "originRow" and "originColumn" indexes.
+// Rows and columns prefixed with "$" are absolute, the rest are relative, e.g. "$B2" from the "C3" cell is "R[-1]C2".
+func A1ToR1C1(cell string, originRow, originColumn int64) (R1C1Ref, error) {
+	var r R1C1Ref
+
+	s := cell
+	columnAbsolute := strings.HasPrefix(s, "$")
+	s = strings.TrimPrefix(s, "$")
+
+	i := 0
+	for ; i < len(s) && isLetter(s[i]); i++ {
+	}
+
+	letters, digits := s[:i], s[i:]
+	rowAbsolute := strings.HasPrefix(digits, "$")
+	digits = strings.TrimPrefix(digits, "$")
+
+	col := ColumnToIndex(letters)
+	row, err := strconv.Atoi(digits)
+	if col < 0 || err != nil || row <= 0 {
+		return r, &InvalidRangeError{Range: cell, Component: "start cell", Value: cell, Reason: "not a cell"}
+	}
+
+	if rowAbsolute {
+		r.Row = row
+	} else {
+		r.Row, r.RowRelative = row-1-int(originRow), true
+	}
+
+	if columnAbsolute {
+		r.Column = col + 1
+	} else {
+		r.Column, r.ColumnRelative = col-int(originColumn), true
+	}
+
+	return r, nil
+}
@@ -0,0 +1,72 @@
+package sheets
+
+import "testing"
+
+func TestParseR1C1(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected R1C1Ref
+	}{
+		{"R2C3", R1C1Ref{Row: 2, Column: 3}},
+		{"R[-1]C[2]", R1C1Ref{Row: -1, Column: 2, RowRelative: true, ColumnRelative: true}},
+		{"RC", R1C1Ref{RowRelative: true, ColumnRelative: true}},
+		{"R2C[-1]", R1C1Ref{Row: 2, Column: -1, ColumnRelative: true}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseR1C1(tt.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tt.expected != got {
+			t.Fatalf("%q: expected %#v but got %#v", tt.ref, tt.expected, got)
+		}
+
+		if got.String() != tt.ref {
+			t.Fatalf("expected %q to be formatted back but got %q", tt.ref, got.String())
+		}
+	}
+
+	for _, ref := range []string{"", "A1", "R1", "R0C1", "R[1C1", "RxC1"} {
+		if _, err := ParseR1C1(ref); err == nil {
+			t.Fatalf("expected %q to be invalid", ref)
+		}
+	}
+}
+
+func TestA1ToR1C1(t *testing.T) {
+	// from the "C3" cell.
+	const originRow, originColumn = 2, 2
+
+	tests := []struct {
+		a1   string
+		r1c1 string
+	}{
+		{"C3", "RC"},
+		{"B2", "R[-1]C[-1]"},
+		{"$B2", "R[-1]C2"},
+		{"B$2", "R2C[-1]"},
+		{"$B$2", "R2C2"},
+		{"E10", "R[7]C[2]"},
+	}
+
+	for _, tt := range tests {
+		ref, err := A1ToR1C1(tt.a1, originRow, originColumn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := ref.String(); tt.r1c1 != got {
+			t.Fatalf("expected %q to be %q but got %q", tt.a1, tt.r1c1, got)
+		}
+
+		if got := ref.A1(originRow, originColumn); tt.a1 != got {
+			t.Fatalf("expected %q to be converted back but got %q", tt.a1, got)
+		}
+	}
+
+	if _, err := A1ToR1C1("A0", 0, 0); err == nil {
+		t.Fatal("expected an invalid cell error")
+	}
+}
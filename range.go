@@ -0,0 +1,533 @@
+package sheets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxColumnLetters is the maximum number of letters of a column, "ZZZ" is the last column of a sheet.
+const maxColumnLetters = 3
+
+// InvalidRangeError is returned, before any request is sent, when a range is not a valid A1 notation range.
+// It matches the `ErrInvalidRange` sentinel error through `errors.Is`.
+type InvalidRangeError struct {
+	// Range is the full range as given by the caller.
+	Range string
+	// Component is the offending component of the range, e.g. "sheet title", "start cell", "end cell" or "end row".
+	Component string
+	// Value is the value of the offending component.
+	Value string
+	// Reason describes what is wrong with the component.
+	Reason string
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *InvalidRangeError) Error() string {
+	return fmt.Sprintf("sheets: invalid range %q: %s %q: %s", e.Range, e.Component, e.Value, e.Reason)
+}
+
+// Is implements the standard `errors.Is` internal interface.
+// It reports whether the "target" is the `ErrInvalidRange` sentinel error.
+func (e *InvalidRangeError) Is(target error) bool {
+	return target == ErrInvalidRange
+}
+
+// ValidateRange reports whether "dataRange" is a valid A1 notation range,
+// e.g. "Sheet1!A1:B2", "'My Sheet'!A:C", "A1:Z", "Sheet1!2:5" or a sheet title or named range alone.
+// R1C1 notation cells, e.g. "Sheet1!R1C1:R2C2", are accepted too.
+// It returns an `*InvalidRangeError` which describes the offending component of the range.
+func ValidateRange(dataRange string) error {
+	if dataRange == "" {
+		return &InvalidRangeError{Range: dataRange, Component: "range", Reason: "empty"}
+	}
+
+	title, cells := "", dataRange
+	if i := strings.LastIndexByte(dataRange, '!'); i >= 0 && !isQuoted(dataRange) {
+		title, cells = dataRange[:i], dataRange[i+1:]
+		if reason := validateSheetTitle(title); reason != "" {
+			return &InvalidRangeError{Range: dataRange, Component: "sheet title", Value: title, Reason: reason}
+		}
+
+		if cells == "" {
+			return &InvalidRangeError{Range: dataRange, Component: "start cell", Reason: "missing after the sheet title"}
+		}
+	} else if !isCells(dataRange) {
+		// A sheet title or a named range alone.
+		if reason := validateSheetTitle(dataRange); reason != "" {
+			return &InvalidRangeError{Range: dataRange, Component: "sheet title", Value: dataRange, Reason: reason}
+		}
+
+		return nil
+	}
+
+	start, end := cells, ""
+	hasEnd := false
+	if i := strings.IndexByte(cells, ':'); i >= 0 {
+		start, end, hasEnd = cells[:i], cells[i+1:], true
+	}
+
+	startCol, startRow, ok := parseCell(start)
+	if !ok {
+		return &InvalidRangeError{Range: dataRange, Component: "start cell", Value: start, Reason: cellReason(start)}
+	}
+
+	if !hasEnd {
+		if startCol == 0 || startRow == 0 {
+			return &InvalidRangeError{Range: dataRange, Component: "start cell", Value: start, Reason: "a single cell requires both column and row"}
+		}
+
+		return nil
+	}
+
+	endCol, endRow, ok := parseCell(end)
+	if !ok {
+		return &InvalidRangeError{Range: dataRange, Component: "end cell", Value: end, Reason: cellReason(end)}
+	}
+
+	if startCol == 0 && endCol > 0 && endRow == 0 || startRow == 0 && endRow > 0 && endCol == 0 {
+		return &InvalidRangeError{Range: dataRange, Component: "end cell", Value: end, Reason: "does not match the start cell " + start}
+	}
+
+	if startCol > 0 && endCol > 0 && startCol > endCol {
+		return &InvalidRangeError{Range: dataRange, Component: "end cell", Value: end, Reason: "column is before the start column"}
+	}
+
+	if startRow > 0 && endRow > 0 && startRow > endRow {
+		return &InvalidRangeError{Range: dataRange, Component: "end cell", Value: end, Reason: "row is before the start row"}
+	}
+
+	return nil
+}
+
+// ParseA1 parses an A1 notation range, e.g. "'Sheet 1'!B2:D10", into its sheet title and grid range.
+// The sheet title is unquoted, e.g. "Sheet 1", and it's empty when the range does not include one.
+// The `GridRange.SheetID` is not set, the caller should resolve it from the title (see `Spreadsheet.GetSheet`).
+// Named ranges cannot be parsed.
+//
+// See `GridRange.A1` method for the inverse.
+func ParseA1(dataRange string) (sheetTitle string, gridRange GridRange, err error) {
+	if err = ValidateRange(dataRange); err != nil {
+		return
+	}
+
+	cells := dataRange
+	if i := strings.LastIndexByte(dataRange, '!'); i >= 0 && !isQuoted(dataRange) {
+		sheetTitle, cells = unquoteSheetTitle(dataRange[:i]), dataRange[i+1:]
+	} else if !isCells(dataRange) {
+		// A sheet title alone (or a named range, which cannot be told apart).
+		return unquoteSheetTitle(dataRange), gridRange, nil
+	}
+
+	start, end, hasEnd := strings.Cut(cells, ":")
+	startCol, startRow, _ := parseCell(start)
+	if startCol > 0 {
+		gridRange.StartColumnIndex = int64(startCol - 1)
+	}
+	if startRow > 0 {
+		gridRange.StartRowIndex = int64(startRow - 1)
+	}
+
+	if !hasEnd {
+		gridRange.EndColumnIndex, gridRange.EndRowIndex = int64(startCol), int64(startRow)
+		return
+	}
+
+	endCol, endRow, _ := parseCell(end)
+	gridRange.EndColumnIndex, gridRange.EndRowIndex = int64(endCol), int64(endRow)
+	return
+}
+
+// A1 returns the A1 notation of the range on the sheet with "sheetTitle", e.g. "'Sheet 1'!B2:D10".
+// The title is quoted if necessary, an empty "sheetTitle" omits the sheet.
+// A zero end index is unbounded, e.g. "Sheet1!A5:B" or "Sheet1!A:B".
+// The A1 notation cannot express an unbounded end column along with a start row or column (e.g. "C5" to the last column),
+// so that range spans the start column only, e.g. "Sheet1!C5:C", set its end column index to the sheet's column count instead.
+//
+// See `ParseA1` package-level function for the inverse.
+func (r GridRange) A1(sheetTitle string) string {
+	var (
+		cells   string
+		hasCols = r.StartColumnIndex > 0 || r.EndColumnIndex > 0
+		hasRows = r.StartRowIndex > 0 || r.EndRowIndex > 0
+	)
+
+	startRow, endRow := "", ""
+	if hasRows {
+		startRow = strconv.FormatInt(r.StartRowIndex+1, 10)
+	}
+	if r.EndRowIndex > 0 {
+		endRow = strconv.FormatInt(r.EndRowIndex, 10)
+	}
+
+	switch {
+	case !hasCols && !hasRows:
+		// the whole sheet.
+	case !hasCols && endRow != "":
+		cells = startRow + ":" + endRow
+	default:
+		startCol := columnName(int(r.StartColumnIndex) + 1)
+		endCol := startCol
+		if r.EndColumnIndex > 0 {
+			endCol = columnName(int(r.EndColumnIndex))
+		}
+
+		start, end := startCol+startRow, endCol+endRow
+		if start == end && startRow != "" {
+			cells = start // a single cell.
+		} else {
+			cells = start + ":" + end
+		}
+	}
+
+	switch {
+	case sheetTitle == "":
+		return cells
+	case cells == "":
+		return QuoteSheetTitle(sheetTitle)
+	default:
+		return QuoteSheetTitle(sheetTitle) + "!" + cells
+	}
+}
+
+// ChunkRange splits the A1 notation range "a1" into contiguous sub-ranges of up to "rowsPerChunk" rows each,
+// e.g. ChunkRange("Sheet1!A1:C250", 100) returns "Sheet1!A1:C100", "Sheet1!A101:C200" and "Sheet1!A201:C250".
+// It's useful to batch reads and writes, and report progress, over very large sheets.
+// The range must have a bounded end row, e.g. "Sheet1!A:C" cannot be split.
+func ChunkRange(a1 string, rowsPerChunk int) ([]string, error) {
+	if rowsPerChunk <= 0 {
+		return nil, fmt.Errorf("sheets: invalid rows per chunk: %d", rowsPerChunk)
+	}
+
+	sheetTitle, gridRange, err := ParseA1(a1)
+	if err != nil {
+		return nil, err
+	}
+
+	if gridRange.EndRowIndex == 0 {
+		return nil, &InvalidRangeError{Range: a1, Component: "end row", Value: a1, Reason: "unbounded range cannot be split"}
+	}
+
+	var (
+		n      = int64(rowsPerChunk)
+		chunks = make([]string, 0, (gridRange.EndRowIndex-gridRange.StartRowIndex+n-1)/n)
+	)
+
+	for start := gridRange.StartRowIndex; start < gridRange.EndRowIndex; start += n {
+		chunk := gridRange
+		chunk.StartRowIndex, chunk.EndRowIndex = start, start+n
+		if chunk.EndRowIndex > gridRange.EndRowIndex {
+			chunk.EndRowIndex = gridRange.EndRowIndex
+		}
+
+		chunks = append(chunks, chunk.A1(sheetTitle))
+	}
+
+	return chunks, nil
+}
+
+// WholeColumn returns the A1 notation range of the whole "column" letters on the sheet with "sheetTitle",
+// e.g. WholeColumn("Sheet1", "C") returns "Sheet1!C:C". An empty "sheetTitle" omits the sheet.
+// It returns an empty string if "column" is not a valid column.
+func WholeColumn(sheetTitle, column string) string {
+	col := ColumnToIndex(column)
+	if col < 0 {
+		return ""
+	}
+
+	cells := IndexToColumn(col) + ":" + IndexToColumn(col)
+	if sheetTitle == "" {
+		return cells
+	}
+
+	return QuoteSheetTitle(sheetTitle) + "!" + cells
+}
+
+// WholeRow returns the A1 notation range of the whole one-based "row" on the sheet with "sheetTitle",
+// e.g. WholeRow("Sheet1", 5) returns "Sheet1!5:5". An empty "sheetTitle" omits the sheet.
+// It returns an empty string if "row" is not positive.
+func WholeRow(sheetTitle string, row int) string {
+	if row <= 0 {
+		return ""
+	}
+
+	return GridRange{StartRowIndex: int64(row - 1), EndRowIndex: int64(row)}.A1(sheetTitle)
+}
+
+// OpenEndedFrom returns the A1 notation range from the "startCell", e.g. "A2" or "Sheet1!A2",
+// to the last row of the sheet, through the "endColumn" letters,
+// e.g. OpenEndedFrom("Sheet1!B2", "D") returns "Sheet1!B2:D". An empty "endColumn" spans the start column only,
+// e.g. OpenEndedFrom("B2", "") returns "B2:B".
+// The end column should not exceed the sheet's column count (see `SheetGrid`), otherwise the API fails with "exceeds grid limits".
+// It returns an empty string if "startCell" is not a valid cell or "endColumn" is before its column.
+func OpenEndedFrom(startCell, endColumn string) string {
+	sheetTitle, cell := "", startCell
+	if i := strings.LastIndexByte(startCell, '!'); i >= 0 {
+		sheetTitle, cell = unquoteSheetTitle(startCell[:i]), startCell[i+1:]
+	}
+
+	start, err := ParseCell(cell)
+	if err != nil {
+		return ""
+	}
+
+	endCol := start.Col
+	if endColumn != "" {
+		if endCol = ColumnToIndex(endColumn); endCol < start.Col {
+			return ""
+		}
+	}
+
+	r := GridRange{
+		StartRowIndex:    int64(start.Row),
+		StartColumnIndex: int64(start.Col),
+		EndColumnIndex:   int64(endCol + 1),
+	}
+	return r.A1(sheetTitle)
+}
+
+// QuoteSheetTitle returns the "title" ready to be used in an A1 notation range, e.g. QuoteSheetTitle("Bob's data") + "!A1:B".
+// It encloses the title in single quotes, escaping its quotes by doubling them,
+// if it contains characters other than letters, digits and underscores or if it looks like a cell, e.g. "A1".
+func QuoteSheetTitle(title string) string {
+	if _, _, ok := parseCell(title); ok || title == "" {
+		return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+	}
+
+	for _, r := range title {
+		if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+		}
+	}
+
+	return title
+}
+
+// unquoteSheetTitle returns the unquoted and unescaped "title".
+func unquoteSheetTitle(title string) string {
+	if isQuoted(title) {
+		return strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+
+	return title
+}
+
+// validateSheetTitle returns the reason why "title" is not a valid,
+// properly escaped, sheet title or an empty string if it is valid.
+func validateSheetTitle(title string) string {
+	if title == "" {
+		return "empty"
+	}
+
+	if title[0] != '\'' {
+		for _, r := range title {
+			if r == '\'' || unicode.IsSpace(r) {
+				return "must be enclosed in single quotes"
+			}
+		}
+
+		return ""
+	}
+
+	if len(title) < 2 || title[len(title)-1] != '\'' {
+		return "missing closing single quote"
+	}
+
+	inner := title[1 : len(title)-1]
+	if inner == "" {
+		return "empty"
+	}
+
+	if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+		return "single quotes must be escaped by doubling them"
+	}
+
+	return ""
+}
+
+func isQuoted(s string) bool {
+	return len(s) > 1 && s[0] == '\'' && s[len(s)-1] == '\''
+}
+
+// isCells reports whether "s" looks like cells without a sheet title,
+// i.e. a range of cells (e.g. "A1:B", "A:C") or a single cell (e.g. "A1").
+func isCells(s string) bool {
+	if start, _, found := strings.Cut(s, ":"); found {
+		_, _, ok := parseCell(start)
+		return ok
+	}
+
+	col, row, ok := parseCell(s)
+	return ok && col > 0 && row > 0
+}
+
+// parseCell parses an A1 notation cell, e.g. "A1", "A" or "1", or an R1C1 notation cell, e.g. "R1C1".
+// It returns the one-based column and row, zero when they are omitted.
+func parseCell(s string) (col, row int, ok bool) {
+	if col, row, ok = parseA1Cell(s); ok {
+		return
+	}
+
+	return parseR1C1Cell(s)
+}
+
+// parseA1Cell parses an A1 notation cell, e.g. "A1", "A" or "1".
+func parseA1Cell(s string) (col, row int, ok bool) {
+	i := 0
+	for ; i < len(s) && isLetter(s[i]); i++ {
+		col = col*26 + int(toUpper(s[i])-'A'+1)
+	}
+
+	if i > maxColumnLetters {
+		return 0, 0, false
+	}
+
+	digits := s[i:]
+	if digits == "" {
+		return col, 0, i > 0
+	}
+
+	for j := 0; j < len(digits); j++ {
+		if !isDigit(digits[j]) || row > 1e7 {
+			return 0, 0, false
+		}
+		row = row*10 + int(digits[j]-'0')
+	}
+
+	return col, row, row > 0
+}
+
+// parseR1C1Cell parses an R1C1 notation cell, e.g. "R1C1", "R2" or "C3".
+func parseR1C1Cell(s string) (col, row int, ok bool) {
+	if s == "" || (toUpper(s[0]) != 'R' && toUpper(s[0]) != 'C') {
+		return 0, 0, false
+	}
+
+	for len(s) > 0 {
+		prefix := toUpper(s[0])
+		j := 1
+		n := 0
+		for ; j < len(s) && isDigit(s[j]); j++ {
+			n = n*10 + int(s[j]-'0')
+		}
+
+		if j == 1 || n == 0 {
+			return 0, 0, false
+		}
+
+		switch {
+		case prefix == 'R' && row == 0 && col == 0:
+			row = n
+		case prefix == 'C' && col == 0:
+			col = n
+		default:
+			return 0, 0, false
+		}
+
+		s = s[j:]
+	}
+
+	return col, row, true
+}
+
+// cellReason returns the reason why "cell" cannot be parsed.
+func cellReason(cell string) string {
+	if cell == "" {
+		return "empty"
+	}
+
+	i := 0
+	for ; i < len(cell) && isLetter(cell[i]); i++ {
+	}
+
+	switch {
+	case i > maxColumnLetters:
+		return fmt.Sprintf("column letters exceed the %d letters limit", maxColumnLetters)
+	case strings.TrimLeft(cell[i:], "0123456789") != "":
+		return "column must be letters followed by a row number"
+	default:
+		return "row must be greater than zero"
+	}
+}
+
+// cellOf returns the A1 notation of the cell at the zero-based "row" and "column" offsets
+// from the start cell of "dataRange", e.g. cellOf("Sheet1!B2:D", 1, 1) returns "Sheet1!C3".
+// It returns an empty string if the start cell of "dataRange" cannot be parsed.
+func cellOf(dataRange string, row, column int) string {
+	title, cells := "", dataRange
+	if i := strings.LastIndexByte(dataRange, '!'); i >= 0 {
+		title, cells = dataRange[:i+1], dataRange[i+1:]
+	} else if !isCells(dataRange) {
+		title, cells = dataRange+"!", ""
+	}
+
+	start, _, _ := strings.Cut(cells, ":")
+	startCol, startRow := 1, 1
+	if start != "" {
+		c, r, ok := parseA1Cell(start)
+		if !ok {
+			return ""
+		}
+
+		if c > 0 {
+			startCol = c
+		}
+		if r > 0 {
+			startRow = r
+		}
+	}
+
+	return fmt.Sprintf("%s%s%d", title, columnName(startCol+column), startRow+row)
+}
+
+// ColumnToIndex returns the zero-based index of the column "letters", e.g. "A" is 0 and "AB" is 27,
+// matching the `GridRange` column indexes. The letters are case-insensitive.
+// It returns -1 if "letters" is not a valid column.
+func ColumnToIndex(letters string) int {
+	if letters == "" || len(letters) > maxColumnLetters {
+		return -1
+	}
+
+	col := 0
+	for i := 0; i < len(letters); i++ {
+		if !isLetter(letters[i]) {
+			return -1
+		}
+		col = col*26 + int(toUpper(letters[i])-'A'+1)
+	}
+
+	return col - 1
+}
+
+// IndexToColumn returns the letters of the zero-based column "index", e.g. 0 is "A" and 27 is "AB",
+// matching the `GridRange` column indexes. It returns an empty string for a negative index.
+func IndexToColumn(index int) string {
+	return columnName(index + 1)
+}
+
+// columnName returns the letters of the one-based "col", e.g. 1 is "A" and 27 is "AA".
+func columnName(col int) string {
+	var b []byte
+	for ; col > 0; col = (col - 1) / 26 {
+		b = append([]byte{byte('A' + (col-1)%26)}, b...)
+	}
+
+	return string(b)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func toUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
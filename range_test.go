@@ -0,0 +1,264 @@
+package sheets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateRange(t *testing.T) {
+	valid := []string{
+		"A1",
+		"A1:Z",
+		"a1:b2",
+		"A:C",
+		"2:5",
+		"Sheet1",
+		"Sheet1!A1:B2",
+		"Sheet1!A5:B",
+		"'My Sheet'!A:C",
+		"'It''s'!A1",
+		"'a!b'!A1",
+		"'My Sheet'",
+		"MyNamedRange",
+		"Sheet1!R1C1:R2C2",
+		"Sheet1!ZZZ1",
+	}
+
+	for _, dataRange := range valid {
+		if err := ValidateRange(dataRange); err != nil {
+			t.Fatalf("expected %q to be valid but got: %v", dataRange, err)
+		}
+	}
+
+	invalid := []struct {
+		dataRange string
+		component string
+		value     string
+	}{
+		{"", "range", ""},
+		{"My Sheet!A1", "sheet title", "My Sheet"},
+		{"'It's'!A1", "sheet title", "'It's'"},
+		{"'Sheet1!A1", "sheet title", "'Sheet1"},
+		{"Sheet1!", "start cell", ""},
+		{"Sheet1!A0", "start cell", "A0"},
+		{"Sheet1!AAAA1", "start cell", "AAAA1"},
+		{"Sheet1!A1:B-2", "end cell", "B-2"},
+		{"Sheet1!C1:A2", "end cell", "A2"},
+		{"Sheet1!A5:B2", "end cell", "B2"},
+		{"Sheet1!A:1", "end cell", "1"},
+		{"Sheet1!A", "start cell", "A"},
+	}
+
+	for _, tt := range invalid {
+		err := ValidateRange(tt.dataRange)
+
+		var rangeErr *InvalidRangeError
+		if !errors.As(err, &rangeErr) {
+			t.Fatalf("expected %q to be invalid but got: %v", tt.dataRange, err)
+		}
+
+		if rangeErr.Component != tt.component || rangeErr.Value != tt.value {
+			t.Fatalf("expected %q to fail at %s %q but got: %v", tt.dataRange, tt.component, tt.value, err)
+		}
+
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Fatalf("expected %q error to match ErrInvalidRange", tt.dataRange)
+		}
+	}
+}
+
+func TestParseA1(t *testing.T) {
+	tests := []struct {
+		dataRange  string
+		sheetTitle string
+		gridRange  GridRange
+		a1         string // the formatted range, if different.
+	}{
+		{"'Sheet 1'!B2:D10", "Sheet 1", GridRange{StartRowIndex: 1, EndRowIndex: 10, StartColumnIndex: 1, EndColumnIndex: 4}, ""},
+		{"Sheet1!A1", "Sheet1", GridRange{EndRowIndex: 1, EndColumnIndex: 1}, ""},
+		{"Sheet1!A1:A1", "Sheet1", GridRange{EndRowIndex: 1, EndColumnIndex: 1}, "Sheet1!A1"},
+		{"Sheet1!A3:B4", "Sheet1", GridRange{StartRowIndex: 2, EndRowIndex: 4, EndColumnIndex: 2}, ""},
+		{"Sheet1!A:B", "Sheet1", GridRange{EndColumnIndex: 2}, ""},
+		{"Sheet1!A5:B", "Sheet1", GridRange{StartRowIndex: 4, StartColumnIndex: 0, EndColumnIndex: 2}, ""},
+		{"Sheet1!3:4", "Sheet1", GridRange{StartRowIndex: 2, EndRowIndex: 4}, ""},
+		{"Sheet1!C5:ZZZ", "Sheet1", GridRange{StartRowIndex: 4, StartColumnIndex: 2, EndColumnIndex: 18278}, ""},
+		{"'Bob''s data'", "Bob's data", GridRange{}, ""},
+		{"B2:C3", "", GridRange{StartRowIndex: 1, EndRowIndex: 3, StartColumnIndex: 1, EndColumnIndex: 3}, ""},
+	}
+
+	for _, tt := range tests {
+		sheetTitle, gridRange, err := ParseA1(tt.dataRange)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.dataRange, err)
+		}
+
+		if sheetTitle != tt.sheetTitle || gridRange != tt.gridRange {
+			t.Fatalf("%q: expected %q %#v but got %q %#v", tt.dataRange, tt.sheetTitle, tt.gridRange, sheetTitle, gridRange)
+		}
+
+		expected := tt.a1
+		if expected == "" {
+			expected = tt.dataRange
+		}
+		if got := gridRange.A1(sheetTitle); expected != got {
+			t.Fatalf("%q: expected A1 %q but got %q", tt.dataRange, expected, got)
+		}
+	}
+
+	if _, _, err := ParseA1("Sheet1!B2:A1"); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an invalid range error but got %v", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		letters string
+		index   int
+	}{
+		{"A", 0},
+		{"Z", 25},
+		{"AA", 26},
+		{"AB", 27},
+		{"ZZ", 701},
+		{"AAA", 702},
+		{"ZZZ", 18277},
+	}
+
+	for _, tt := range tests {
+		if got := ColumnToIndex(tt.letters); tt.index != got {
+			t.Fatalf("expected %q to be index %d but got %d", tt.letters, tt.index, got)
+		}
+		if got := IndexToColumn(tt.index); tt.letters != got {
+			t.Fatalf("expected index %d to be %q but got %q", tt.index, tt.letters, got)
+		}
+	}
+
+	if got := ColumnToIndex("ab"); got != 27 {
+		t.Fatalf("expected lowercase letters to be accepted but got %d", got)
+	}
+
+	for _, letters := range []string{"", "A1", "AAAA", "-"} {
+		if got := ColumnToIndex(letters); got != -1 {
+			t.Fatalf("expected %q to be invalid but got %d", letters, got)
+		}
+	}
+
+	if got := IndexToColumn(-1); got != "" {
+		t.Fatalf("expected an empty column for a negative index but got %q", got)
+	}
+}
+
+func TestQuoteSheetTitle(t *testing.T) {
+	tests := []struct {
+		title  string
+		quoted string
+	}{
+		{"Sheet1", "Sheet1"},
+		{"Φύλλο_1", "Φύλλο_1"},
+		{"My Sheet", "'My Sheet'"},
+		{"Bob's data", "'Bob''s data'"},
+		{"A1", "'A1'"},
+		{"2024", "'2024'"},
+		{"", "''"},
+	}
+
+	for _, tt := range tests {
+		quoted := QuoteSheetTitle(tt.title)
+		if tt.quoted != quoted {
+			t.Fatalf("expected %q to be quoted as %q but got %q", tt.title, tt.quoted, quoted)
+		}
+
+		if err := ValidateRange(quoted + "!A1"); tt.title != "" && err != nil {
+			t.Fatalf("expected quoted %q to be valid: %v", quoted, err)
+		}
+	}
+}
+
+func TestChunkRange(t *testing.T) {
+	tests := []struct {
+		a1           string
+		rowsPerChunk int
+		expected     []string
+	}{
+		{"Sheet1!A1:C250", 100, []string{"Sheet1!A1:C100", "Sheet1!A101:C200", "Sheet1!A201:C250"}},
+		{"'My Sheet'!B5:D6", 10, []string{"'My Sheet'!B5:D6"}},
+		{"1:4", 2, []string{"1:2", "3:4"}},
+		{"A1", 1, []string{"A1"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ChunkRange(tt.a1, tt.rowsPerChunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(tt.expected, got) {
+			t.Fatalf("%q: expected %q but got %q", tt.a1, tt.expected, got)
+		}
+	}
+
+	if _, err := ChunkRange("Sheet1!A:C", 10); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an invalid range error but got: %v", err)
+	}
+	if _, err := ChunkRange("Sheet1!A1:C10", 0); err == nil {
+		t.Fatal("expected an error for zero rows per chunk")
+	}
+}
+
+func TestWholeRangeHelpers(t *testing.T) {
+	tests := []struct {
+		got      string
+		expected string
+	}{
+		{WholeColumn("Sheet1", "C"), "Sheet1!C:C"},
+		{WholeColumn("My Sheet", "ab"), "'My Sheet'!AB:AB"},
+		{WholeColumn("", "C"), "C:C"},
+		{WholeColumn("Sheet1", "C1"), ""},
+		{WholeRow("Sheet1", 5), "Sheet1!5:5"},
+		{WholeRow("", 1), "1:1"},
+		{WholeRow("Sheet1", 0), ""},
+		{OpenEndedFrom("A2", "C"), "A2:C"},
+		{OpenEndedFrom("B2", ""), "B2:B"},
+		{OpenEndedFrom("'My Sheet'!A1", "z"), "'My Sheet'!A:Z"},
+		{OpenEndedFrom("Sheet1!C2", "B"), ""},
+		{OpenEndedFrom("Sheet1!A", "C"), ""},
+	}
+
+	for i, tt := range tests {
+		if tt.expected != tt.got {
+			t.Fatalf("[%d] expected %q but got %q", i, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestUnboundedRangesWithinGrid(t *testing.T) {
+	sheet := Sheet{Properties: SheetProperties{Title: "Sheet1", Grid: SheetGrid{RowCount: 1000, ColumnCount: 26}}}
+	unknownGrid := Sheet{Properties: SheetProperties{Title: "Sheet1", Grid: SheetGrid{RowCount: 1000, FrozenRowCount: 1}}}
+
+	tests := []struct {
+		dataRange string
+		expected  string
+	}{
+		{OpenEndedFrom("Sheet1!B2", ""), "Sheet1!B2:B"},
+		{OpenEndedFrom("Sheet1!B2", "Z"), "Sheet1!B2:Z"},
+		{GridRange{StartRowIndex: 4, StartColumnIndex: 2}.A1("Sheet1"), "Sheet1!C5:C"},
+		{GridRange{StartRowIndex: 4}.A1("Sheet1"), "Sheet1!A5:A"},
+		{unknownGrid.DataRange(), "Sheet1!2:1000"},
+	}
+
+	for _, tt := range tests {
+		if tt.expected != tt.dataRange {
+			t.Fatalf("expected %q but got %q", tt.expected, tt.dataRange)
+		}
+
+		_, r, err := ParseA1(tt.dataRange)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = sheet.ValidateRange(r); err != nil {
+			t.Fatalf("%q: expected the range to be within a 26-column grid but got %v", tt.dataRange, err)
+		}
+	}
+}
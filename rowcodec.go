@@ -0,0 +1,96 @@
+package sheets
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowDecoder can be implemented by a struct (pointer receiver) to decode a row of cell values
+// without reflection, the `Decoder` calls it instead of binding each field through reflection.
+// The cells are in the order of the struct's headers, extra cells are removed (see `Decoder.Strict`).
+// A `FieldDecoder` of the same struct is not called.
+//
+// The sheetsgen tool generates the implementation, see its documentation:
+//
+//	go run github.com/kataras/sheets/cmd/sheetsgen -h
+type RowDecoder interface {
+	DecodeRow(row []interface{}) error
+}
+
+// RowEncoder can be implemented by a struct to encode it to a row of cell values without reflection,
+// e.g. by `SheetService.AppendRow`. See `RowDecoder` too.
+type RowEncoder interface {
+	EncodeRow() ([]interface{}, error)
+}
+
+var rowDecoderTyp = reflect.TypeOf((*RowDecoder)(nil)).Elem()
+
+// The following helpers are used by the code generated by the sheetsgen tool,
+// each one of them reports false for values which should be decoded by `DecodeCell` instead.
+
+// CellString returns the text of a string, number or bool cell value.
+func CellString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64, bool:
+		return fmt.Sprint(value), true
+	default:
+		return "", false
+	}
+}
+
+// CellInt returns the integer of a number or numeric string cell value, the fraction is truncated.
+func CellInt(value interface{}) (int64, bool) {
+	if i, ok := value.(int); ok {
+		return int64(i), true
+	}
+
+	f, ok := decodeFloat(value)
+	return int64(f), ok
+}
+
+// CellFloat returns the number of a number or numeric string cell value.
+func CellFloat(value interface{}) (float64, bool) {
+	return decodeFloat(value)
+}
+
+// CellBool returns the bool of a bool, number or checkbox (e.g. "TRUE") cell value.
+func CellBool(value interface{}) (bool, bool) {
+	switch value := value.(type) {
+	case bool:
+		return value, true
+	case float64:
+		return value != 0, true
+	case string:
+		s := strings.TrimSpace(value)
+		if s == "" {
+			return false, true
+		}
+
+		b, err := strconv.ParseBool(strings.ToLower(s))
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
+// CellTime returns the time of a date and time cell value, see `DecodeValueRange`.
+func CellTime(value interface{}) (time.Time, bool) {
+	return parseTime(value)
+}
+
+// DecodeCell sets the cell "value" to the field pointed by "ptr", e.g. &row.Name,
+// through reflection as `DecodeValueRange` does.
+func DecodeCell(ptr interface{}, value interface{}) {
+	setFieldValue(reflect.ValueOf(ptr).Elem(), value)
+}
+
+// EncodeCell returns the cell value of the field pointed by "ptr", e.g. &row.Name,
+// through reflection as the struct rows are encoded.
+func EncodeCell(ptr interface{}) (interface{}, error) {
+	return encodeValue(reflect.ValueOf(ptr).Elem())
+}
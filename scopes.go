@@ -0,0 +1,85 @@
+package sheets
+
+import (
+	"fmt"
+	"strings"
+)
+
+const scopePrefix = "https://www.googleapis.com/auth/"
+
+// impliedScopes holds the scopes that are already granted by a broader one.
+var impliedScopes = map[string][]string{
+	ScopeReadWrite: {ScopeReadOnly},
+	ScopeDrive:     {ScopeDriveFile, ScopeDriveReadOnly, ScopeReadWrite, ScopeReadOnly},
+}
+
+// Scopes validates and combines oauth2 scopes of one or more APIs
+// so that they can be passed on the authentication functions, e.g. `ServiceAccountE`.
+// A scope can be a full URL or its short name, e.g. "spreadsheets" or "drive.file".
+// Duplicated scopes and scopes which are granted by a broader one
+// (e.g. `ScopeReadOnly` when `ScopeReadWrite` is present) are removed.
+//
+// Usage:
+//
+//	scopes, err := sheets.Scopes(sheets.ScopeReadWrite, "drive.file")
+func Scopes(scopes ...string) ([]string, error) {
+	set := make(map[string]struct{}, len(scopes))
+	var result []string
+
+	for _, scope := range scopes {
+		full, err := normalizeScope(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := set[full]; ok {
+			continue
+		}
+
+		set[full] = struct{}{}
+		result = append(result, full)
+	}
+
+	combined := result[:0]
+	for _, scope := range result {
+		if !isImpliedScope(scope, set) {
+			combined = append(combined, scope)
+		}
+	}
+
+	return combined, nil
+}
+
+func normalizeScope(scope string) (string, error) {
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		return "", fmt.Errorf("empty scope")
+	}
+
+	if !strings.Contains(scope, "://") {
+		scope = scopePrefix + scope
+	}
+
+	if !strings.HasPrefix(scope, "https://") || strings.ContainsAny(scope, " \t\n") {
+		return "", fmt.Errorf("invalid scope: %q", scope)
+	}
+
+	return scope, nil
+}
+
+// isImpliedScope reports whether "scope" is granted by any other scope of the "set".
+func isImpliedScope(scope string, set map[string]struct{}) bool {
+	for broader, implied := range impliedScopes {
+		if _, ok := set[broader]; !ok || broader == scope {
+			continue
+		}
+
+		for _, s := range implied {
+			if s == scope {
+				return true
+			}
+		}
+	}
+
+	return false
+}
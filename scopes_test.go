@@ -0,0 +1,39 @@
+package sheets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScopes(t *testing.T) {
+	tests := []struct {
+		scopes   []string
+		expected []string
+		err      bool
+	}{
+		{[]string{ScopeReadOnly}, []string{ScopeReadOnly}, false},
+		{[]string{"spreadsheets", ScopeReadOnly, ScopeReadWrite}, []string{ScopeReadWrite}, false},
+		{[]string{ScopeReadWrite, "drive.file", ScopeDriveFile}, []string{ScopeReadWrite, ScopeDriveFile}, false},
+		{[]string{ScopeDriveFile, ScopeReadOnly, ScopeDrive}, []string{ScopeDrive}, false},
+		{[]string{""}, nil, true},
+		{[]string{"http://example.com/scope"}, nil, true},
+	}
+
+	for i, tt := range tests {
+		got, err := Scopes(tt.scopes...)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("[%d] expected an error", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(tt.expected, got) {
+			t.Fatalf("[%d] expected scopes %v but got %v", i, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// scriptRunURL is the Apps Script Execution API endpoint to run a function of a script.
+const scriptRunURL = "/v1/scripts/%s:run"
+
+// ScriptRequest holds the options of an Apps Script function execution.
+// See `Client.RunScript` method.
+type ScriptRequest struct {
+	// Function is the name of the function to execute in the script.
+	Function string `json:"function"`
+	// Parameters are the function's parameters, they must be primitive types
+	// (strings, numbers, booleans), slices or maps of them.
+	Parameters []interface{} `json:"parameters,omitempty"`
+	// DevMode, if true, runs the most recently saved version of the script instead of the deployed one.
+	// Only the owner of the script can use it.
+	DevMode bool `json:"devMode,omitempty"`
+}
+
+// ScriptError is returned by `Client.RunScript` when the Apps Script function throws an exception.
+type ScriptError struct {
+	// Function is the name of the executed function.
+	Function string
+	// Type is the type of the error, e.g. "TypeError".
+	Type string `json:"errorType"`
+	// Message is the error message thrown by the script.
+	Message string `json:"errorMessage"`
+	// StackTrace holds the script's stack trace, the innermost function first.
+	StackTrace []ScriptStackTraceElement `json:"scriptStackTraceElements"`
+}
+
+// ScriptStackTraceElement is an element of a `ScriptError` stack trace.
+type ScriptStackTraceElement struct {
+	Function   string `json:"function"`
+	LineNumber int    `json:"lineNumber"`
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *ScriptError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sheets: script function %q: %s: %s", e.Function, e.Type, e.Message)
+	for _, el := range e.StackTrace {
+		fmt.Fprintf(&b, "\n\tat %s:%d", el.Function, el.LineNumber)
+	}
+
+	return b.String()
+}
+
+// RunScript executes a function of the Apps Script project of "scriptID" through the Apps Script Execution API
+// and binds its return value to the "result" pointer, which can be nil to ignore it.
+// It's useful for operations the Sheets API cannot express, e.g. functions of a bound script.
+// The script must be deployed as an API executable and share the same Cloud project as the Client's credentials,
+// which must be authorized with all the scopes the script uses.
+// It returns a `*ScriptError` when the function throws an exception.
+//
+// Usage:
+//
+//	var total float64
+//	err := client.RunScript(ctx, scriptID, sheets.ScriptRequest{
+//		Function:   "sumColumn",
+//		Parameters: []interface{}{"Sheet1", "C"},
+//	}, &total)
+func (c *Client) RunScript(ctx context.Context, scriptID string, req ScriptRequest, result interface{}) error {
+	// https://developers.google.com/apps-script/api/reference/rest/v1/scripts/run
+	url := "https://script." + c.universeDomain() + fmt.Sprintf(scriptRunURL, scriptID)
+
+	var operation struct {
+		Response struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"response"`
+		Error *struct {
+			Message string        `json:"message"`
+			Details []ScriptError `json:"details"`
+		} `json:"error"`
+	}
+
+	if err := c.ReadJSON(ctx, http.MethodPost, url, req, &operation); err != nil {
+		return c.opError(err, "scripts.run", "", "", 0)
+	}
+
+	if operation.Error != nil {
+		scriptErr := &ScriptError{Function: req.Function, Message: operation.Error.Message}
+		if len(operation.Error.Details) > 0 {
+			*scriptErr = operation.Error.Details[0]
+			scriptErr.Function = req.Function
+		}
+
+		return scriptErr
+	}
+
+	if result == nil || len(operation.Response.Result) == 0 {
+		return nil
+	}
+
+	return c.codec().NewDecoder(bytes.NewReader(operation.Response.Result)).Decode(result)
+}
@@ -0,0 +1,54 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientRunScript(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/v1/scripts/script:run", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"function":"sumColumn"`) {
+			if expected, got := `{"function":"sumColumn","parameters":["Sheet1","C"]}`, string(body); expected != got {
+				t.Errorf("expected body %s but got %s", expected, got)
+			}
+
+			w.Write([]byte(`{"done":true,"response":{"@type":"type.googleapis.com/google.apps.script.v1.ExecutionResponse","result":42.5}}`))
+			return
+		}
+
+		w.Write([]byte(`{"done":true,"error":{"code":3,"message":"ScriptError","details":[{"@type":"type.googleapis.com/google.apps.script.v1.ExecutionError",
+			"errorMessage":"x is not defined","errorType":"ReferenceError","scriptStackTraceElements":[{"function":"fail","lineNumber":3}]}]}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	var total float64
+	if err := c.RunScript(ctx, "script", ScriptRequest{Function: "sumColumn", Parameters: []interface{}{"Sheet1", "C"}}, &total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 42.5 {
+		t.Fatalf("expected result 42.5 but got %v", total)
+	}
+
+	err := c.RunScript(ctx, "script", ScriptRequest{Function: "fail"}, nil)
+
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("expected a script error but got: %v", err)
+	}
+	if expected, got := "sheets: script function \"fail\": ReferenceError: x is not defined\n\tat fail:3", scriptErr.Error(); expected != got {
+		t.Fatalf("expected error %q but got %q", expected, got)
+	}
+}
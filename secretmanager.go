@@ -0,0 +1,93 @@
+package sheets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+var secretManagerURL = "https://secretmanager.googleapis.com/v1/%s:access"
+
+// ServiceAccountFromSecretManager is like `ServiceAccountJSON` but it fetches the contents of the service account file
+// from Google Secret Manager, so no key material touches the filesystem.
+// The "secretName" is the resource name of the secret, e.g. "projects/my-project/secrets/sheets-sa",
+// the latest version is used if the name does not contain a version, e.g. "projects/my-project/secrets/sheets-sa/versions/2".
+//
+// The secret is accessed with the Application Default Credentials (e.g. the runtime's service account)
+// which must have the "Secret Manager Secret Accessor" role on the secret.
+func ServiceAccountFromSecretManager(ctx context.Context, secretName string, scopes ...string) (http.RoundTripper, error) {
+	client, err := google.DefaultClient(ctx, ScopeCloudPlatform)
+	if err != nil {
+		return nil, fmt.Errorf("secret manager: unable to find default credentials: %w", err)
+	}
+
+	b, err := accessSecret(ctx, client, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ServiceAccountJSON(ctx, b, scopes...)
+}
+
+// accessSecret returns the payload of a Secret Manager secret version.
+func accessSecret(ctx context.Context, client *http.Client, secretName string) ([]byte, error) {
+	secretName = strings.Trim(secretName, "/")
+	if secretName == "" {
+		return nil, fmt.Errorf("secret manager: empty secret name")
+	}
+
+	if !strings.Contains(secretName, "/versions/") {
+		secretName += "/versions/latest"
+	}
+
+	// https://cloud.google.com/secret-manager/docs/reference/rest/v1/projects.secrets.versions/access
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(secretManagerURL, secretName), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secret manager: unable to access secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(fmt.Sprintf("secret manager: unable to access secret %q", secretName), resp)
+	}
+
+	var payload struct {
+		Payload struct {
+			Data       string `json:"data"`
+			DataCrc32c string `json:"dataCrc32c"`
+		} `json:"payload"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("secret manager: unable to parse secret: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("secret manager: unable to decode secret: %w", err)
+	}
+
+	if payload.Payload.DataCrc32c != "" {
+		expected, err := strconv.ParseUint(payload.Payload.DataCrc32c, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("secret manager: invalid checksum: %w", err)
+		}
+
+		if got := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)); uint64(got) != expected {
+			return nil, fmt.Errorf("secret manager: secret %q is corrupted", secretName)
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,46 @@
+package sheets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestAccessSecret(t *testing.T) {
+	secret := []byte(`{"type":"service_account"}`)
+
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+
+		checksum := crc32.Checksum(secret, crc32.MakeTable(crc32.Castagnoli))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"payload": map[string]string{
+				"data":       base64.StdEncoding.EncodeToString(secret),
+				"dataCrc32c": strconv.FormatUint(uint64(checksum), 10),
+			},
+		})
+	}))
+	defer srv.Close()
+
+	defer func(original string) { secretManagerURL = original }(secretManagerURL)
+	secretManagerURL = srv.URL + "/v1/%s:access"
+
+	b, err := accessSecret(context.Background(), srv.Client(), "projects/p/secrets/sa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "/v1/projects/p/secrets/sa/versions/latest:access", path; expected != got {
+		t.Fatalf("expected path %q but got %q", expected, got)
+	}
+
+	if expected, got := string(secret), string(b); expected != got {
+		t.Fatalf("expected secret %q but got %q", expected, got)
+	}
+}
@@ -0,0 +1,174 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+)
+
+// SpreadsheetService is a spreadsheet-scoped handle of a Client.
+// It calls the Client's methods without the need to pass the spreadsheet ID on each one of them.
+//
+// See `Client.Spreadsheet` method.
+type SpreadsheetService struct {
+	client *Client
+	// ID is the spreadsheet ID.
+	ID string
+}
+
+// Spreadsheet returns a new handle for the spreadsheet of "spreadsheetID".
+func (c *Client) Spreadsheet(spreadsheetID string) *SpreadsheetService {
+	return &SpreadsheetService{client: c, ID: spreadsheetID}
+}
+
+// Client returns the Client of this spreadsheet handle.
+func (s *SpreadsheetService) Client() *Client {
+	return s.client
+}
+
+// Info returns general information about the spreadsheet.
+// See `Client.GetSpreadsheetInfo` method.
+func (s *SpreadsheetService) Info(ctx context.Context) (*Spreadsheet, error) {
+	return s.client.GetSpreadsheetInfo(ctx, s.ID)
+}
+
+// Sheet returns a new handle for the sheet of "title" of the spreadsheet.
+func (s *SpreadsheetService) Sheet(title string) *SheetService {
+	return &SheetService{spreadsheet: s, Title: title}
+}
+
+// Values returns record values of the spreadsheet based on the provided "dataRanges".
+// See `Client.Range` method.
+func (s *SpreadsheetService) Values(ctx context.Context, dataRanges ...string) ([]ValueRange, error) {
+	return s.client.Range(ctx, s.ID, dataRanges...)
+}
+
+// Read binds record values of the spreadsheet to the "dest".
+// See `Client.ReadSpreadsheet` method.
+func (s *SpreadsheetService) Read(ctx context.Context, dest interface{}, dataRanges ...string) error {
+	return s.client.ReadSpreadsheet(ctx, dest, s.ID, dataRanges...)
+}
+
+// Update updates the spreadsheet values of a range.
+// See `Client.UpdateSpreadsheet` method.
+func (s *SpreadsheetService) Update(ctx context.Context, values ValueRange) (UpdateValuesResponse, error) {
+	return s.client.UpdateSpreadsheet(ctx, s.ID, values)
+}
+
+// Clear clears the spreadsheet values of a range.
+// See `Client.ClearSpreadsheet` method.
+func (s *SpreadsheetService) Clear(ctx context.Context, dataRange string) (ClearValuesResponse, error) {
+	return s.client.ClearSpreadsheet(ctx, s.ID, dataRange)
+}
+
+// AddChart creates or updates an existing chart of the spreadsheet.
+// See `Client.AddChart` method.
+func (s *SpreadsheetService) AddChart(ctx context.Context, chart Chart) (BatchUpdateResponse, error) {
+	return s.client.AddChart(ctx, s.ID, chart)
+}
+
+// SheetService is a sheet-scoped handle of a spreadsheet.
+// Its methods automatically qualify the ranges with the sheet title
+// and the batch update requests with the sheet ID.
+//
+// See `SpreadsheetService.Sheet` method.
+type SheetService struct {
+	spreadsheet *SpreadsheetService
+	// Title is the sheet title.
+	Title string
+}
+
+// Spreadsheet returns the spreadsheet handle of this sheet.
+func (s *SheetService) Spreadsheet() *SpreadsheetService {
+	return s.spreadsheet
+}
+
+// Range returns the A1 notation of "cells" (e.g. "A1:B2") qualified with the sheet title.
+// If "cells" is empty then it returns a range which covers all the values of the sheet.
+func (s *SheetService) Range(cells string) string {
+	r := QuoteSheetTitle(s.Title)
+	if cells != "" {
+		r += "!" + cells
+	}
+
+	return r
+}
+
+// Properties fetches and returns the sheet's information.
+func (s *SheetService) Properties(ctx context.Context) (Sheet, error) {
+	sd, err := s.spreadsheet.Info(ctx)
+	if err != nil {
+		return Sheet{}, err
+	}
+
+	sheet, ok := sd.GetSheet(s.Title)
+	if !ok {
+		return Sheet{}, fmt.Errorf("sheet %q not found in spreadsheet %q", s.Title, s.spreadsheet.ID)
+	}
+
+	return sheet, nil
+}
+
+// Rows returns all the rows of the sheet.
+func (s *SheetService) Rows(ctx context.Context) ([][]interface{}, error) {
+	valueRanges, err := s.spreadsheet.Values(ctx, s.Range(""))
+	if err != nil {
+		return nil, err
+	}
+
+	return valueRanges[0].Values, nil
+}
+
+// Read binds all the rows of the sheet to the "dest".
+func (s *SheetService) Read(ctx context.Context, dest interface{}) error {
+	return s.spreadsheet.Read(ctx, dest, s.Range(""))
+}
+
+// ReadColumns binds the data rows of the sheet to the "dest", fetching only the columns mapped to its struct fields.
+// See `Client.ReadColumns` method.
+func (s *SheetService) ReadColumns(ctx context.Context, dest interface{}) error {
+	return s.spreadsheet.client.ReadColumns(ctx, dest, s.spreadsheet.ID, s.Title)
+}
+
+// AppendRow appends a row after the last row of the sheet.
+// The "v" can be a row of values or a struct value,
+// its fields are written in the same order as they are decoded.
+func (s *SheetService) AppendRow(ctx context.Context, v interface{}) (AppendValuesResponse, error) {
+	row, err := encodeRow(v)
+	if err != nil {
+		return AppendValuesResponse{}, err
+	}
+
+	return s.spreadsheet.client.AppendSpreadsheet(ctx, s.spreadsheet.ID, ValueRange{
+		Range:          s.Range("A1"),
+		MajorDimension: Rows,
+		Values:         [][]interface{}{row},
+	})
+}
+
+// NextRow returns the one-based number of the first empty row after the table of the sheet.
+// See `Client.NextRow` method.
+func (s *SheetService) NextRow(ctx context.Context) (int, error) {
+	return s.spreadsheet.client.NextRow(ctx, s.spreadsheet.ID, s.Title)
+}
+
+// ColumnRange returns the data range of the column with the "headerName" in the header row of the sheet.
+// See `Client.ColumnRange` method.
+func (s *SheetService) ColumnRange(ctx context.Context, headerName string) (string, error) {
+	return s.spreadsheet.client.ColumnRange(ctx, s.spreadsheet.ID, s.Title, headerName)
+}
+
+// Clear clears all the values of the sheet.
+func (s *SheetService) Clear(ctx context.Context) (ClearValuesResponse, error) {
+	return s.spreadsheet.Clear(ctx, s.Range(""))
+}
+
+// Resize sets the number of rows and columns of the sheet.
+// A zero "rows" or "columns" value keeps the current value.
+func (s *SheetService) Resize(ctx context.Context, rows, columns int) (BatchUpdateResponse, error) {
+	sheet, err := s.Properties(ctx)
+	if err != nil {
+		return BatchUpdateResponse{}, err
+	}
+
+	return s.spreadsheet.client.ResizeSheet(ctx, s.spreadsheet.ID, sheet.Properties.ID, rows, columns)
+}
@@ -0,0 +1,263 @@
+package sheets_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kataras/sheets"
+	"github.com/kataras/sheets/sheetstest"
+)
+
+type user struct {
+	Name     string
+	Username string
+	Ignored  string `sheets:"-"`
+}
+
+func TestSheetService(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Other", "My Users")
+	srv.SetValues("test", "My Users", [][]interface{}{{"makis", "kataras"}})
+
+	ctx := context.Background()
+	sheet := srv.Client().Spreadsheet("test").Sheet("My Users")
+
+	if _, err := sheet.AppendRow(ctx, user{Name: "giwrgos", Username: "giwrgos1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sheet.AppendRow(ctx, []interface{}{"efi", "efi2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var users []user
+	if err := sheet.Read(ctx, &users); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []user{{"makis", "kataras", ""}, {"giwrgos", "giwrgos1", ""}, {"efi", "efi2", ""}}
+	if !reflect.DeepEqual(expected, users) {
+		t.Fatalf("expected users %v but got %v", expected, users)
+	}
+
+	if _, err := sheet.Resize(ctx, 10, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	props, err := sheet.Properties(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := (sheets.SheetGrid{RowCount: 10, ColumnCount: 5}), props.Properties.Grid; expected != got {
+		t.Fatalf("expected grid %v but got %v", expected, got)
+	}
+
+	if _, err = sheet.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := sheet.Rows(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 0 {
+		t.Fatalf("expected no rows but got %v", rows)
+	}
+
+	if got := srv.Values("test", "Other"); got != nil {
+		t.Fatalf("expected other sheet to be untouched but got %v", got)
+	}
+}
+
+func TestSheetServiceNextRow(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Empty", "Users")
+	srv.SetValues("test", "Users", [][]interface{}{{"Name"}, {"makis"}, {"giwrgos"}})
+
+	ctx := context.Background()
+	spreadsheet := srv.Client().Spreadsheet("test")
+
+	for title, expected := range map[string]int{"Empty": 1, "Users": 4} {
+		row, err := spreadsheet.Sheet(title).NextRow(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected != row {
+			t.Fatalf("[%s] expected next row %d but got %d", title, expected, row)
+		}
+	}
+
+	if got := srv.Values("test", "Users"); len(got) != 3 {
+		t.Fatalf("expected no values to be written but got %v", got)
+	}
+}
+
+func TestSheetServiceColumnRange(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "My Users")
+	srv.SetValues("test", "My Users", [][]interface{}{{"Name", " Age ", "Email"}, {"makis", 27, "makis@example.com"}})
+
+	ctx := context.Background()
+	sheet := srv.Client().Spreadsheet("test").Sheet("My Users")
+
+	for headerName, expected := range map[string]string{"Name": "'My Users'!A2:A", "Age": "'My Users'!B2:B", "Email": "'My Users'!C2:C"} {
+		got, err := sheet.ColumnRange(ctx, headerName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected != got {
+			t.Fatalf("[%s] expected range %q but got %q", headerName, expected, got)
+		}
+	}
+
+	if _, err := sheet.ColumnRange(ctx, "Phone"); !errors.Is(err, sheets.ErrNotFound) {
+		t.Fatalf("expected a not found error but got: %v", err)
+	}
+}
+
+func TestSheetReaderWriter(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Users")
+
+	ctx := context.Background()
+	client := srv.Client()
+
+	w := sheets.NewSheetWriter(ctx, client, "test", "Users!A1")
+	w.BatchSize = 2
+	w.Write([]string{"Name", "Age"})
+	w.Write([]string{"makis", "27"})
+	if got := srv.Values("test", "Users"); len(got) != 2 {
+		t.Fatalf("expected a full batch to be flushed but got %v", got)
+	}
+
+	if err := w.WriteAll([][]string{{"giwrgos", "30"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := sheets.NewSheetReader(ctx, client, "test", "Users").ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"Name", "Age"}, {"makis", "27"}, {"giwrgos", "30"}}
+	if !reflect.DeepEqual(expected, records) {
+		t.Fatalf("expected records %v but got %v", expected, records)
+	}
+
+	if _, err = sheets.NewSheetReader(ctx, client, "test", "Missing").Read(); err == nil {
+		t.Fatal("expected an error for a missing sheet")
+	}
+}
+
+func TestClientAppendFromCSV(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Users")
+
+	var progress []int
+	input := "Name,Age\nmakis,27\ngiwrgos,30\nefi,25\n"
+	n, err := srv.Client().AppendFromCSV(context.Background(), "test", "Users", strings.NewReader(input), 3, func(rows int) {
+		progress = append(progress, rows)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 4 {
+		t.Fatalf("expected 4 rows to be appended but got %d", n)
+	}
+	if expected := []int{3, 4}; !reflect.DeepEqual(expected, progress) {
+		t.Fatalf("expected progress %v but got %v", expected, progress)
+	}
+
+	expected := [][]interface{}{{"Name", "Age"}, {"makis", "27"}, {"giwrgos", "30"}, {"efi", "25"}}
+	if got := srv.Values("test", "Users"); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected values %v but got %v", expected, got)
+	}
+
+	n, err = srv.Client().AppendFromCSV(context.Background(), "test", "Users", strings.NewReader("a,b\n\"c"), 1, nil)
+	if err == nil || n != 1 {
+		t.Fatalf("expected a CSV error after 1 row but got %d rows and error: %v", n, err)
+	}
+}
+
+type syncUser struct {
+	Email string
+	Name  string
+	Age   int
+}
+
+func TestClientSync(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Users", "Empty")
+	srv.SetValues("test", "Users", [][]interface{}{
+		{"Name", "Note", "Email", "Age"},
+		{"makis", "=A2", "makis@example.com", "27"},
+		{"giwrgos", "", "giwrgos@example.com", "30"},
+		{"old", "", "old@example.com", "99"},
+		{"efi", "kept", "efi@example.com", "25"},
+	})
+
+	ctx := context.Background()
+	client := srv.Client()
+
+	users := []syncUser{
+		{Email: "makis@example.com", Name: "makis", Age: 28},
+		{Email: "efi@example.com", Name: "efi", Age: 25},
+		{Email: "giwrgos@example.com", Name: "george", Age: 30},
+		{Email: "new@example.com", Name: "new", Age: 20},
+	}
+
+	result, err := client.Sync(ctx, "test", "Users", users, sheets.SyncOptions{Key: "Email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (sheets.SyncResult{Added: 1, Updated: 2, UpdatedCells: 2, Orphans: 1}); expected != result {
+		t.Fatalf("expected result %#v but got %#v", expected, result)
+	}
+
+	result, err = client.Sync(ctx, "test", "Users", users, sheets.SyncOptions{Key: "Email", DeleteOrphans: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (sheets.SyncResult{Deleted: 1}); expected != result {
+		t.Fatalf("expected result %#v but got %#v", expected, result)
+	}
+
+	expected := [][]interface{}{
+		{"Name", "Note", "Email", "Age"},
+		{"makis", "=A2", "makis@example.com", 28.0},
+		{"george", "", "giwrgos@example.com", "30"},
+		{"efi", "kept", "efi@example.com", "25"},
+		{"new", nil, "new@example.com", 20.0},
+	}
+	if got := srv.Values("test", "Users"); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected values %v but got %v", expected, got)
+	}
+
+	result, err = client.Sync(ctx, "test", "Empty", users[:1], sheets.SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 1 {
+		t.Fatalf("expected 1 added row but got %#v", result)
+	}
+	if got := srv.Values("test", "Empty"); len(got) != 2 || got[0][0] != "Email" {
+		t.Fatalf("expected a header row and a value row but got %v", got)
+	}
+}
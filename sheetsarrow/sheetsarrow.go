@@ -0,0 +1,330 @@
+// Package sheetsarrow converts value ranges into Apache Arrow records and Parquet files,
+// so analytics pipelines can land sheet data directly in a lakehouse.
+// It's a separate module, so the users of the sheets package do not depend on Apache Arrow.
+//
+// The column types are declared through `Options.Columns` (see `QueryColumns` for the types of a `sheets.Client.Query` result)
+// or inferred from the cell values: a column of bools is a boolean one, a column of numbers is a float64 one
+// and any other column is a string one. Empty cells are nulls.
+//
+// Usage:
+//
+//	valueRanges, err := client.Range(ctx, spreadsheetID, "Orders")
+//	f, err := os.Create("orders.parquet")
+//	err = sheetsarrow.WriteParquet(f, valueRanges[0], sheetsarrow.Options{Header: true})
+package sheetsarrow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/kataras/sheets"
+)
+
+// Column is a column of the Arrow schema.
+type Column struct {
+	// Name is the field name of the column.
+	Name string
+	// Type is the Arrow type of the column, one of `arrow.BinaryTypes.String`, `arrow.PrimitiveTypes.Float64`,
+	// `arrow.PrimitiveTypes.Int64`, `arrow.FixedWidthTypes.Boolean`, `arrow.FixedWidthTypes.Date32`
+	// or an `*arrow.TimestampType`, e.g. `arrow.FixedWidthTypes.Timestamp_us`.
+	Type arrow.DataType
+}
+
+// Options holds the configuration of the conversion.
+type Options struct {
+	// Header, if true, reports that the first row holds the column names, it's not converted.
+	Header bool
+	// Columns declares the columns in the order of the cells.
+	// Defaults to the header names (or the column letters, e.g. "A", if Header is false)
+	// with types inferred from the values.
+	Columns []Column
+	// Allocator is the memory allocator of the Arrow arrays.
+	// Defaults to `memory.DefaultAllocator`.
+	Allocator memory.Allocator
+}
+
+// QueryColumns returns the columns of a Google Visualization API query result, see `sheets.Client.Query`.
+// The "number" columns are float64 ones, the "boolean" are boolean ones, the "date" and "datetime"
+// are microsecond timestamp ones and the rest are string ones.
+func QueryColumns(columns []sheets.QueryColumn) []Column {
+	result := make([]Column, len(columns))
+	for i, column := range columns {
+		name := column.Label
+		if name == "" {
+			name = column.ID
+		}
+
+		var typ arrow.DataType = arrow.BinaryTypes.String
+		switch column.Type {
+		case "number":
+			typ = arrow.PrimitiveTypes.Float64
+		case "boolean":
+			typ = arrow.FixedWidthTypes.Boolean
+		case "date", "datetime":
+			typ = arrow.FixedWidthTypes.Timestamp_us
+		}
+
+		result[i] = Column{Name: name, Type: typ}
+	}
+
+	return result
+}
+
+// Schema returns the Arrow schema of the "values", see `Options.Columns`.
+func Schema(values sheets.ValueRange, opts Options) *arrow.Schema {
+	columns := opts.Columns
+	if columns == nil {
+		columns = inferColumns(values, opts.Header)
+	}
+
+	fields := make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.Name, Type: column.Type, Nullable: true}
+	}
+
+	return arrow.NewSchema(fields, nil)
+}
+
+// NewRecord converts the "values" into an Arrow record of the `Schema`.
+// The caller should call its Release method.
+// It returns an error if a cell cannot be converted to the type of its column.
+func NewRecord(values sheets.ValueRange, opts Options) (arrow.Record, error) {
+	mem := opts.Allocator
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+
+	schema := Schema(values, opts)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	rows, offset := values.Values, 1 // the one-based row numbers of the range, for the errors.
+	if opts.Header && len(rows) > 0 {
+		rows, offset = rows[1:], 2
+	}
+
+	for i, field := range schema.Fields() {
+		fb := b.Field(i)
+		for r, row := range rows {
+			var cell interface{}
+			if i < len(row) {
+				cell = row[i]
+			}
+
+			if err := appendCell(fb, field.Type, cell); err != nil {
+				return nil, fmt.Errorf("sheetsarrow: row %d: column %q: %w", r+offset, field.Name, err)
+			}
+		}
+	}
+
+	return b.NewRecord(), nil
+}
+
+// WriteParquet converts the "values" into an Arrow record (see `NewRecord`)
+// and writes it to "w" as a Parquet file of a single row group.
+func WriteParquet(w io.Writer, values sheets.ValueRange, opts Options) error {
+	rec, err := NewRecord(values, opts)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), w, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+
+	if err = fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+
+	return fw.Close()
+}
+
+// inferColumns returns the columns of the "values", their types are inferred from their non-empty cells.
+func inferColumns(values sheets.ValueRange, header bool) []Column {
+	rows := values.Values
+
+	var names []interface{}
+	if header && len(rows) > 0 {
+		names, rows = rows[0], rows[1:]
+	}
+
+	n := len(names)
+	for _, row := range rows {
+		if len(row) > n {
+			n = len(row)
+		}
+	}
+
+	columns := make([]Column, n)
+	for i := range columns {
+		name := ""
+		if i < len(names) {
+			name = formatCell(names[i])
+		}
+		if name == "" {
+			name = sheets.IndexToColumn(i)
+		}
+
+		columns[i] = Column{Name: name, Type: inferType(rows, i)}
+	}
+
+	return columns
+}
+
+// inferType returns the Arrow type of the column "col" of the "rows".
+func inferType(rows [][]interface{}, col int) arrow.DataType {
+	var bools, numbers, others int
+	for _, row := range rows {
+		if col >= len(row) {
+			continue
+		}
+
+		switch row[col].(type) {
+		case nil:
+		case bool:
+			bools++
+		case float64, json.Number:
+			numbers++
+		case string:
+			if row[col] != "" {
+				others++
+			}
+		default:
+			others++
+		}
+	}
+
+	switch {
+	case others == 0 && numbers == 0 && bools > 0:
+		return arrow.FixedWidthTypes.Boolean
+	case others == 0 && bools == 0 && numbers > 0:
+		return arrow.PrimitiveTypes.Float64
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// appendCell appends the "cell" value, converted to "typ", to the "b" builder.
+func appendCell(b array.Builder, typ arrow.DataType, cell interface{}) error {
+	if cell == nil || cell == "" {
+		b.AppendNull()
+		return nil
+	}
+
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		b.Append(formatCell(cell))
+	case *array.Float64Builder:
+		v, err := toFloat(cell)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.Int64Builder:
+		v, err := toFloat(cell)
+		if err != nil {
+			return err
+		}
+		if v != float64(int64(v)) {
+			return fmt.Errorf("%v is not an integer", cell)
+		}
+		b.Append(int64(v))
+	case *array.BooleanBuilder:
+		v, ok := cell.(bool)
+		if !ok {
+			var err error
+			if v, err = strconv.ParseBool(formatCell(cell)); err != nil {
+				return err
+			}
+		}
+		b.Append(v)
+	case *array.TimestampBuilder:
+		t, err := toTime(cell)
+		if err != nil {
+			return err
+		}
+		v, err := arrow.TimestampFromTime(t, typ.(*arrow.TimestampType).Unit)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.Date32Builder:
+		t, err := toTime(cell)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Date32FromTime(t))
+	default:
+		return fmt.Errorf("unsupported column type %s", typ)
+	}
+
+	return nil
+}
+
+// formatCell returns the text of a cell value as it's read from the API.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toFloat(cell interface{}) (float64, error) {
+	switch v := cell.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	default:
+		return strconv.ParseFloat(formatCell(cell), 64)
+	}
+}
+
+// timeLayouts are the layouts of the date and time cell values, like the sheets package decodes them.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// serialEpoch is the epoch of the serial numbers of date and time cell values,
+// which are returned on the "UNFORMATTED_VALUE" value render option.
+var serialEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+func toTime(cell interface{}) (time.Time, error) {
+	switch v := cell.(type) {
+	case time.Time:
+		return v, nil
+	case float64:
+		return serialEpoch.Add(time.Duration(v * float64(24*time.Hour))), nil
+	}
+
+	s := formatCell(cell)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a date or time", s)
+}
@@ -0,0 +1,111 @@
+package sheetsarrow
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/kataras/sheets"
+)
+
+var testValues = sheets.ValueRange{
+	Range: "Orders!A1:D4",
+	Values: [][]interface{}{
+		{"ID", "Amount", "Paid", "Date"},
+		{"order-1", float64(10.5), true, "2024-01-01"},
+		{"order-2", float64(20), false},
+		{"order-3", "", nil, "2024-01-03"},
+	},
+}
+
+func TestNewRecordInferred(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec, err := NewRecord(testValues, Options{Header: true, Allocator: mem})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	expected := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "Amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "Paid", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "Date", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	if !expected.Equal(rec.Schema()) {
+		t.Fatalf("expected schema %s but got %s", expected, rec.Schema())
+	}
+
+	if expected, got := int64(3), rec.NumRows(); expected != got {
+		t.Fatalf("expected %d rows but got %d", expected, got)
+	}
+
+	amounts := rec.Column(1).(*array.Float64)
+	if amounts.Value(0) != 10.5 || amounts.Value(1) != 20 || !amounts.IsNull(2) {
+		t.Fatalf("unexpected amounts: %s", amounts)
+	}
+
+	if paid := rec.Column(2).(*array.Boolean); !paid.Value(0) || paid.Value(1) || !paid.IsNull(2) {
+		t.Fatalf("unexpected paid values: %s", paid)
+	}
+
+	if dates := rec.Column(3).(*array.String); !dates.IsNull(1) || dates.Value(2) != "2024-01-03" {
+		t.Fatalf("unexpected dates: %s", dates)
+	}
+}
+
+func TestNewRecordDeclared(t *testing.T) {
+	columns := QueryColumns([]sheets.QueryColumn{
+		{ID: "A", Label: "ID", Type: "string"},
+		{ID: "B", Label: "Amount", Type: "number"},
+		{ID: "C", Label: "Paid", Type: "boolean"},
+		{ID: "D", Type: "date"},
+	})
+
+	rec, err := NewRecord(testValues, Options{Header: true, Columns: columns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	if expected, got := "D", rec.Schema().Field(3).Name; expected != got {
+		t.Fatalf("expected the column ID %q as the name of an unlabeled column but got %q", expected, got)
+	}
+
+	dates := rec.Column(3).(*array.Timestamp)
+	expected, _ := arrow.TimestampFromTime(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), arrow.Microsecond)
+	if dates.Value(0) != expected || !dates.IsNull(1) {
+		t.Fatalf("unexpected dates: %s", dates)
+	}
+
+	columns[0].Type = arrow.PrimitiveTypes.Int64
+	if _, err = NewRecord(testValues, Options{Header: true, Columns: columns}); err == nil {
+		t.Fatal("expected an error for a text cell of an integer column")
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, testValues, Options{Header: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(buf.Bytes()),
+		parquet.NewReaderProperties(memory.DefaultAllocator), pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Release()
+
+	if table.NumRows() != 3 || table.NumCols() != 4 {
+		t.Fatalf("expected 3 rows and 4 columns but got %d rows and %d columns", table.NumRows(), table.NumCols())
+	}
+}
@@ -0,0 +1,395 @@
+package sheetsql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type statementKind int
+
+const (
+	selectStatement statementKind = iota + 1
+	insertStatement
+)
+
+// statement is a parsed SQL statement.
+type statement struct {
+	kind  statementKind
+	table string
+	// columns are the selected or inserted columns, nil means all (*) or all in sheet order.
+	columns []string
+	where   []condition
+	limit   int // -1 means no limit.
+	values  [][]operand
+	// numInput is the number of "?" placeholders.
+	numInput int
+}
+
+// condition is a "column op value" expression of a WHERE clause.
+type condition struct {
+	column string
+	op     string // "=" or "!=".
+	value  operand
+}
+
+// operand is a literal value or a "?" placeholder.
+type operand struct {
+	ordinal int // one-based placeholder ordinal, zero for a literal.
+	literal interface{}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// quoted reports whether an identifier was quoted, quoted identifiers are never keywords.
+	quoted bool
+}
+
+// tokenize splits a SQL statement into tokens.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			text, n, err := readQuoted(query[i:], c)
+			if err != nil {
+				return nil, err
+			}
+
+			if c == '\'' {
+				tokens = append(tokens, token{kind: tokenString, text: text})
+			} else {
+				tokens = append(tokens, token{kind: tokenIdent, text: text, quoted: true})
+			}
+			i += n
+		case isDigit(c) || (c == '-' || c == '.') && i+1 < len(query) && isDigit(query[i+1]):
+			j := i + 1
+			for j < len(query) && (isDigit(query[j]) || query[j] == '.' || query[j] == 'e' || query[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: query[i:j]})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(query) && (isIdentStart(query[j]) || isDigit(query[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: query[i:j]})
+			i = j
+		case c == '!' || c == '<':
+			if i+1 < len(query) && (query[i+1] == '=' || c == '<' && query[i+1] == '>') {
+				tokens = append(tokens, token{kind: tokenSymbol, text: "!="})
+				i += 2
+				continue
+			}
+			return nil, fmt.Errorf("sheetsql: unexpected %q at %d", c, i)
+		case strings.IndexByte("(),*=?;", c) >= 0:
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("sheetsql: unexpected %q at %d", c, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+// readQuoted reads a quoted string or identifier, the quote is escaped by doubling it.
+// It returns the unquoted text and the number of bytes read.
+func readQuoted(s string, quote byte) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+
+		return b.String(), i + 1, nil
+	}
+
+	return "", 0, fmt.Errorf("sheetsql: unterminated %c quote", quote)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// parser parses the supported subset of SQL:
+//
+//	SELECT * | column [, column...] FROM table [WHERE column = value [AND ...]] [LIMIT n]
+//	INSERT INTO table [(column [, column...])] VALUES (value [, value...]) [, (...)]
+type parser struct {
+	tokens []token
+	pos    int
+	stmt   *statement
+}
+
+func parse(query string) (*statement, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, stmt: &statement{limit: -1}}
+
+	switch {
+	case p.keyword("SELECT"):
+		err = p.parseSelect()
+	case p.keyword("INSERT"):
+		err = p.parseInsert()
+	default:
+		err = fmt.Errorf("sheetsql: unsupported statement %q, only SELECT and INSERT are supported", query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.symbol(";")
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("sheetsql: unexpected %q", t.text)
+	}
+
+	return p.stmt, nil
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// keyword consumes the next token if it's the (case-insensitive) keyword "kw".
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokenIdent && !t.quoted && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// symbol consumes the next token if it's the symbol "s".
+func (p *parser) symbol(s string) bool {
+	if t := p.peek(); t.kind == tokenSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.keyword(kw) {
+		return fmt.Errorf("sheetsql: expected %s but got %q", kw, p.peek().text)
+	}
+
+	return nil
+}
+
+func (p *parser) expectSymbol(s string) error {
+	if !p.symbol(s) {
+		return fmt.Errorf("sheetsql: expected %q but got %q", s, p.peek().text)
+	}
+
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return "", fmt.Errorf("sheetsql: expected an identifier but got %q", t.text)
+	}
+
+	return t.text, nil
+}
+
+func (p *parser) identList() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		if !p.symbol(",") {
+			return names, nil
+		}
+	}
+}
+
+func (p *parser) operand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return operand{literal: t.text}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return operand{}, fmt.Errorf("sheetsql: invalid number %q", t.text)
+		}
+		return operand{literal: n}, nil
+	case tokenIdent:
+		switch {
+		case t.quoted:
+		case strings.EqualFold(t.text, "TRUE"):
+			return operand{literal: true}, nil
+		case strings.EqualFold(t.text, "FALSE"):
+			return operand{literal: false}, nil
+		case strings.EqualFold(t.text, "NULL"):
+			return operand{literal: nil}, nil
+		}
+	case tokenSymbol:
+		if t.text == "?" {
+			p.stmt.numInput++
+			return operand{ordinal: p.stmt.numInput}, nil
+		}
+	}
+
+	return operand{}, fmt.Errorf("sheetsql: expected a value but got %q", t.text)
+}
+
+func (p *parser) parseSelect() (err error) {
+	p.stmt.kind = selectStatement
+
+	if !p.symbol("*") {
+		if p.stmt.columns, err = p.identList(); err != nil {
+			return
+		}
+	}
+
+	if err = p.expectKeyword("FROM"); err != nil {
+		return
+	}
+	if p.stmt.table, err = p.ident(); err != nil {
+		return
+	}
+
+	if p.keyword("WHERE") {
+		for {
+			var cond condition
+			if cond.column, err = p.ident(); err != nil {
+				return
+			}
+
+			switch {
+			case p.symbol("="):
+				cond.op = "="
+			case p.symbol("!="):
+				cond.op = "!="
+			default:
+				return fmt.Errorf("sheetsql: unsupported operator %q, only = and != are supported", p.peek().text)
+			}
+
+			if cond.value, err = p.operand(); err != nil {
+				return
+			}
+			p.stmt.where = append(p.stmt.where, cond)
+
+			if !p.keyword("AND") {
+				break
+			}
+		}
+	}
+
+	if p.keyword("LIMIT") {
+		t := p.next()
+		if p.stmt.limit, err = strconv.Atoi(t.text); err != nil || t.kind != tokenNumber || p.stmt.limit < 0 {
+			return fmt.Errorf("sheetsql: invalid limit %q", t.text)
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseInsert() (err error) {
+	p.stmt.kind = insertStatement
+
+	if err = p.expectKeyword("INTO"); err != nil {
+		return
+	}
+	if p.stmt.table, err = p.ident(); err != nil {
+		return
+	}
+
+	if p.symbol("(") {
+		if p.stmt.columns, err = p.identList(); err != nil {
+			return
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return
+		}
+	}
+
+	if err = p.expectKeyword("VALUES"); err != nil {
+		return
+	}
+
+	for {
+		if err = p.expectSymbol("("); err != nil {
+			return
+		}
+
+		var row []operand
+		for {
+			v, err := p.operand()
+			if err != nil {
+				return err
+			}
+			row = append(row, v)
+
+			if !p.symbol(",") {
+				break
+			}
+		}
+
+		if err = p.expectSymbol(")"); err != nil {
+			return
+		}
+
+		if p.stmt.columns != nil && len(row) != len(p.stmt.columns) {
+			return fmt.Errorf("sheetsql: %d values for %d columns", len(row), len(p.stmt.columns))
+		}
+		p.stmt.values = append(p.stmt.values, row)
+
+		if !p.symbol(",") {
+			return nil
+		}
+	}
+}
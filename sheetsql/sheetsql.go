@@ -0,0 +1,451 @@
+// Package sheetsql provides a database/sql driver over Google Sheets.
+// Each sheet of a spreadsheet is a table and its first row holds the column names.
+// It supports a small subset of SQL, enough for quick internal tools built on sql-centric code:
+//
+//	SELECT * | column [, column...] FROM table [WHERE column = value [AND ...]] [LIMIT n]
+//	INSERT INTO table [(column [, column...])] VALUES (value [, value...]) [, (...)]
+//
+// SELECT reads the whole sheet and filters the rows locally, INSERT appends rows after the table.
+// Values can be "?" placeholders or literals. Transactions are not supported.
+//
+// Usage:
+//
+//	db, err := sql.Open("sheets", "SPREADSHEET_ID?credentials=service_account.json")
+//	// or, with an existing sheets.Client:
+//	db := sql.OpenDB(sheetsql.NewConnector(client, "SPREADSHEET_ID"))
+//
+//	rows, err := db.QueryContext(ctx, `SELECT Name, Email FROM "My Users" WHERE Active = TRUE`)
+package sheetsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/sheets"
+)
+
+// DriverName is the name the driver is registered with.
+const DriverName = "sheets"
+
+func init() {
+	sql.Register(DriverName, Driver{})
+}
+
+// Driver is the database/sql driver over Google Sheets.
+// The data source name is the spreadsheet ID followed by a "credentials" query parameter
+// which holds the path of a service account file, e.g. "SPREADSHEET_ID?credentials=service_account.json".
+// See `NewConnector` package-level function to use an existing `sheets.Client` instead.
+type Driver struct{}
+
+var (
+	_ driver.Driver        = Driver{}
+	_ driver.DriverContext = Driver{}
+)
+
+// Open implements the `driver.Driver` interface.
+func (d Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector implements the `driver.DriverContext` interface.
+func (Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	spreadsheetID, rawQuery, _ := strings.Cut(dsn, "?")
+	if spreadsheetID == "" {
+		return nil, fmt.Errorf("sheetsql: missing spreadsheet ID")
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("sheetsql: invalid data source name: %w", err)
+	}
+
+	credentialsFile := query.Get("credentials")
+	if credentialsFile == "" {
+		return nil, fmt.Errorf("sheetsql: missing credentials file")
+	}
+
+	authentication, err := sheets.ServiceAccountE(context.Background(), credentialsFile, sheets.ScopeReadWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConnector(sheets.NewClient(authentication), spreadsheetID), nil
+}
+
+// NewConnector returns a new `driver.Connector` over the spreadsheet of "spreadsheetID"
+// which can be passed on the `sql.OpenDB` function.
+func NewConnector(client *sheets.Client, spreadsheetID string) driver.Connector {
+	return &connector{client: client, spreadsheetID: spreadsheetID}
+}
+
+type connector struct {
+	client        *sheets.Client
+	spreadsheetID string
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client, spreadsheetID: c.spreadsheetID}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return Driver{}
+}
+
+// ErrTxNotSupported is returned when a transaction is started, Google Sheets has no transactions.
+var ErrTxNotSupported = errors.New("sheetsql: transactions are not supported")
+
+type conn struct {
+	client        *sheets.Client
+	spreadsheetID string
+}
+
+var (
+	_ driver.QueryerContext = (*conn)(nil)
+	_ driver.ExecerContext  = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	st, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{conn: c, st: st}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, ErrTxNotSupported
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	st, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.query(ctx, st, args)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	st, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.exec(ctx, st, args)
+}
+
+// header reads the column names of the "table".
+func (c *conn) header(ctx context.Context, table string) ([]string, error) {
+	valueRanges, err := c.client.Range(ctx, c.spreadsheetID, sheets.WholeRow(table, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	if len(valueRanges) > 0 && len(valueRanges[0].Values) > 0 {
+		for _, cell := range valueRanges[0].Values[0] {
+			columns = append(columns, strings.TrimSpace(fmt.Sprint(cell)))
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("sheetsql: table %q has no header row", table)
+	}
+
+	return columns, nil
+}
+
+func (c *conn) query(ctx context.Context, st *statement, args []driver.NamedValue) (driver.Rows, error) {
+	if st.kind != selectStatement {
+		return nil, fmt.Errorf("sheetsql: not a query, use Exec instead")
+	}
+
+	valueRanges, err := c.client.Range(ctx, c.spreadsheetID, sheets.QuoteSheetTitle(st.table))
+	if err != nil {
+		return nil, err
+	}
+
+	var values [][]interface{}
+	if len(valueRanges) > 0 {
+		values = valueRanges[0].Values
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("sheetsql: table %q has no header row", st.table)
+	}
+
+	header := make([]string, len(values[0]))
+	for i, cell := range values[0] {
+		header[i] = strings.TrimSpace(fmt.Sprint(cell))
+	}
+
+	projection := make([]int, 0, len(header))
+	if st.columns == nil {
+		for i := range header {
+			projection = append(projection, i)
+		}
+	} else {
+		for _, column := range st.columns {
+			i, err := columnIndex(st.table, header, column)
+			if err != nil {
+				return nil, err
+			}
+			projection = append(projection, i)
+		}
+	}
+
+	type filter struct {
+		index int
+		op    string
+		value string
+	}
+
+	filters := make([]filter, len(st.where))
+	for i, cond := range st.where {
+		index, err := columnIndex(st.table, header, cond.column)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := cond.value.eval(args)
+		if err != nil {
+			return nil, err
+		}
+
+		filters[i] = filter{index: index, op: cond.op, value: format(value)}
+	}
+
+	r := &rows{columns: make([]string, len(projection))}
+	for i, index := range projection {
+		r.columns[i] = header[index]
+	}
+
+rows:
+	for _, row := range values[1:] {
+		if st.limit >= 0 && len(r.values) >= st.limit {
+			break
+		}
+
+		for _, f := range filters {
+			var cell interface{}
+			if f.index < len(row) {
+				cell = row[f.index]
+			}
+
+			if matched := format(cell) == f.value; matched != (f.op == "=") {
+				continue rows
+			}
+		}
+
+		projected := make([]interface{}, len(projection))
+		for i, index := range projection {
+			if index < len(row) {
+				projected[i] = row[index]
+			}
+		}
+		r.values = append(r.values, projected)
+	}
+
+	return r, nil
+}
+
+func (c *conn) exec(ctx context.Context, st *statement, args []driver.NamedValue) (driver.Result, error) {
+	if st.kind != insertStatement {
+		return nil, fmt.Errorf("sheetsql: not an insert statement, use Query instead")
+	}
+
+	var positions []int // the header position of each value, nil for the sheet order.
+	if st.columns != nil {
+		header, err := c.header(ctx, st.table)
+		if err != nil {
+			return nil, err
+		}
+
+		positions = make([]int, len(st.columns))
+		for i, column := range st.columns {
+			if positions[i], err = columnIndex(st.table, header, column); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	values := make([][]interface{}, len(st.values))
+	for i, operands := range st.values {
+		var row []interface{}
+		for j, op := range operands {
+			value, err := op.eval(args)
+			if err != nil {
+				return nil, err
+			}
+
+			pos := j
+			if positions != nil {
+				pos = positions[j]
+			}
+			for len(row) <= pos {
+				row = append(row, nil)
+			}
+
+			row[pos] = value
+		}
+
+		values[i] = row
+	}
+
+	_, err := c.client.AppendSpreadsheet(ctx, c.spreadsheetID, sheets.ValueRange{
+		Range:          sheets.QuoteSheetTitle(st.table) + "!A1",
+		MajorDimension: sheets.Rows,
+		Values:         values,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(len(values)), nil
+}
+
+func columnIndex(table string, header []string, column string) (int, error) {
+	for i, name := range header {
+		if name == column {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("sheetsql: column %q not found in table %q", column, table)
+}
+
+// eval returns the value of the operand, the placeholder ones are read from "args".
+func (o operand) eval(args []driver.NamedValue) (interface{}, error) {
+	if o.ordinal == 0 {
+		return o.literal, nil
+	}
+
+	for _, arg := range args {
+		if arg.Ordinal == o.ordinal {
+			return cellValue(arg.Value)
+		}
+	}
+
+	return nil, fmt.Errorf("sheetsql: missing argument $%d", o.ordinal)
+}
+
+// cellValue converts a driver value to a value the Sheets API accepts: a string, a float64, a bool or nil.
+func cellValue(v driver.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case nil, string, float64, bool:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case []byte:
+		return string(v), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// format returns the text of a cell or an argument value,
+// so that "?" arguments are compared against cells the way they are displayed.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type stmt struct {
+	conn *conn
+	st   *statement
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return s.st.numInput
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.exec(ctx, s.st, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.query(ctx, s.st, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+
+	return named
+}
+
+type rows struct {
+	columns []string
+	values  [][]interface{}
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	row := r.values[0]
+	r.values = r.values[1:]
+
+	for i := range dest {
+		dest[i] = nil
+		if i < len(row) {
+			dest[i], _ = cellValue(row[i])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package sheetsql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/sheets/sheetsql"
+	"github.com/kataras/sheets/sheetstest"
+)
+
+func TestDB(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "My Users")
+	srv.SetValues("test", "My Users", [][]interface{}{
+		{"Name", "Email", "Active"},
+		{"makis", "makis@example.com", "TRUE"},
+		{"giwrgos", "giwrgos@example.com", "FALSE"},
+	})
+
+	db := sql.OpenDB(sheetsql.NewConnector(srv.Client(), "test"))
+	defer db.Close()
+
+	ctx := context.Background()
+
+	result, err := db.ExecContext(ctx, `INSERT INTO "My Users" (Active, Name) VALUES (?, ?), (TRUE, 'o''neil')`, true, "efi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := result.RowsAffected(); n != 2 {
+		t.Fatalf("expected 2 rows affected but got %d", n)
+	}
+
+	expected := [][]interface{}{
+		{"Name", "Email", "Active"},
+		{"makis", "makis@example.com", "TRUE"},
+		{"giwrgos", "giwrgos@example.com", "FALSE"},
+		{"efi", nil, true},
+		{"o'neil", nil, true},
+	}
+	if got := srv.Values("test", "My Users"); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected values %v but got %v", expected, got)
+	}
+
+	stmt, err := db.PrepareContext(ctx, "SELECT Name, Email FROM `My Users` WHERE Active = ? AND Name != 'efi' LIMIT 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name, email sql.NullString
+		if err = rows.Scan(&name, &email); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name.String)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"makis", "o'neil"}; !reflect.DeepEqual(expected, names) {
+		t.Fatalf("expected names %v but got %v", expected, names)
+	}
+
+	var count int
+	if err = db.QueryRowContext(ctx, `SELECT Name FROM "My Users" LIMIT 1`).Scan(new(string)); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ = db.QueryContext(ctx, `SELECT * FROM "My Users"`)
+	for rows.Next() {
+		count++
+	}
+	rows.Close()
+	if count != 4 {
+		t.Fatalf("expected 4 rows but got %d", count)
+	}
+
+	if _, err = db.QueryContext(ctx, `SELECT Phone FROM "My Users"`); err == nil {
+		t.Fatal("expected an error for a missing column")
+	}
+	if _, err = db.ExecContext(ctx, `DELETE FROM "My Users"`); err == nil {
+		t.Fatal("expected an error for an unsupported statement")
+	}
+	if _, err = db.Begin(); !errors.Is(err, sheetsql.ErrTxNotSupported) {
+		t.Fatalf("expected transactions to be unsupported but got: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package sheetstest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/sheets"
+)
+
+// cellRange is a parsed A1 range, all indexes are zero-based
+// and the end indexes are exclusive.
+type cellRange struct {
+	sheet              string
+	startRow, startCol int
+	endRow, endCol     int
+}
+
+// parseRange parses an A1 notation range, e.g. "Sheet1!A1:B2", "'My Sheet'!A:C", "A1:Z" or "Sheet1".
+// A range without a sheet title refers to the first sheet.
+func (sd *spreadsheet) parseRange(s string) (cellRange, error) {
+	var title, cells string
+
+	if i := strings.LastIndexByte(s, '!'); i >= 0 {
+		title, cells = s[:i], s[i+1:]
+	} else if isCells(s) && !sd.hasSheet(s) {
+		cells = s
+	} else {
+		title = s
+	}
+
+	if len(title) > 1 && title[0] == '\'' && title[len(title)-1] == '\'' {
+		title = strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+
+	if len(sd.info.Sheets) == 0 {
+		return cellRange{}, fmt.Errorf("spreadsheet has no sheets")
+	}
+
+	var rng cellRange
+	found := false
+	for _, sheet := range sd.info.Sheets {
+		if title == "" || sheet.Properties.Title == title {
+			rng = cellRange{
+				sheet:  sheet.Properties.Title,
+				endRow: sheet.Properties.Grid.RowCount,
+				endCol: sheet.Properties.Grid.ColumnCount,
+			}
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return cellRange{}, fmt.Errorf("unable to parse range: %s", s)
+	}
+
+	if cells == "" {
+		return rng, nil
+	}
+
+	start, end := cells, ""
+	if i := strings.IndexByte(cells, ':'); i >= 0 {
+		start, end = cells[:i], cells[i+1:]
+	}
+
+	col, row, ok := parseCell(start)
+	if !ok {
+		return cellRange{}, fmt.Errorf("unable to parse range: %s", s)
+	}
+	if col >= 0 {
+		rng.startCol = col
+	}
+	if row >= 0 {
+		rng.startRow = row
+	}
+
+	if end == "" {
+		// single cell.
+		rng.endCol, rng.endRow = rng.startCol+1, rng.startRow+1
+		return rng, nil
+	}
+
+	col, row, ok = parseCell(end)
+	if !ok {
+		return cellRange{}, fmt.Errorf("unable to parse range: %s", s)
+	}
+	if col >= 0 {
+		rng.endCol = col + 1
+	}
+	if row >= 0 {
+		rng.endRow = row + 1
+	}
+
+	return rng, nil
+}
+
+// hasSheet reports whether the spreadsheet has a sheet with "title",
+// e.g. "Users" is a sheet title and not the "USERS" column.
+func (sd *spreadsheet) hasSheet(title string) bool {
+	for _, sheet := range sd.info.Sheets {
+		if sheet.Properties.Title == title {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatRange returns the A1 notation of "rng".
+func (sd *spreadsheet) formatRange(rng cellRange) string {
+	endRow, endCol := rng.endRow, rng.endCol
+	if endRow <= rng.startRow {
+		endRow = rng.startRow + 1
+	}
+	if endCol <= rng.startCol {
+		endCol = rng.startCol + 1
+	}
+
+	return fmt.Sprintf("%s!%s%d:%s%d", sheets.QuoteSheetTitle(rng.sheet),
+		columnName(rng.startCol), rng.startRow+1, columnName(endCol-1), endRow)
+}
+
+func isCells(s string) bool {
+	for _, part := range strings.Split(s, ":") {
+		if _, _, ok := parseCell(part); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseCell parses a cell reference such as "A1", "A" or "1".
+// The missing parts are reported as -1.
+func parseCell(s string) (col, row int, ok bool) {
+	i := 0
+	col = -1
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = (col+1)*26 + int(c-'A')
+	}
+
+	row = -1
+	if i < len(s) {
+		n, err := strconv.Atoi(s[i:])
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		row = n - 1
+	}
+
+	return col, row, i > 0 || row >= 0
+}
+
+// columnName returns the letters of a zero-based column index, e.g. 0 is "A" and 26 is "AA".
+func columnName(col int) string {
+	var b []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		b = append([]byte{byte('A' + (col-1)%26)}, b...)
+	}
+
+	return string(b)
+}
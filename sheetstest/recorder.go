@@ -0,0 +1,261 @@
+package sheetstest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// RecorderMode is the mode of a `Recorder`.
+type RecorderMode int
+
+const (
+	// Replay mode serves the responses from the golden file, no network requests are made.
+	Replay RecorderMode = iota
+	// Record mode sends the requests to the real API and records the interactions.
+	Record
+)
+
+// Interaction is a single recorded request and response pair.
+type Interaction struct {
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"requestHeader,omitempty"`
+	RequestBody    string      `json:"requestBody,omitempty"`
+	StatusCode     int         `json:"statusCode"`
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	ResponseBody   string      `json:"responseBody,omitempty"`
+}
+
+// DefaultScrubHeaders is the list of the headers removed from the recorded interactions.
+var DefaultScrubHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Goog-Api-Key"}
+
+// DefaultScrubQuery is the list of the URL query parameters removed from the recorded interactions.
+var DefaultScrubQuery = []string{"key", "access_token"}
+
+// Recorder is a VCR-style `http.RoundTripper`.
+// On `Record` mode it sends the requests through its Transport and records the interactions,
+// credentials are scrubbed, see `DefaultScrubHeaders` and `DefaultScrubQuery`.
+// Call its `Save` method to write them to the golden file.
+// On `Replay` mode it replays the interactions of the golden file deterministically.
+//
+// Usage:
+//
+//	rec, err := sheetstest.NewRecorder("testdata/read.json", sheetstest.Replay, nil)
+//	client := sheets.NewClient(rec)
+type Recorder struct {
+	// Mode is the recorder mode, `Replay` or `Record`.
+	Mode RecorderMode
+	// Filename is the golden file.
+	Filename string
+	// Transport is used to send the requests on `Record` mode.
+	Transport http.RoundTripper
+	// Scrub, if not nil, is called before an interaction is recorded
+	// to remove any sensitive information left.
+	Scrub func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// NewRecorder returns a new Recorder.
+// On `Replay` mode it loads the interactions from the golden "filename".
+// On `Record` mode the "transport" (the authenticated one) is used to send the requests,
+// e.g. `sheets.ServiceAccount(...)` result.
+func NewRecorder(filename string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		Mode:      mode,
+		Filename:  filename,
+		Transport: transport,
+	}
+
+	if mode == Replay {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, err
+		}
+
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip implements the `http.RoundTripper` interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body, req.Header.Get("Content-Encoding"))
+	if req.Body != nil {
+		req.Body.Close() // a RoundTripper must always close the request body.
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rawURL := scrubURL(req.URL)
+
+	if r.Mode == Replay {
+		return r.replay(req, rawURL, reqBody)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	outReq := req.Clone(req.Context())
+	outReq.Body = io.NopCloser(bytes.NewReader(reqBody))
+	outReq.ContentLength = int64(len(reqBody))
+	outReq.Header.Del("Content-Encoding")
+
+	resp, err := transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	resp.Uncompressed = true
+
+	in := Interaction{
+		Method:         req.Method,
+		URL:            rawURL,
+		RequestHeader:  scrubHeader(req.Header),
+		RequestBody:    string(reqBody),
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: scrubHeader(resp.Header),
+		ResponseBody:   string(respBody),
+	}
+	in.RequestHeader.Del("Content-Encoding")
+
+	if r.Scrub != nil {
+		r.Scrub(&in)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, rawURL string, reqBody []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Method != req.Method || in.URL != rawURL || in.RequestBody != string(reqBody) {
+			continue
+		}
+
+		r.used[i] = true
+
+		header := in.ResponseHeader.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+			StatusCode:    in.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(in.ResponseBody)),
+			ContentLength: int64(len(in.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("sheetstest: no recorded interaction for %s %s", req.Method, rawURL)
+}
+
+// Interactions returns a copy of the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the golden file.
+// It does nothing on `Replay` mode.
+func (r *Recorder) Save() error {
+	if r.Mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.Filename, b, 0644)
+}
+
+func readBody(body io.ReadCloser, encoding string) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoding == "gzip" && len(b) > 0 {
+		gr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+
+		return io.ReadAll(gr)
+	}
+
+	return b, nil
+}
+
+func scrubHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, key := range DefaultScrubHeaders {
+		h.Del(key)
+	}
+
+	return h
+}
+
+func scrubURL(u *url.URL) string {
+	c := *u
+	query := c.Query()
+	for _, key := range DefaultScrubQuery {
+		query.Del(key)
+	}
+	c.RawQuery = query.Encode()
+
+	return c.String()
+}
@@ -0,0 +1,101 @@
+package sheetstest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kataras/sheets"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := NewServer()
+	srv.SetValues("test", "Sheet1", [][]interface{}{{"makis", "27"}})
+
+	filename := filepath.Join(t.TempDir(), "golden.json")
+
+	rec, err := NewRecorder(filename, Record, srv.Transport())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	recorded, err := sheets.NewClient(authTransport{rec}).Range(ctx, "test", "A1:B1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "secret") {
+		t.Fatalf("expected credentials to be scrubbed from the golden file:\n%s", b)
+	}
+
+	// Replay without the server.
+	srv.Close()
+
+	rec, err = NewRecorder(filename, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := sheets.NewClient(authTransport{rec}).Range(ctx, "test", "A1:B1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := recorded[0].Values[0][0], replayed[0].Values[0][0]; expected != got {
+		t.Fatalf("expected replayed value %v but got %v", expected, got)
+	}
+
+	// All interactions are consumed.
+	if _, err = sheets.NewClient(authTransport{rec}).Range(ctx, "test", "A1:B1"); err == nil {
+		t.Fatal("expected an error for an unrecorded interaction")
+	}
+}
+
+type authTransport struct{ base http.RoundTripper }
+
+func (t authTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer secret")
+	return t.base.RoundTrip(r)
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRecorderClosesRequestBody(t *testing.T) {
+	rec := &Recorder{Mode: Replay}
+
+	body := &closeTracker{Reader: strings.NewReader(`{"values":[]}`)}
+	req, err := http.NewRequest(http.MethodPost, "https://sheets.googleapis.com/v4/spreadsheets/test", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = rec.RoundTrip(req); err == nil {
+		t.Fatal("expected an error for a missing interaction")
+	}
+
+	if !body.closed {
+		t.Fatal("expected the request body to be closed")
+	}
+}
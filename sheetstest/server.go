@@ -0,0 +1,595 @@
+// Package sheetstest provides an in-memory fake of the Google Sheets API
+// which can be used to test code that depends on the sheets Client
+// without live credentials or network access.
+package sheetstest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/kataras/sheets"
+)
+
+const (
+	defaultSheetTitle  = "Sheet1"
+	defaultRowCount    = 1000
+	defaultColumnCount = 26
+)
+
+// Server is an httptest-backed fake of the Google Sheets API.
+// It supports spreadsheet metadata, the values get, batchGet, update, batchUpdate, append and clear endpoints
+// and the sheet grid resize and delete rows batch update requests.
+//
+// Use its `Client` method to create a sheets Client which sends its requests to this Server.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.RWMutex
+	spreadsheets map[string]*spreadsheet
+}
+
+type spreadsheet struct {
+	info   sheets.Spreadsheet
+	values map[string][][]interface{} // by sheet title.
+}
+
+// NewServer starts and returns a new fake Sheets API Server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{spreadsheets: make(map[string]*spreadsheet)}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// AddSpreadsheet registers a new empty spreadsheet of "id" with the given sheets.
+// If no "sheetTitles" are provided then a single "Sheet1" sheet is created.
+func (s *Server) AddSpreadsheet(id string, sheetTitles ...string) {
+	if len(sheetTitles) == 0 {
+		sheetTitles = []string{defaultSheetTitle}
+	}
+
+	sd := &spreadsheet{
+		info: sheets.Spreadsheet{
+			ID:         id,
+			Properties: sheets.SpreadsheetProperties{Title: id, Locale: "en_US", Timezone: "Etc/GMT"},
+			URL:        "https://docs.google.com/spreadsheets/d/" + id + "/edit",
+		},
+		values: make(map[string][][]interface{}),
+	}
+
+	for i, title := range sheetTitles {
+		sd.info.Sheets = append(sd.info.Sheets, sheets.Sheet{
+			Properties: sheets.SheetProperties{
+				ID:        int64(i),
+				Title:     title,
+				Index:     i,
+				SheetType: sheets.Grid,
+				Grid: sheets.SheetGrid{
+					RowCount:    defaultRowCount,
+					ColumnCount: defaultColumnCount,
+				},
+			},
+		})
+	}
+
+	s.mu.Lock()
+	s.spreadsheets[id] = sd
+	s.mu.Unlock()
+}
+
+// SetValues replaces all values of a sheet, the outer slice represents the rows.
+// The spreadsheet is registered automatically if it does not exist yet.
+func (s *Server) SetValues(spreadsheetID, sheetTitle string, values [][]interface{}) {
+	s.mu.RLock()
+	_, ok := s.spreadsheets[spreadsheetID]
+	s.mu.RUnlock()
+	if !ok {
+		s.AddSpreadsheet(spreadsheetID, sheetTitle)
+	}
+
+	s.mu.Lock()
+	s.spreadsheets[spreadsheetID].values[sheetTitle] = copyValues(values)
+	s.mu.Unlock()
+}
+
+// Values returns a copy of all the values of a sheet.
+func (s *Server) Values(spreadsheetID, sheetTitle string) [][]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sd, ok := s.spreadsheets[spreadsheetID]
+	if !ok {
+		return nil
+	}
+
+	return copyValues(sd.values[sheetTitle])
+}
+
+// Transport returns an `http.RoundTripper` which redirects all requests to this Server.
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &rewriteTransport{target: target, base: s.Server.Client().Transport}
+}
+
+// Client returns a new sheets Client which sends all of its requests to this Server.
+func (s *Server) Client() *sheets.Client {
+	return sheets.NewClient(s.Transport())
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = ""
+	return t.base.RoundTrip(r)
+}
+
+// ServeHTTP implements the `http.Handler` interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/")
+	if path == r.URL.Path || path == "" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+
+	var id, rest string
+	if i := strings.IndexAny(path, "/:"); i >= 0 {
+		id, rest = path[:i], path[i:]
+	} else {
+		id = path
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sd, ok := s.spreadsheets[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, sd.info)
+	case rest == ":batchUpdate" && r.Method == http.MethodPost:
+		s.handleBatchUpdate(w, r, sd)
+	case rest == "/values:batchGet" && r.Method == http.MethodGet:
+		s.handleBatchGet(w, r, sd)
+	case rest == "/values:batchUpdate" && r.Method == http.MethodPost:
+		s.handleBatchUpdateValues(w, r, sd)
+	case strings.HasPrefix(rest, "/values/"):
+		dataRange := strings.TrimPrefix(rest, "/values/")
+		switch {
+		case strings.HasSuffix(dataRange, ":clear") && r.Method == http.MethodPost:
+			s.handleClear(w, sd, strings.TrimSuffix(dataRange, ":clear"))
+		case strings.HasSuffix(dataRange, ":append") && r.Method == http.MethodPost:
+			s.handleAppend(w, r, sd, strings.TrimSuffix(dataRange, ":append"))
+		case r.Method == http.MethodGet:
+			s.handleGet(w, sd, dataRange)
+		case r.Method == http.MethodPut:
+			s.handleUpdate(w, r, sd, dataRange)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "INVALID_ARGUMENT", "Method not allowed.")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+	}
+}
+
+// handleBatchUpdate applies the supported batch update requests,
+// the rest of them are acknowledged but ignored.
+func (s *Server) handleBatchUpdate(w http.ResponseWriter, r *http.Request, sd *spreadsheet) {
+	var payload struct {
+		Requests []struct {
+			UpdateSheetProperties *struct {
+				Properties struct {
+					SheetID int64 `json:"sheetId"`
+					Grid    struct {
+						RowCount    int `json:"rowCount"`
+						ColumnCount int `json:"columnCount"`
+					} `json:"gridProperties"`
+				} `json:"properties"`
+				Fields string `json:"fields"`
+			} `json:"updateSheetProperties"`
+			DeleteDimension *struct {
+				Range struct {
+					SheetID    int64  `json:"sheetId"`
+					Dimension  string `json:"dimension"`
+					StartIndex int    `json:"startIndex"`
+					EndIndex   int    `json:"endIndex"`
+				} `json:"range"`
+			} `json:"deleteDimension"`
+		} `json:"requests"`
+	}
+
+	if err := readJSON(r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	for _, req := range payload.Requests {
+		if req.DeleteDimension != nil {
+			rng := req.DeleteDimension.Range
+			sheet, ok := sd.sheetByID(rng.SheetID)
+			if !ok {
+				writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("No grid with id: %d", rng.SheetID))
+				return
+			}
+
+			if rng.Dimension == sheets.Rows {
+				sd.deleteRows(sheet, rng.StartIndex, rng.EndIndex)
+			}
+			continue
+		}
+
+		if req.UpdateSheetProperties == nil {
+			continue
+		}
+
+		props := req.UpdateSheetProperties.Properties
+		found := false
+		for i := range sd.info.Sheets {
+			sheet := &sd.info.Sheets[i].Properties
+			if sheet.ID != props.SheetID {
+				continue
+			}
+
+			found = true
+			for _, field := range strings.Split(req.UpdateSheetProperties.Fields, ",") {
+				switch field {
+				case "gridProperties.rowCount":
+					sheet.Grid.RowCount = props.Grid.RowCount
+				case "gridProperties.columnCount":
+					sheet.Grid.ColumnCount = props.Grid.ColumnCount
+				}
+			}
+		}
+
+		if !found {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("No grid with id: %d", props.SheetID))
+			return
+		}
+	}
+
+	writeJSON(w, sheets.BatchUpdateResponse{SpreadsheetID: sd.info.ID})
+}
+
+func (s *Server) handleBatchUpdateValues(w http.ResponseWriter, r *http.Request, sd *spreadsheet) {
+	var payload struct {
+		Data []sheets.ValueRange `json:"data"`
+	}
+	if err := readJSON(r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	response := sheets.BatchUpdateValuesResponse{SpreadsheetID: sd.info.ID}
+	for _, vr := range payload.Data {
+		rng, err := sd.parseRange(vr.Range)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+			return
+		}
+
+		updated := sd.write(rng, rng.startRow, vr.Values)
+		response.TotalUpdatedRows += updated.UpdatedRows
+		response.TotalUpdatedColumns += updated.UpdatedColumns
+		response.TotalUpdatedCells += updated.UpdatedCells
+		response.Responses = append(response.Responses, updated)
+	}
+
+	writeJSON(w, response)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, sd *spreadsheet, dataRange string) {
+	vr, err := sd.get(dataRange)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	writeJSON(w, vr)
+}
+
+func (s *Server) handleBatchGet(w http.ResponseWriter, r *http.Request, sd *spreadsheet) {
+	var payload = struct {
+		SpreadsheetID string              `json:"spreadsheetId"`
+		ValueRanges   []sheets.ValueRange `json:"valueRanges"`
+	}{SpreadsheetID: sd.info.ID}
+
+	for _, dataRange := range r.URL.Query()["ranges"] {
+		vr, err := sd.get(dataRange)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+			return
+		}
+
+		payload.ValueRanges = append(payload.ValueRanges, vr)
+	}
+
+	writeJSON(w, payload)
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request, sd *spreadsheet, dataRange string) {
+	var vr sheets.ValueRange
+	if err := readJSON(r, &vr); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	rng, err := sd.parseRange(dataRange)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	writeJSON(w, sd.write(rng, rng.startRow, vr.Values))
+}
+
+func (s *Server) handleAppend(w http.ResponseWriter, r *http.Request, sd *spreadsheet, dataRange string) {
+	var vr sheets.ValueRange
+	if err := readJSON(r, &vr); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	rng, err := sd.parseRange(dataRange)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	// The new values are written after the last non-empty row of the table.
+	row := rng.startRow
+	rows := sd.values[rng.sheet]
+	for i := len(rows) - 1; i >= rng.startRow; i-- {
+		if !isEmptyRow(rows[i], rng.startCol, math.MaxInt) {
+			row = i + 1
+			break
+		}
+	}
+
+	// Like the Sheets API, the table range is omitted when no table was found
+	// and it spans all the columns of the table, even if the range is a single cell.
+	var tableRange string
+	if row > rng.startRow {
+		table := rng
+		table.endRow = row
+		for _, r := range rows[rng.startRow:row] {
+			if len(r) > table.endCol {
+				table.endCol = len(r)
+			}
+		}
+		tableRange = sd.formatRange(table)
+	}
+
+	writeJSON(w, struct {
+		SpreadsheetID string                      `json:"spreadsheetId"`
+		TableRange    string                      `json:"tableRange,omitempty"`
+		Updates       sheets.UpdateValuesResponse `json:"updates"`
+	}{
+		SpreadsheetID: sd.info.ID,
+		TableRange:    tableRange,
+		Updates:       sd.write(rng, row, vr.Values),
+	})
+}
+
+func (s *Server) handleClear(w http.ResponseWriter, sd *spreadsheet, dataRange string) {
+	rng, err := sd.parseRange(dataRange)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	rows := sd.values[rng.sheet]
+	for i := rng.startRow; i < len(rows) && i < rng.endRow; i++ {
+		for j := rng.startCol; j < len(rows[i]) && j < rng.endCol; j++ {
+			rows[i][j] = nil
+		}
+	}
+
+	writeJSON(w, sheets.ClearValuesResponse{
+		SpreadsheetID: sd.info.ID,
+		ClearedRange:  sd.formatRange(rng),
+	})
+}
+
+// sheetByID returns the properties of the sheet with "id".
+func (sd *spreadsheet) sheetByID(id int64) (*sheets.SheetProperties, bool) {
+	for i := range sd.info.Sheets {
+		if props := &sd.info.Sheets[i].Properties; props.ID == id {
+			return props, true
+		}
+	}
+
+	return nil, false
+}
+
+// deleteRows deletes the rows from "start" (inclusive) to "end" (exclusive) of the sheet,
+// the rows below are shifted up and the grid shrinks.
+func (sd *spreadsheet) deleteRows(sheet *sheets.SheetProperties, start, end int) {
+	if end > sheet.Grid.RowCount {
+		end = sheet.Grid.RowCount
+	}
+	if start >= end {
+		return
+	}
+	sheet.Grid.RowCount -= end - start
+
+	rows := sd.values[sheet.Title]
+	if start >= len(rows) {
+		return
+	}
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	sd.values[sheet.Title] = append(rows[:start], rows[end:]...)
+}
+
+func (sd *spreadsheet) get(dataRange string) (sheets.ValueRange, error) {
+	rng, err := sd.parseRange(dataRange)
+	if err != nil {
+		return sheets.ValueRange{}, err
+	}
+
+	var values [][]interface{}
+	rows := sd.values[rng.sheet]
+	for i := rng.startRow; i < len(rows) && i < rng.endRow; i++ {
+		var row []interface{}
+		for j := rng.startCol; j < len(rows[i]) && j < rng.endCol; j++ {
+			v := rows[i][j]
+			if v == nil {
+				v = ""
+			}
+			row = append(row, v)
+		}
+
+		values = append(values, trimRow(row))
+	}
+
+	// Empty trailing rows are not included.
+	for len(values) > 0 && len(values[len(values)-1]) == 0 {
+		values = values[:len(values)-1]
+	}
+
+	return sheets.ValueRange{
+		Range:          sd.formatRange(rng),
+		MajorDimension: sheets.Rows,
+		Values:         values,
+	}, nil
+}
+
+func (sd *spreadsheet) write(rng cellRange, startRow int, values [][]interface{}) sheets.UpdateValuesResponse {
+	rows := sd.values[rng.sheet]
+	updatedRows, updatedColumns, updatedCells := 0, 0, 0
+
+	for i, row := range values {
+		r := startRow + i
+		for len(rows) <= r {
+			rows = append(rows, nil)
+		}
+
+		rowUpdated := false
+		for j, v := range row {
+			if v == nil { // null values are skipped.
+				continue
+			}
+
+			c := rng.startCol + j
+			for len(rows[r]) <= c {
+				rows[r] = append(rows[r], nil)
+			}
+
+			rows[r][c] = v
+			rowUpdated = true
+			updatedCells++
+			if j+1 > updatedColumns {
+				updatedColumns = j + 1
+			}
+		}
+
+		if rowUpdated {
+			updatedRows++
+		}
+	}
+
+	sd.values[rng.sheet] = rows
+
+	updated := rng
+	updated.startRow = startRow
+	updated.endRow = startRow + len(values)
+	updated.endCol = rng.startCol + updatedColumns
+
+	return sheets.UpdateValuesResponse{
+		SpreadsheetID:  sd.info.ID,
+		UpdatedRange:   sd.formatRange(updated),
+		UpdatedRows:    updatedRows,
+		UpdatedColumns: updatedColumns,
+		UpdatedCells:   updatedCells,
+	}
+}
+
+func trimRow(row []interface{}) []interface{} {
+	for len(row) > 0 {
+		if s, ok := row[len(row)-1].(string); !ok || s != "" {
+			break
+		}
+		row = row[:len(row)-1]
+	}
+
+	return row
+}
+
+func isEmptyRow(row []interface{}, startCol, endCol int) bool {
+	for j := startCol; j < len(row) && j < endCol; j++ {
+		if v := row[j]; v != nil && v != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func copyValues(values [][]interface{}) [][]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	c := make([][]interface{}, len(values))
+	for i, row := range values {
+		c[i] = append([]interface{}(nil), row...)
+	}
+
+	return c
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		body = gr
+	}
+
+	return json.NewDecoder(body).Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, status, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+
+	var payload struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	payload.Error.Code = statusCode
+	payload.Error.Message = message
+	payload.Error.Status = status
+
+	json.NewEncoder(w).Encode(payload)
+}
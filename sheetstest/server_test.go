@@ -0,0 +1,151 @@
+package sheetstest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/sheets"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Users", "My Sheet")
+	srv.SetValues("test", "Users", [][]interface{}{
+		{"makis", "27"},
+		{"giwrgos", "30"},
+	})
+
+	ctx := context.Background()
+	client := srv.Client()
+
+	info, err := client.GetSpreadsheetInfo(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(info.Sheets); expected != got {
+		t.Fatalf("expected %d sheets but got %d", expected, got)
+	}
+
+	var records []struct {
+		Name string
+		Age  string
+	}
+	if err = client.ReadSpreadsheet(ctx, &records, "test", "Users!A1:B"); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(records); expected != got {
+		t.Fatalf("expected %d records but got %d", expected, got)
+	}
+	if expected, got := "giwrgos", records[1].Name; expected != got {
+		t.Fatalf("expected name %q but got %q", expected, got)
+	}
+
+	updated, err := client.UpdateSpreadsheet(ctx, "test", sheets.ValueRange{
+		Range:  "'My Sheet'!B2",
+		Values: [][]interface{}{{"a", "b"}, {"c"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "'My Sheet'!B2:C3", updated.UpdatedRange; expected != got {
+		t.Fatalf("expected updated range %q but got %q", expected, got)
+	}
+	if expected, got := 3, updated.UpdatedCells; expected != got {
+		t.Fatalf("expected %d updated cells but got %d", expected, got)
+	}
+
+	expectedValues := [][]interface{}{nil, {nil, "a", "b"}, {nil, "c"}}
+	if got := srv.Values("test", "My Sheet"); !reflect.DeepEqual(expectedValues, got) {
+		t.Fatalf("expected values %v but got %v", expectedValues, got)
+	}
+
+	ranges, err := client.Range(ctx, "test", "Users!A2:B2", "'My Sheet'!A2:C2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRanges := []sheets.ValueRange{
+		{Range: "Users!A2:B2", MajorDimension: sheets.Rows, Values: [][]interface{}{{"giwrgos", "30"}}},
+		{Range: "'My Sheet'!A2:C2", MajorDimension: sheets.Rows, Values: [][]interface{}{{"", "a", "b"}}},
+	}
+	if !reflect.DeepEqual(expectedRanges, ranges) {
+		t.Fatalf("expected ranges %v but got %v", expectedRanges, ranges)
+	}
+
+	if _, err = client.ClearSpreadsheet(ctx, "test", "Users!A1:A"); err != nil {
+		t.Fatal(err)
+	}
+	expectedValues = [][]interface{}{{nil, "27"}, {nil, "30"}}
+	if got := srv.Values("test", "Users"); !reflect.DeepEqual(expectedValues, got) {
+		t.Fatalf("expected values %v but got %v", expectedValues, got)
+	}
+
+	_, err = client.GetSpreadsheetInfo(ctx, "missing")
+	if _, ok := sheets.IsStatusError(http.StatusNotFound, err); !ok {
+		t.Fatalf("expected not found error but got %v", err)
+	}
+}
+
+func TestServerAppend(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetValues("test", "Sheet1", [][]interface{}{{"a", "b"}})
+
+	var resp struct {
+		TableRange string                      `json:"tableRange"`
+		Updates    sheets.UpdateValuesResponse `json:"updates"`
+	}
+	err := srv.Client().ReadJSON(context.Background(), http.MethodPost, srv.URL+"/v4/spreadsheets/test/values/A1:B:append",
+		sheets.ValueRange{Values: [][]interface{}{{"c", "d"}}}, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Sheet1!A1:B1", resp.TableRange; expected != got {
+		t.Fatalf("expected table range %q but got %q", expected, got)
+	}
+	if expected, got := "Sheet1!A2:B2", resp.Updates.UpdatedRange; expected != got {
+		t.Fatalf("expected updated range %q but got %q", expected, got)
+	}
+
+	expectedValues := [][]interface{}{{"a", "b"}, {"c", "d"}}
+	if got := srv.Values("test", "Sheet1"); !reflect.DeepEqual(expectedValues, got) {
+		t.Fatalf("expected values %v but got %v", expectedValues, got)
+	}
+}
+
+func TestServerSheetIDIsNumber(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddSpreadsheet("test", "Users", "My Sheet")
+
+	resp, err := srv.Server.Client().Get(srv.URL + "/v4/spreadsheets/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Sheets []struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payload.Sheets) != 2 {
+		t.Fatalf("expected 2 sheets but got %d", len(payload.Sheets))
+	}
+
+	// The API sends the sheet ID as a number.
+	if sheetID, ok := payload.Sheets[1].Properties["sheetId"].(float64); !ok || sheetID != 1 {
+		t.Fatalf("expected a numeric sheet ID but got %#v", payload.Sheets[1].Properties["sheetId"])
+	}
+}
@@ -0,0 +1,230 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// snapshotFields are the spreadsheet fields captured by a snapshot:
+// the metadata, the sheet properties, charts and cell data (values, formulas, formats and notes) and the named ranges.
+const snapshotFields = "spreadsheetId,properties,namedRanges," +
+	"sheets(properties,charts,data(startRow,startColumn,rowData(values(userEnteredValue,userEnteredFormat,note))))"
+
+// SpreadsheetSnapshot is a serializable, full, copy of a spreadsheet:
+// its metadata, values (including formulas), formats, notes, named ranges and charts.
+// It can be saved, e.g. as JSON, and restored later with `Client.Restore`.
+//
+// See `Client.Snapshot` method.
+type SpreadsheetSnapshot struct {
+	// SpreadsheetID is the ID of the captured spreadsheet.
+	SpreadsheetID string `json:"spreadsheetId"`
+	// Time is the time the snapshot was captured.
+	Time time.Time `json:"time"`
+	// Data is the spreadsheet resource, as returned by the Sheets API.
+	Data json.RawMessage `json:"data"`
+}
+
+// Spreadsheet decodes and returns the metadata of the captured spreadsheet.
+func (s *SpreadsheetSnapshot) Spreadsheet() (*Spreadsheet, error) {
+	sd := new(Spreadsheet)
+	if err := json.Unmarshal(s.Data, sd); err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
+// Snapshot captures a full copy of the spreadsheet, e.g. for backup and disaster-recovery tooling.
+// See `SpreadsheetSnapshot` and `Client.Restore`.
+func (c *Client) Snapshot(ctx context.Context, spreadsheetID string) (*SpreadsheetSnapshot, error) {
+	url := c.apiURL(spreadsheetURL, spreadsheetID)
+
+	var data json.RawMessage
+	err := c.ReadJSON(ctx, http.MethodGet, url, nil, &data, Query{"fields": []string{snapshotFields}})
+	if err != nil {
+		return nil, c.opError(err, "spreadsheets.get", spreadsheetID, "", 0)
+	}
+
+	return &SpreadsheetSnapshot{SpreadsheetID: spreadsheetID, Time: time.Now(), Data: data}, nil
+}
+
+// snapshotSheet is a sheet of a snapshot, kept generic so that it's sent back as it was captured.
+type snapshotSheet struct {
+	Properties struct {
+		SheetID json.Number `json:"sheetId"`
+		Title   string      `json:"title"`
+		Grid    interface{} `json:"gridProperties"`
+	} `json:"properties"`
+	Charts []map[string]interface{} `json:"charts"`
+	Data   []struct {
+		StartRow    int64         `json:"startRow"`
+		StartColumn int64         `json:"startColumn"`
+		RowData     []interface{} `json:"rowData"`
+	} `json:"data"`
+}
+
+// Restore writes the "snapshot" to the spreadsheet of "targetID", which can be the captured one or another one,
+// e.g. a new spreadsheet. The sheets are matched by their titles, missing sheets are added
+// and the cells of existing ones are replaced by the captured ones. The named ranges and charts are added,
+// so restoring to a spreadsheet which already has named ranges with the same names fails.
+func (c *Client) Restore(ctx context.Context, snapshot *SpreadsheetSnapshot, targetID string) error {
+	var data struct {
+		NamedRanges []map[string]interface{} `json:"namedRanges"`
+		Sheets      []snapshotSheet          `json:"sheets"`
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(snapshot.Data))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return fmt.Errorf("sheets: restore: invalid snapshot: %w", err)
+	}
+
+	target, err := c.GetSpreadsheetInfo(ctx, targetID)
+	if err != nil {
+		return err
+	}
+
+	url := c.apiURL(spreadsheetBatchUpdateURL, targetID)
+	defer c.InvalidateSpreadsheetInfo(targetID) // sheets and named ranges are added.
+
+	// sheetIDs maps the captured sheet IDs to the target ones.
+	sheetIDs := make(map[string]json.Number, len(data.Sheets))
+	var addSheets []interface{}
+	for _, sheet := range data.Sheets {
+		if existing, ok := target.GetSheet(sheet.Properties.Title); ok {
+			sheetIDs[sheet.Properties.SheetID.String()] = json.Number(strconv.FormatInt(existing.Properties.ID, 10))
+			continue
+		}
+
+		addSheets = append(addSheets, map[string]interface{}{
+			"addSheet": map[string]interface{}{
+				"properties": map[string]interface{}{"title": sheet.Properties.Title},
+			},
+		})
+	}
+
+	if len(addSheets) > 0 {
+		var response struct {
+			Replies []struct {
+				AddSheet struct {
+					Properties struct {
+						SheetID json.Number `json:"sheetId"`
+						Title   string      `json:"title"`
+					} `json:"properties"`
+				} `json:"addSheet"`
+			} `json:"replies"`
+		}
+
+		err = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{"requests": addSheets}, &response)
+		if err != nil {
+			return c.opError(err, "spreadsheets.batchUpdate/addSheet", targetID, "", 0)
+		}
+
+		for _, reply := range response.Replies {
+			for _, sheet := range data.Sheets {
+				if sheet.Properties.Title == reply.AddSheet.Properties.Title {
+					sheetIDs[sheet.Properties.SheetID.String()] = reply.AddSheet.Properties.SheetID
+				}
+			}
+		}
+	}
+
+	var requests []interface{}
+	for _, sheet := range data.Sheets {
+		sheetID := sheetIDs[sheet.Properties.SheetID.String()]
+
+		if sheet.Properties.Grid != nil {
+			requests = append(requests, map[string]interface{}{
+				"updateSheetProperties": map[string]interface{}{
+					"properties": map[string]interface{}{"sheetId": sheetID, "gridProperties": sheet.Properties.Grid},
+					"fields":     "gridProperties",
+				},
+			})
+		}
+
+		for _, block := range sheet.Data {
+			requests = append(requests, map[string]interface{}{
+				"updateCells": map[string]interface{}{
+					"range":  map[string]interface{}{"sheetId": sheetID, "startRowIndex": block.StartRow, "startColumnIndex": block.StartColumn},
+					"rows":   remapSheetIDs(block.RowData, sheetIDs),
+					"fields": "userEnteredValue,userEnteredFormat,note",
+				},
+			})
+		}
+	}
+
+	for _, namedRange := range data.NamedRanges {
+		delete(namedRange, "namedRangeId")
+		requests = append(requests, map[string]interface{}{
+			"addNamedRange": map[string]interface{}{"namedRange": remapSheetIDs(namedRange, sheetIDs)},
+		})
+	}
+
+	for _, sheet := range data.Sheets {
+		for _, chart := range sheet.Charts {
+			delete(chart, "chartId")
+			requests = append(requests, map[string]interface{}{
+				"addChart": map[string]interface{}{"chart": remapSheetIDs(chart, sheetIDs)},
+			})
+		}
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	var response BatchUpdateResponse
+	err = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{"requests": requests}, &response)
+	return c.opError(err, "spreadsheets.batchUpdate/restore", targetID, "", 0)
+}
+
+// remapSheetIDs replaces the captured sheet IDs of "v" with the target ones.
+// The Sheets API omits a zero sheet ID, so the grid ranges and coordinates without one
+// refer to the captured sheet of ID 0.
+func remapSheetIDs(v interface{}, sheetIDs map[string]json.Number) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			switch key {
+			case "sheetId":
+				if id, ok := sheetIDs[fmt.Sprint(value)]; ok {
+					v[key] = id
+				}
+				continue
+			case "range", "anchorCell": // a grid range or a grid coordinate.
+				setDefaultSheetID(value)
+			case "sources": // the grid ranges of a chart's data source.
+				if list, ok := value.([]interface{}); ok {
+					for _, item := range list {
+						setDefaultSheetID(item)
+					}
+				}
+			}
+
+			v[key] = remapSheetIDs(value, sheetIDs)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = remapSheetIDs(value, sheetIDs)
+		}
+	}
+
+	return v
+}
+
+// setDefaultSheetID sets the omitted zero sheet ID of a grid range or coordinate explicitly, so that it's remapped too.
+func setDefaultSheetID(v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if _, ok = m["sheetId"]; !ok {
+		m["sheetId"] = json.Number("0")
+	}
+}
@@ -0,0 +1,110 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientSnapshotRestore(t *testing.T) {
+	const data = `{"spreadsheetId":"src","properties":{"title":"Report"},
+"namedRanges":[{"namedRangeId":"n1","name":"Totals","range":{"startRowIndex":1,"endRowIndex":3}}],
+"sheets":[
+ {"properties":{"sheetId":0,"title":"Sheet1","gridProperties":{"rowCount":100,"columnCount":5}},
+  "data":[{"rowData":[{"values":[{"userEnteredValue":{"stringValue":"Name"},"userEnteredFormat":{"textFormat":{"bold":true}}}]},{"values":[{"userEnteredValue":{"formulaValue":"=UPPER(\"x\")"}}]}]}],
+  "charts":[{"chartId":5,"spec":{"basicChart":{"domains":[{"domain":{"sourceRange":{"sources":[{"startRowIndex":0,"endRowIndex":3}]}}}]}},
+   "position":{"overlayPosition":{"anchorCell":{"sheetId":0,"rowIndex":1}}}}]},
+ {"properties":{"sheetId":42,"title":"Data","gridProperties":{"rowCount":10,"columnCount":2}}}
+]}`
+
+	var batchUpdates []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v4/spreadsheets/src":
+			if !strings.Contains(r.URL.Query().Get("fields"), "userEnteredFormat") {
+				t.Errorf("expected the formats to be requested")
+			}
+			w.Write([]byte(data))
+		case r.URL.Path == "/v4/spreadsheets/dst":
+			w.Write([]byte(`{"spreadsheetId":"dst","sheets":[{"properties":{"sheetId":7,"title":"Sheet1"}}]}`))
+		case r.URL.Path == "/v4/spreadsheets/dst:batchUpdate":
+			body, _ := io.ReadAll(r.Body)
+			batchUpdates = append(batchUpdates, string(body))
+			if strings.Contains(string(body), "addSheet") {
+				w.Write([]byte(`{"spreadsheetId":"dst","replies":[{"addSheet":{"properties":{"sheetId":9,"title":"Data"}}}]}`))
+				return
+			}
+			w.Write([]byte(`{"spreadsheetId":"dst"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	snapshot, err := c.Snapshot(ctx, "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The snapshot is serializable.
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot = new(SpreadsheetSnapshot)
+	if err = json.Unmarshal(b, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	sd, err := snapshot.Spreadsheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sd.Properties.Title != "Report" || len(sd.Sheets) != 2 || sd.NamedRanges[0].Name != "Totals" {
+		t.Fatalf("unexpected snapshot metadata: %#v", sd)
+	}
+
+	if err = c.Restore(ctx, snapshot, "dst"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batchUpdates) != 2 {
+		t.Fatalf("expected 2 batch updates but got %d", len(batchUpdates))
+	}
+	if expected := `{"requests":[{"addSheet":{"properties":{"title":"Data"}}}]}`; expected != batchUpdates[0] {
+		t.Fatalf("expected add sheet request %s but got %s", expected, batchUpdates[0])
+	}
+
+	var restore struct {
+		Requests []map[string]json.RawMessage `json:"requests"`
+	}
+	if err = json.Unmarshal([]byte(batchUpdates[1]), &restore); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, req := range restore.Requests {
+		for name, body := range req {
+			got = append(got, name+" "+string(body))
+		}
+	}
+
+	expected := []string{
+		`updateSheetProperties {"fields":"gridProperties","properties":{"gridProperties":{"columnCount":5,"rowCount":100},"sheetId":7}}`,
+		`updateCells {"fields":"userEnteredValue,userEnteredFormat,note","range":{"sheetId":7,"startColumnIndex":0,"startRowIndex":0},"rows":[{"values":[{"userEnteredFormat":{"textFormat":{"bold":true}},"userEnteredValue":{"stringValue":"Name"}}]},{"values":[{"userEnteredValue":{"formulaValue":"=UPPER(\"x\")"}}]}]}`,
+		`updateSheetProperties {"fields":"gridProperties","properties":{"gridProperties":{"columnCount":2,"rowCount":10},"sheetId":9}}`,
+		`addNamedRange {"namedRange":{"name":"Totals","range":{"endRowIndex":3,"sheetId":7,"startRowIndex":1}}}`,
+		`addChart {"chart":{"position":{"overlayPosition":{"anchorCell":{"rowIndex":1,"sheetId":7}}},"spec":{"basicChart":{"domains":[{"domain":{"sourceRange":{"sources":[{"endRowIndex":3,"sheetId":7,"startRowIndex":0}]}}}]}}}}`,
+	}
+
+	if strings.Join(expected, "\n") != strings.Join(got, "\n") {
+		t.Fatalf("expected requests:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
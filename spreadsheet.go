@@ -1,5 +1,10 @@
 package sheets
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // SheetType represents the type of a Sheet.
 type SheetType string
 
@@ -32,7 +37,7 @@ type (
 
 	// SheetProperties holds the properties of a sheet.
 	SheetProperties struct {
-		ID        string    `json:"sheetId"`
+		ID        int64     `json:"sheetId"`
 		Title     string    `json:"title"`
 		Index     int       `json:"index"`
 		SheetType SheetType `json:"sheetType"`
@@ -48,12 +53,15 @@ type (
 
 	// NamedRange represents the namedRange of a request.
 	NamedRange struct {
-		ID    string `json:"namedRangeId"`
-		Name  string `json:"name"`
-		Range Range  `json:"range"`
+		ID    string    `json:"namedRangeId"`
+		Name  string    `json:"name"`
+		Range GridRange `json:"range"`
 	}
 
 	// Range holds the range request and response values.
+	//
+	// Deprecated: Use `GridRange` instead, which holds the sheet ID as a number, as the API sends it.
+	// See `Range.ToGridRange` method and `FromGridRange` package-level function to convert between them.
 	Range struct {
 		SheetID          string `json:"sheetId"`
 		StartRowIndex    int    `json:"startRowIndex"`
@@ -72,19 +80,122 @@ type (
 	}
 )
 
+// ToGridRange returns the range as a `GridRange`. An empty or invalid sheet ID is the default sheet ID, zero.
+func (r Range) ToGridRange() GridRange {
+	sheetID, _ := strconv.ParseInt(r.SheetID, 10, 64)
+
+	return GridRange{
+		SheetID:          sheetID,
+		StartRowIndex:    int64(r.StartRowIndex),
+		EndRowIndex:      int64(r.EndRowIndex),
+		StartColumnIndex: int64(r.StartColumnIndex),
+		EndColumnIndex:   int64(r.EndColumnIndex),
+	}
+}
+
+// FromGridRange returns the "r" grid range as a (deprecated) `Range`.
+func FromGridRange(r GridRange) Range {
+	return Range{
+		SheetID:          strconv.FormatInt(r.SheetID, 10),
+		StartRowIndex:    int(r.StartRowIndex),
+		EndRowIndex:      int(r.EndRowIndex),
+		StartColumnIndex: int(r.StartColumnIndex),
+		EndColumnIndex:   int(r.EndColumnIndex),
+	}
+}
+
 // RangeAll returns a data range text which can be used to fetch all rows of a sheet.
 func (s *Sheet) RangeAll() string {
 	// To return all values we use the sheet's title as the range, so we return that one here.
-	return "'" + s.Properties.Title + "'"
+	return QuoteSheetTitle(s.Properties.Title)
 }
 
-// GetSheet finds and returns a sheet based on its "title" inside the "sd" Spreadsheet value.
+// DataRange returns a data range text which can be used to fetch the data rows of a sheet,
+// i.e. all rows except the frozen (header) ones, e.g. "Sheet1!A2:Z" for one frozen row and 26 columns.
+// Use it with `Client.ReadSpreadsheet` so the header row is not decoded into the first struct value.
+// The end row is unbounded so rows appended later are included too.
+// If the column count is unknown, the rows up to the row count are returned instead, e.g. "Sheet1!2:1000".
+func (s *Sheet) DataRange() string {
+	grid := s.Properties.Grid
+	r := GridRange{
+		StartRowIndex:  int64(grid.FrozenRowCount),
+		EndColumnIndex: int64(grid.ColumnCount),
+	}
+	if grid.ColumnCount == 0 {
+		r.EndRowIndex = int64(grid.RowCount)
+	}
+
+	return r.A1(s.Properties.Title)
+}
+
+// ValidateRange reports whether "r" is within the grid of the sheet, based on its row and column counts,
+// so out-of-bounds writes are caught locally instead of failing with an "exceeds grid limits" server error.
+// The sheet ID of "r" is not checked. It returns an `*InvalidRangeError` which matches the `ErrInvalidRange`.
+func (s *Sheet) ValidateRange(r GridRange) error {
+	grid := s.Properties.Grid
+	newErr := func(component string, index int64, reason string) error {
+		return &InvalidRangeError{
+			Range:     r.A1(s.Properties.Title),
+			Component: component,
+			Value:     strconv.FormatInt(index, 10),
+			Reason:    reason,
+		}
+	}
+
+	if grid.RowCount > 0 {
+		if r.StartRowIndex >= int64(grid.RowCount) {
+			return newErr("start row", r.StartRowIndex+1, fmt.Sprintf("exceeds the grid limits of %d rows", grid.RowCount))
+		}
+		if r.EndRowIndex > int64(grid.RowCount) {
+			return newErr("end row", r.EndRowIndex, fmt.Sprintf("exceeds the grid limits of %d rows", grid.RowCount))
+		}
+	}
+
+	if grid.ColumnCount > 0 {
+		if r.StartColumnIndex >= int64(grid.ColumnCount) {
+			return newErr("start column", r.StartColumnIndex+1, fmt.Sprintf("exceeds the grid limits of %d columns", grid.ColumnCount))
+		}
+		if r.EndColumnIndex > int64(grid.ColumnCount) {
+			return newErr("end column", r.EndColumnIndex, fmt.Sprintf("exceeds the grid limits of %d columns", grid.ColumnCount))
+		}
+	}
+
+	return nil
+}
+
+// GetSheet finds and returns a sheet based on its "title" (or its sheet ID as text) inside the "sd" Spreadsheet value.
 func (sd *Spreadsheet) GetSheet(title string) (Sheet, bool) {
 	for _, s := range sd.Sheets {
-		if s.Properties.Title == title || s.Properties.ID == title {
+		if s.Properties.Title == title || strconv.FormatInt(s.Properties.ID, 10) == title {
 			return s, true
 		}
 	}
 
 	return Sheet{}, false
 }
+
+// ResolveRange returns the A1 notation of "nameOrA1", which can be the name of a named range
+// of the spreadsheet or an A1 notation range, so that higher-level code can accept either form.
+// A named range is resolved through its sheet's title, e.g. "Sheet1!A1:B10".
+// The spreadsheet's sheets and named ranges should be fetched first, see `Client.GetSpreadsheetInfo`.
+func (sd *Spreadsheet) ResolveRange(nameOrA1 string) (string, error) {
+	for _, namedRange := range sd.NamedRanges {
+		if namedRange.Name != nameOrA1 {
+			continue
+		}
+
+		for _, sheet := range sd.Sheets {
+			if sheet.Properties.ID == namedRange.Range.SheetID {
+				return namedRange.Range.A1(sheet.Properties.Title), nil
+			}
+		}
+
+		return "", fmt.Errorf("sheets: named range %q: sheet %d not found", nameOrA1, namedRange.Range.SheetID)
+	}
+
+	if err := ValidateRange(nameOrA1); err != nil {
+		return "", err
+	}
+
+	return nameOrA1, nil
+}
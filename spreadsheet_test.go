@@ -0,0 +1,133 @@
+package sheets
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSpreadsheetResolveRange(t *testing.T) {
+	sd := &Spreadsheet{
+		Sheets: []Sheet{
+			{Properties: SheetProperties{ID: 0, Title: "Sheet1"}},
+			{Properties: SheetProperties{ID: 42, Title: "Bob's data"}},
+		},
+		NamedRanges: []NamedRange{
+			{Name: "Totals", Range: GridRange{StartRowIndex: 1, EndRowIndex: 10, EndColumnIndex: 2}},
+			{Name: "People", Range: GridRange{SheetID: 42, StartColumnIndex: 1, EndColumnIndex: 3}},
+			{Name: "Orphan", Range: GridRange{SheetID: 7}},
+		},
+	}
+
+	tests := []struct {
+		nameOrA1 string
+		expected string
+	}{
+		{"Totals", "Sheet1!A2:B10"},
+		{"People", "'Bob''s data'!B:C"},
+		{"Sheet1!A1:B2", "Sheet1!A1:B2"},
+	}
+
+	for _, tt := range tests {
+		got, err := sd.ResolveRange(tt.nameOrA1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tt.expected != got {
+			t.Fatalf("expected %q to be resolved to %q but got %q", tt.nameOrA1, tt.expected, got)
+		}
+	}
+
+	if _, err := sd.ResolveRange("Orphan"); err == nil {
+		t.Fatal("expected an error for a named range of a missing sheet")
+	}
+	if _, err := sd.ResolveRange("Bad Title!A1"); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected an invalid range error but got %v", err)
+	}
+}
+
+func TestNamedRangeDecode(t *testing.T) {
+	var namedRange NamedRange
+	err := json.Unmarshal([]byte(`{"namedRangeId":"x","name":"People","range":{"sheetId":42,"startRowIndex":1,"endColumnIndex":3}}`), &namedRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := (GridRange{SheetID: 42, StartRowIndex: 1, EndColumnIndex: 3}), namedRange.Range; expected != got {
+		t.Fatalf("expected range %#v but got %#v", expected, got)
+	}
+}
+
+func TestSheetValidateRange(t *testing.T) {
+	sheet := Sheet{Properties: SheetProperties{Title: "Sheet1", Grid: SheetGrid{RowCount: 1000, ColumnCount: 26}}}
+
+	for _, dataRange := range []string{"A1:Z1000", "A:Z", "B2:C", "1:1000"} {
+		if err := sheet.ValidateRange(mustParseA1(t, dataRange)); err != nil {
+			t.Fatalf("expected %q to be within the grid: %v", dataRange, err)
+		}
+	}
+
+	tests := []struct {
+		dataRange string
+		component string
+	}{
+		{"A1:AA1", "end column"},
+		{"A1:B1001", "end row"},
+		{"A1001:B", "start row"},
+		{"AA:AB", "start column"},
+	}
+
+	for _, tt := range tests {
+		err := sheet.ValidateRange(mustParseA1(t, tt.dataRange))
+
+		var rangeErr *InvalidRangeError
+		if !errors.As(err, &rangeErr) || rangeErr.Component != tt.component {
+			t.Fatalf("expected %q to exceed the grid at %s but got %v", tt.dataRange, tt.component, err)
+		}
+	}
+}
+
+func TestSheetDataRange(t *testing.T) {
+	tests := []struct {
+		grid     SheetGrid
+		expected string
+	}{
+		{SheetGrid{RowCount: 1000, ColumnCount: 26, FrozenRowCount: 1}, "'My Sheet'!A2:Z"},
+		{SheetGrid{RowCount: 1000, ColumnCount: 3, FrozenRowCount: 2}, "'My Sheet'!A3:C"},
+		{SheetGrid{RowCount: 1000, ColumnCount: 26}, "'My Sheet'!A:Z"},
+		{SheetGrid{RowCount: 1000, FrozenRowCount: 1}, "'My Sheet'!2:1000"},
+		{SheetGrid{}, "'My Sheet'"},
+	}
+
+	for _, tt := range tests {
+		sheet := Sheet{Properties: SheetProperties{Title: "My Sheet", Grid: tt.grid}}
+		if got := sheet.DataRange(); tt.expected != got {
+			t.Fatalf("expected %q but got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestSheetPropertiesDecode(t *testing.T) {
+	var props SheetProperties
+	if err := json.Unmarshal([]byte(`{"sheetId":42,"title":"Users"}`), &props); err != nil {
+		t.Fatal(err)
+	}
+
+	if props.ID != 42 || props.Title != "Users" {
+		t.Fatalf("unexpected properties: %#v", props)
+	}
+}
+
+func TestRangeGridRangeConversion(t *testing.T) {
+	r := Range{SheetID: "42", StartRowIndex: 1, EndRowIndex: 10, EndColumnIndex: 3}
+	grid := r.ToGridRange()
+
+	if expected := (GridRange{SheetID: 42, StartRowIndex: 1, EndRowIndex: 10, EndColumnIndex: 3}); expected != grid {
+		t.Fatalf("expected %#v but got %#v", expected, grid)
+	}
+
+	if got := FromGridRange(grid); r != got {
+		t.Fatalf("expected %#v but got %#v", r, got)
+	}
+}
@@ -0,0 +1,184 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// StreamRange reads the values of the "dataRange" of the spreadsheet and calls "fn" for each row
+// as it arrives, instead of decoding the whole response into memory first, so the memory stays flat
+// for responses of hundreds of megabytes. The "rowIndex" is the zero-based index of the row in the range.
+// The "row" slice is reused between calls, so "fn" should not retain it.
+//
+// If "fn" returns an error then the streaming stops and that error is returned.
+// The response is always decoded by the standard encoding/json package, the `Client.JSON` codec is not used.
+func (c *Client) StreamRange(ctx context.Context, spreadsheetID, dataRange string, fn func(rowIndex int, row []interface{}) error) error {
+	return c.streamRange(ctx, spreadsheetID, dataRange, nil, fn)
+}
+
+// StreamSpreadsheet reads the rows of the "dataRange" of the spreadsheet as they arrive, see `StreamRange`,
+// decodes each one of them to a value of the "elem" struct type (e.g. User{} or &User{}) and calls "fn" with a pointer to it.
+// A single struct value is reused, so "fn" should copy it to retain it. It uses the `Client.Decoder`, if any:
+// with `Decoder.CollectErrors` the rows that fail to be decoded are skipped and a `DecodeErrors` is returned at the end.
+func (c *Client) StreamSpreadsheet(ctx context.Context, elem interface{}, spreadsheetID, dataRange string, fn func(v interface{}) error) error {
+	typ := reflect.TypeOf(elem)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("not a struct")
+	}
+
+	d := c.Decoder
+	if d == nil {
+		d = new(Decoder)
+	}
+
+	var (
+		errs DecodeErrors
+		meta = d.metadata(typ)
+		v    = reflect.New(typ)
+		zero = reflect.Zero(typ)
+		// rangeValue locates the decode errors, its values are not used.
+		rangeValue = ValueRange{Range: dataRange}
+	)
+
+	onRange := func(responseRange string) {
+		rangeValue.Range = responseRange
+	}
+
+	err := c.streamRange(ctx, spreadsheetID, dataRange, onRange, func(i int, row []interface{}) error {
+		v.Elem().Set(zero)
+		if err := d.decodeValue(rangeValue, i, row, meta, v); err != nil {
+			if !d.CollectErrors {
+				return err
+			}
+
+			errs = append(errs, asDecodeError(rangeValue, i, err))
+			return nil
+		}
+
+		return fn(v.Interface())
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// streamRange fires a values.get request and streams its rows to "fn".
+// The "onRange", if not nil, is called with the range of the response when it arrives, usually before the rows.
+func (c *Client) streamRange(ctx context.Context, spreadsheetID, dataRange string, onRange func(string), fn func(int, []interface{}) error) error {
+	if err := ValidateRange(dataRange); err != nil {
+		return c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/get
+	url := c.apiURL(spreadsheetValuesURL, spreadsheetID, dataRange)
+
+	resp, err := c.Do(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if err = ctx.Err(); err == nil {
+			err = newResourceError(resp)
+		}
+
+		return c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+
+	var fnErr error
+	err = streamValues(resp.Body, onRange, func(i int, row []interface{}) error {
+		fnErr = fn(i, row)
+		return fnErr
+	})
+	if err != nil && err != fnErr {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		return c.opError(err, "values.get", spreadsheetID, dataRange, 0)
+	}
+
+	return err
+}
+
+// streamValues decodes a ValueRange JSON object from "r" token by token
+// and calls "fn" for each row of its values, the row slice is reused.
+func streamValues(r io.Reader, onRange func(string), fn func(int, []interface{}) error) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch key, _ := tok.(string); key {
+		case "values":
+			if err = expectDelim(dec, '['); err != nil {
+				return err
+			}
+
+			var row []interface{}
+			for i := 0; dec.More(); i++ {
+				if err = dec.Decode(&row); err != nil {
+					return err
+				}
+
+				if err = fn(i, row); err != nil {
+					return err
+				}
+			}
+
+			if err = expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "range":
+			var responseRange string
+			if err = dec.Decode(&responseRange); err != nil {
+				return err
+			}
+
+			if onRange != nil {
+				onRange(responseRange)
+			}
+		default:
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("sheets: stream: expected %q but got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientStreamSpreadsheet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected, got := "/v4/spreadsheets/id/values/Sheet1!A2:B", r.URL.Path; expected != got {
+			t.Errorf("expected path %q but got %q", expected, got)
+		}
+
+		w.Write([]byte(`{"range":"Sheet1!A2:B1000","majorDimension":"ROWS","values":[["makis","27"],["giwrgos","n/a"],["efi",30],[]]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	var rows []string
+	err := c.StreamRange(ctx, "id", "Sheet1!A2:B", func(i int, row []interface{}) error {
+		rows = append(rows, fmt.Sprint(i, row))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"0 [makis 27]", "1 [giwrgos n/a]", "2 [efi 30]", "3 []"}; !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("expected rows %q but got %q", expected, rows)
+	}
+
+	c.Decoder = &Decoder{CollectErrors: true}
+
+	var decoded []testRowFailingDecoder
+	err = c.StreamSpreadsheet(ctx, testRowFailingDecoder{}, "id", "Sheet1!A2:B", func(v interface{}) error {
+		decoded = append(decoded, *v.(*testRowFailingDecoder))
+		return nil
+	})
+
+	var decodeErrs DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs) != 1 || decodeErrs[0].Cell != "Sheet1!B3" {
+		t.Fatalf("expected a decode error of the cell Sheet1!B3 but got %v", err)
+	}
+
+	if expected := []testRowFailingDecoder{{"makis", 27}, {"efi", 30}, {}}; !reflect.DeepEqual(expected, decoded) {
+		t.Fatalf("expected decoded rows %v but got %v", expected, decoded)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = c.StreamRange(ctx, "id", "Sheet1!A2:B", func(int, []interface{}) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Fatalf("expected the callback error after one call but got %v after %d calls", err, calls)
+	}
+}
+
+func TestStreamValuesInvalid(t *testing.T) {
+	for _, body := range []string{`[]`, `{"values":{}}`, `{"values":[["a"]`} {
+		err := streamValues(strings.NewReader(body), nil, func(int, []interface{}) error { return nil })
+		if err == nil {
+			t.Fatalf("expected an error for %s", body)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SyncOptions holds the options of a `Client.Sync` call.
+type SyncOptions struct {
+	// Key is the header name of the column which identifies a row, e.g. "ID" or "Email".
+	// Defaults to the first header of the struct.
+	Key string
+	// DeleteOrphans, if true, deletes the sheet rows whose key is not found in the synced values,
+	// including rows with a duplicated key. By default they are kept and reported as `SyncResult.Orphans`.
+	DeleteOrphans bool
+}
+
+// SyncResult reports the changes a `Client.Sync` call applied to the sheet.
+type SyncResult struct {
+	// Added is the number of appended rows.
+	Added int
+	// Updated is the number of rows with at least one changed cell.
+	Updated int
+	// UpdatedCells is the number of changed cells.
+	UpdatedCells int
+	// Deleted is the number of deleted rows, see `SyncOptions.DeleteOrphans`.
+	Deleted int
+	// Orphans is the number of kept sheet rows whose key is not found in the synced values.
+	Orphans int
+}
+
+// Sync makes the sheet with "sheetTitle" match "rows", a slice of structs (or pointers to structs),
+// by applying only the differences, identified by the `SyncOptions.Key` column:
+// changed cells are updated in a single batch request, new rows are appended and,
+// optionally, orphaned rows are deleted. Unlike clearing and rewriting the whole sheet,
+// it keeps the formulas, comments and formatting of the unchanged cells and of the columns
+// which are not mapped to struct fields.
+//
+// The first row of the sheet is the header row, the struct fields are mapped to the columns by their header names,
+// so the columns can be in any order. An empty sheet gets the struct headers as its header row.
+// Cells are compared by their unformatted values, so a number formatted as currency
+// or a date shown in a locale's format is not rewritten on every call, see `cellEqual`.
+func (c *Client) Sync(ctx context.Context, spreadsheetID, sheetTitle string, rows interface{}, opts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+
+	v := reflect.Indirect(reflect.ValueOf(rows))
+	if v.Kind() != reflect.Slice {
+		return result, fmt.Errorf("sheets: sync: not a slice of structs")
+	}
+
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return result, fmt.Errorf("sheets: sync: not a slice of structs")
+	}
+
+	headers := getMetadata(typ).headers
+	if len(headers) == 0 {
+		return result, fmt.Errorf("sheets: sync: struct %s has no fields", typ)
+	}
+
+	key := 0
+	if opts.Key != "" {
+		key = -1
+		for i, h := range headers {
+			if h.Name == opts.Key {
+				key = i
+				break
+			}
+		}
+		if key < 0 {
+			return result, fmt.Errorf("sheets: sync: key %q is not a header of %s", opts.Key, typ)
+		}
+	}
+
+	sheetRange := QuoteSheetTitle(sheetTitle)
+	q := Query{
+		"valueRenderOption":    []string{"UNFORMATTED_VALUE"},
+		"dateTimeRenderOption": []string{"SERIAL_NUMBER"},
+	}
+	var valueRange ValueRange
+	err := c.ReadJSON(ctx, http.MethodGet, c.apiURL(spreadsheetValuesURL, spreadsheetID, sheetRange), nil, &valueRange, q)
+	if err != nil {
+		return result, c.opError(err, "values.get", spreadsheetID, sheetRange, 0)
+	}
+
+	values := valueRange.Values
+
+	// columns maps the struct headers to the (zero-based) columns of the sheet.
+	columns := make([]int, len(headers))
+	width := len(headers)
+	var newRows [][]interface{}
+
+	if len(values) == 0 {
+		header := make([]interface{}, len(headers))
+		for i, h := range headers {
+			columns[i] = i
+			header[i] = h.Name
+		}
+		newRows = append(newRows, header)
+	} else {
+		width = len(values[0])
+		for i, h := range headers {
+			columns[i] = -1
+			for j, cell := range values[0] {
+				if strings.TrimSpace(formatCell(cell)) == h.Name {
+					columns[i] = j
+					break
+				}
+			}
+
+			if columns[i] < 0 {
+				return result, fmt.Errorf("sheets: sync: header %q not found in sheet %q", h.Name, sheetTitle)
+			}
+		}
+	}
+
+	// existing maps the keys to the zero-based sheet rows, the first one wins.
+	existing := make(map[string]int, len(values))
+	var orphans []int
+	for i := 1; i < len(values); i++ {
+		k := formatCell(cellAt(values[i], columns[key]))
+		if _, ok := existing[k]; ok || k == "" {
+			if k != "" {
+				orphans = append(orphans, i)
+			}
+			continue
+		}
+		existing[k] = i
+	}
+
+	var (
+		updates []ValueRange
+		added   int
+	)
+	seen := make(map[string]struct{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		row, err := encodeRow(v.Index(i).Interface())
+		if err != nil {
+			return result, fmt.Errorf("sheets: sync: row %d: %w", i, err)
+		}
+
+		k := formatCell(row[key])
+		seen[k] = struct{}{}
+
+		rowIndex, ok := existing[k]
+		if !ok {
+			newRow := make([]interface{}, width)
+			for j, value := range row {
+				newRow[columns[j]] = value
+			}
+			newRows = append(newRows, newRow)
+			added++
+			continue
+		}
+
+		changed := 0
+		for j, value := range row {
+			if cellEqual(cellAt(values[rowIndex], columns[j]), value) {
+				continue
+			}
+
+			cell := Cell{Row: rowIndex, Col: columns[j]}
+			updates = append(updates, ValueRange{
+				Range:  QuoteSheetTitle(sheetTitle) + "!" + cell.A1(),
+				Values: [][]interface{}{{value}},
+			})
+			changed++
+		}
+
+		if changed > 0 {
+			result.Updated++
+			result.UpdatedCells += changed
+		}
+	}
+
+	for k, rowIndex := range existing {
+		if _, ok := seen[k]; !ok {
+			orphans = append(orphans, rowIndex)
+		}
+	}
+
+	if len(updates) > 0 {
+		if _, err = c.BatchUpdateValues(ctx, spreadsheetID, updates...); err != nil {
+			return result, err
+		}
+	}
+
+	if len(orphans) > 0 {
+		if !opts.DeleteOrphans {
+			result.Orphans = len(orphans)
+		} else {
+			if err = c.deleteOrphans(ctx, spreadsheetID, sheetTitle, orphans); err != nil {
+				return result, err
+			}
+			result.Deleted = len(orphans)
+		}
+	}
+
+	if len(newRows) > 0 {
+		_, err = c.AppendSpreadsheet(ctx, spreadsheetID, ValueRange{
+			Range:          QuoteSheetTitle(sheetTitle) + "!A1",
+			MajorDimension: Rows,
+			Values:         newRows,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.Added = added
+	}
+
+	return result, nil
+}
+
+// deleteOrphans deletes the zero-based "rows" of the sheet with "sheetTitle",
+// contiguous rows are deleted together, from the bottom to the top so the indexes stay valid.
+func (c *Client) deleteOrphans(ctx context.Context, spreadsheetID, sheetTitle string, rows []int) error {
+	sd, err := c.GetSpreadsheetInfo(ctx, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	sheet, ok := sd.GetSheet(sheetTitle)
+	if !ok {
+		return fmt.Errorf("sheets: sync: sheet %q not found in spreadsheet %q", sheetTitle, spreadsheetID)
+	}
+
+	sheetID := sheet.Properties.ID
+
+	sort.Sort(sort.Reverse(sort.IntSlice(rows)))
+
+	var ranges [][2]int64
+	for _, row := range rows {
+		if n := len(ranges); n > 0 && ranges[n-1][0] == int64(row)+1 {
+			ranges[n-1][0]--
+			continue
+		}
+
+		ranges = append(ranges, [2]int64{int64(row), int64(row) + 1})
+	}
+
+	_, err = c.deleteRows(ctx, spreadsheetID, sheetID, ranges)
+	return err
+}
+
+func cellAt(row []interface{}, col int) interface{} {
+	if col < len(row) {
+		return row[col]
+	}
+
+	return nil
+}
+
+// cellEqual reports whether the unformatted "cell" read from the sheet holds the encoded "value".
+// A number cell is compared by its value to a number and by its time to a date text,
+// dates are read as serial numbers. Any other cell, e.g. a number stored as text,
+// is compared by its text. Booleans are shown as "TRUE" and "FALSE" by the sheet.
+func cellEqual(cell, value interface{}) bool {
+	if n, ok := cell.(float64); ok {
+		switch v := reflect.ValueOf(value); v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return n == float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return n == float64(v.Uint())
+		case reflect.Float32: // written with the float32 precision.
+			return float32(n) == float32(v.Float())
+		case reflect.Float64:
+			return n == v.Float()
+		case reflect.String:
+			if t, ok := parseTime(v.String()); ok {
+				// Serial numbers have no time zone, compare the wall clocks.
+				cellTime, _ := parseTime(n)
+				y, m, d := t.Date()
+				wall := time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+				return cellTime.Equal(wall.Round(time.Millisecond))
+			}
+		}
+	}
+
+	if _, ok := value.(bool); ok {
+		return strings.EqualFold(formatCell(cell), formatCell(value))
+	}
+
+	return formatCell(cell) == formatCell(value)
+}
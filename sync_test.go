@@ -0,0 +1,74 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientSyncUnformatted(t *testing.T) {
+	type order struct {
+		ID     string
+		Amount float64
+		Qty    int
+		Date   time.Time
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v4/spreadsheets/id/values/Orders" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+
+		// The formatted values would never match the encoded ones.
+		if q := r.URL.Query(); q.Get("valueRenderOption") != "UNFORMATTED_VALUE" || q.Get("dateTimeRenderOption") != "SERIAL_NUMBER" {
+			w.Write([]byte(`{"range":"Orders","values":[["ID","Amount","Qty","Date"],["a","$1,234.50","1,000","1/2/2024"]]}`))
+			return
+		}
+
+		w.Write([]byte(`{"range":"Orders","values":[["ID","Amount","Qty","Date"],["a",1234.5,1000,45293.5]]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+
+	rows := []order{{ID: "a", Amount: 1234.5, Qty: 1000, Date: time.Date(2024, 1, 2, 12, 0, 0, 0, time.FixedZone("", 2*60*60))}}
+	result, err := c.Sync(context.Background(), "id", "Orders", rows, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := (SyncResult{}), result; expected != got {
+		t.Fatalf("expected result %+v but got %+v", expected, got)
+	}
+}
+
+func TestCellEqual(t *testing.T) {
+	tests := []struct {
+		cell, value interface{}
+		expected    bool
+	}{
+		{1234.5, 1234.5, true},
+		{1000.0, 1000, true},
+		{1000.0, uint8(200), false},
+		{1.1, float32(1.1), true},
+		{"27", 27, true},
+		{"27", 28, false},
+		{45293.0, "2024-01-02", true},
+		{45293.0, "2024-01-03", false},
+		{45293.0, "text", false},
+		{true, true, true},
+		{"TRUE", true, true},
+		{"a", "a", true},
+		{nil, "", true},
+		{nil, "a", false},
+	}
+
+	for i, tt := range tests {
+		if got := cellEqual(tt.cell, tt.value); got != tt.expected {
+			t.Errorf("[%d] expected cellEqual(%v, %v) to be %v", i, tt.cell, tt.value, tt.expected)
+		}
+	}
+}
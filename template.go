@@ -0,0 +1,55 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"sort"
+)
+
+// InstantiateTemplate copies the template spreadsheet of "templateSpreadsheetID" to a new one with "title"
+// and replaces the "{{key}}" placeholders of all of its sheets with the text of the "data" values,
+// e.g. a "{{customer}}" cell text (or part of it) is replaced by the value of data["customer"].
+// Placeholders without a "data" key are left as they are.
+//
+// It returns the new spreadsheet's Drive metadata, its ID is the new spreadsheet ID.
+// If the placeholders cannot be replaced then the new spreadsheet is returned with the error,
+// so the caller can retry or trash it. See `CopySpreadsheet` and `Trash` methods too.
+func (c *Client) InstantiateTemplate(ctx context.Context, templateSpreadsheetID string, data map[string]interface{}, title string) (*DriveFile, error) {
+	file, err := c.CopySpreadsheet(ctx, templateSpreadsheetID, title, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return file, nil
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/request#findreplacerequest
+	requests := make([]interface{}, len(keys))
+	for i, key := range keys {
+		requests[i] = map[string]interface{}{
+			"findReplace": map[string]interface{}{
+				"find":        "{{" + key + "}}",
+				"replacement": formatCell(data[key]),
+				"allSheets":   true,
+				"matchCase":   true,
+			},
+		}
+	}
+
+	url := c.apiURL(spreadsheetBatchUpdateURL, file.ID)
+
+	var response BatchUpdateResponse
+	err = c.ReadJSON(ctx, http.MethodPost, url, map[string]interface{}{"requests": requests}, &response)
+	if err != nil {
+		return file, c.opError(err, "spreadsheets.batchUpdate/findReplace", file.ID, "", 0)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,51 @@
+package sheets
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientInstantiateTemplate(t *testing.T) {
+	var batchUpdate string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files/template/copy":
+			body, _ := io.ReadAll(r.Body)
+			if expected, got := `{"name":"Invoice 42"}`, strings.TrimSpace(string(body)); expected != got {
+				t.Errorf("expected copy body %s but got %s", expected, got)
+			}
+			w.Write([]byte(`{"id":"new","name":"Invoice 42"}`))
+		case "/v4/spreadsheets/new:batchUpdate":
+			body, _ := io.ReadAll(r.Body)
+			batchUpdate = strings.TrimSpace(string(body))
+			w.Write([]byte(`{"spreadsheetId":"new"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	file, err := c.InstantiateTemplate(context.Background(), "template", map[string]interface{}{
+		"total":    99.5,
+		"customer": "kataras",
+	}, "Invoice 42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "new", file.ID; expected != got {
+		t.Fatalf("expected new spreadsheet ID %q but got %q", expected, got)
+	}
+
+	expected := `{"requests":[` +
+		`{"findReplace":{"allSheets":true,"find":"{{customer}}","matchCase":true,"replacement":"kataras"}},` +
+		`{"findReplace":{"allSheets":true,"find":"{{total}}","matchCase":true,"replacement":"99.5"}}]}`
+	if expected != batchUpdate {
+		t.Fatalf("expected batch update:\n%s\nbut got:\n%s", expected, batchUpdate)
+	}
+}
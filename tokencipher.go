@@ -0,0 +1,178 @@
+package sheets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenCipher encrypts and decrypts the stored oauth2 tokens.
+// Implement it to use a key management service (KMS).
+//
+// See `NewAESGCMCipher`, `NewPassphraseCipher` and `EncryptedFileTokenStore`.
+type TokenCipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher returns a new AES-GCM `TokenCipher`.
+// The "key" should be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewAESGCMCipher(key []byte) (TokenCipher, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesGCMCipher{aead: aead}, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Encrypt implements the `TokenCipher` interface.
+// The random nonce is prepended to the result.
+func (c *aesGCMCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return sealAESGCM(c.aead, nil, plaintext)
+}
+
+// Decrypt implements the `TokenCipher` interface.
+func (c *aesGCMCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return openAESGCM(c.aead, ciphertext)
+}
+
+func sealAESGCM(aead cipher.AEAD, prefix, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(prefix, nonce...)
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+func openAESGCM(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	n := aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	return aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+}
+
+const (
+	passphraseSaltSize   = 16
+	passphraseIterations = 210000
+)
+
+type passphraseCipher struct {
+	passphrase string
+}
+
+// NewPassphraseCipher returns a new AES-256-GCM `TokenCipher` which derives its key
+// from the "passphrase" (PBKDF2 with HMAC-SHA256) and a random salt on each encryption.
+func NewPassphraseCipher(passphrase string) TokenCipher {
+	return &passphraseCipher{passphrase: passphrase}
+}
+
+// Encrypt implements the `TokenCipher` interface.
+// The random salt and nonce are prepended to the result.
+func (c *passphraseCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := c.newAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return sealAESGCM(aead, salt, plaintext)
+}
+
+// Decrypt implements the `TokenCipher` interface.
+func (c *passphraseCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < passphraseSaltSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	salt := ciphertext[:passphraseSaltSize]
+	aead, err := c.newAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAESGCM(aead, ciphertext[passphraseSaltSize:])
+}
+
+// newAEAD returns the AES-256-GCM cipher of the key derived from the passphrase and the "salt".
+func (c *passphraseCipher) newAEAD(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, c.passphrase, salt, passphraseIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAESGCM(key)
+}
+
+// EncryptedFileTokenStore is a `TokenStore` which keeps the token
+// encrypted on a local file, so refresh tokens are never stored as plaintext.
+type EncryptedFileTokenStore struct {
+	Path   string
+	Cipher TokenCipher
+}
+
+var _ TokenStore = (*EncryptedFileTokenStore)(nil)
+
+// Get implements the `TokenStore` interface, it reads and decrypts the token from the file.
+func (s *EncryptedFileTokenStore) Get(ctx context.Context) (*oauth2.Token, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	plaintext, err := s.Cipher.Decrypt(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt oauth token: %w", err)
+	}
+
+	tok := &oauth2.Token{}
+	err = json.Unmarshal(plaintext, tok)
+	return tok, err
+}
+
+// Put implements the `TokenStore` interface, it encrypts and writes the token to the file.
+func (s *EncryptedFileTokenStore) Put(ctx context.Context, token *oauth2.Token) error {
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := s.Cipher.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt oauth token: %w", err)
+	}
+
+	return os.WriteFile(s.Path, ciphertext, 0600)
+}
@@ -0,0 +1,121 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// TokenInfo holds information about the active access token of a Client.
+// See `Client.TokenInfo` method.
+type TokenInfo struct {
+	// Expiry is the expiration time of the access token.
+	Expiry time.Time
+	// Scopes are the granted scopes.
+	Scopes []string
+	// Email is the email of the authenticated identity, if available.
+	Email string
+	// HasRefreshToken reports whether the token can be refreshed without user interaction.
+	HasRefreshToken bool
+}
+
+// ExpiresIn returns the remaining lifetime of the access token.
+func (t *TokenInfo) ExpiresIn() time.Duration {
+	if t.Expiry.IsZero() {
+		return 0
+	}
+
+	return time.Until(t.Expiry)
+}
+
+// HasScope reports whether the "scope" is granted, directly or through a broader scope
+// (e.g. `ScopeReadOnly` is granted by `ScopeReadWrite`).
+func (t *TokenInfo) HasScope(scope string) bool {
+	scope, err := normalizeScope(scope)
+	if err != nil {
+		return false
+	}
+
+	set := make(map[string]struct{}, len(t.Scopes))
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+		set[s] = struct{}{}
+	}
+
+	return isImpliedScope(scope, set)
+}
+
+// CanWrite reports whether the granted scopes include write access to spreadsheets.
+func (t *TokenInfo) CanWrite() bool {
+	return t.HasScope(ScopeReadWrite)
+}
+
+// TokenInfo returns information about the active access token of the Client,
+// so operators can alert before it expires and verify the granted scopes.
+// The Client should be created with one of the package-level authentication functions.
+func (c *Client) TokenInfo(ctx context.Context) (*TokenInfo, error) {
+	source, ok := c.authTransport().(interface{ Token() (*oauth2.Token, error) })
+	if !ok {
+		return nil, fmt.Errorf("token info: the client's authentication does not expose its token")
+	}
+
+	tok, err := source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{
+		Expiry:          tok.Expiry,
+		HasRefreshToken: tok.RefreshToken != "",
+	}
+
+	// https://developers.google.com/identity/sign-in/web/backend-auth#calling-the-tokeninfo-endpoint
+	// The token is sent as a form body, instead of a query parameter, so it's never part
+	// of the request URL which is printed by the transport errors.
+	form := url.Values{"access_token": {tok.AccessToken}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenInfoURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.baseHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("token info", resp)
+	}
+
+	var payload struct {
+		Scope     string `json:"scope"`
+		ExpiresIn string `json:"expires_in"`
+		Email     string `json:"email"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("token info: %w", err)
+	}
+
+	info.Scopes = strings.Fields(payload.Scope)
+	info.Email = payload.Email
+	if info.Expiry.IsZero() {
+		if seconds, err := strconv.Atoi(payload.ExpiresIn); err == nil {
+			info.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,70 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestClientTokenInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.RawQuery != "" {
+			t.Errorf("expected a POST request without query but got %s %s", r.Method, r.URL)
+		}
+		if expected, got := "access", r.PostFormValue("access_token"); expected != got {
+			t.Errorf("expected access token %q but got %q", expected, got)
+		}
+
+		w.Write([]byte(`{"scope":"` + ScopeReadWrite + ` ` + ScopeDriveFile + `","expires_in":"3599","email":"sa@test"}`))
+	}))
+	defer srv.Close()
+
+	defer func(original string) { tokenInfoURL = original }(tokenInfoURL)
+	tokenInfoURL = srv.URL
+
+	expiry := time.Now().Add(time.Hour)
+	c := NewClient(FromTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access", Expiry: expiry})))
+
+	info, err := c.TokenInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.Expiry.Equal(expiry) {
+		t.Fatalf("expected expiry %s but got %s", expiry, info.Expiry)
+	}
+	if !info.CanWrite() || !info.HasScope(ScopeReadOnly) || !info.HasScope("drive.file") || info.HasScope(ScopeDrive) {
+		t.Fatalf("unexpected scopes: %v", info.Scopes)
+	}
+	if expected, got := "sa@test", info.Email; expected != got {
+		t.Fatalf("expected email %q but got %q", expected, got)
+	}
+
+	if _, err = NewClient(nil).TokenInfo(context.Background()); err == nil {
+		t.Fatal("expected an error for a client without authentication")
+	}
+}
+
+func TestClientTokenInfoErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	defer func(original string) { tokenInfoURL = original }(tokenInfoURL)
+	tokenInfoURL = srv.URL
+
+	c := NewClient(FromTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret-access-token"})))
+
+	_, err := c.TokenInfo(context.Background())
+	if err == nil {
+		t.Fatal("expected a transport error")
+	}
+
+	if strings.Contains(err.Error(), "secret-access-token") {
+		t.Fatalf("expected the access token to be hidden from the error but got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrTokenNotFound is returned from a `TokenStore` when there is no stored token.
+var ErrTokenNotFound = fmt.Errorf("token not found")
+
+// ErrTokenRevoked is returned from the interactive authentication (see `TokenWithStore`)
+// when the stored refresh token was revoked or expired and re-consent is not enabled,
+// see `WithReconsent`. Callers can check it with `errors.Is`, delete the stored token and
+// authenticate again.
+var ErrTokenRevoked = fmt.Errorf("token revoked")
+
+type reconsentKey struct{}
+
+// WithReconsent returns a new context which enables the re-consent of the interactive authentication,
+// it should be passed on the `Token`, `TokenE` or `TokenWithStore` package-level functions.
+// When the refresh token was revoked, the browser is opened on the consent page again,
+// instead of returning `ErrTokenRevoked`. It should be used only by interactive (command-line) applications.
+//
+// Usage:
+//
+//	ctx = sheets.WithReconsent(ctx)
+//	client := sheets.NewClient(sheets.Token(ctx, "credentials.json", "token.json"))
+func WithReconsent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reconsentKey{}, true)
+}
+
+func reconsentEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(reconsentKey{}).(bool)
+	return enabled
+}
+
+// TokenStore persists the oauth2 token of the interactive (user consent) authentication flow.
+// Web applications can implement it to keep their users tokens in their database.
+//
+// See `FileTokenStore`, `MemoryTokenStore` and the `TokenWithStore` package-level function.
+type TokenStore interface {
+	// Get returns the stored token or `ErrTokenNotFound`.
+	Get(ctx context.Context) (*oauth2.Token, error)
+	// Put stores the token, it is called after the token was retrieved or refreshed.
+	Put(ctx context.Context, token *oauth2.Token) error
+}
+
+// FileTokenStore is a `TokenStore` which keeps the token as JSON on a local file.
+type FileTokenStore string
+
+var _ TokenStore = FileTokenStore("")
+
+// Get implements the `TokenStore` interface, it reads the token from the file.
+func (path FileTokenStore) Get(ctx context.Context) (*oauth2.Token, error) {
+	f, err := os.Open(string(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	tok := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(tok)
+	return tok, err
+}
+
+// Put implements the `TokenStore` interface, it writes the token to the file.
+func (path FileTokenStore) Put(ctx context.Context, token *oauth2.Token) error {
+	f, err := os.OpenFile(string(path), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to cache oauth token: %w", err)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(token)
+}
+
+// MemoryTokenStore is a `TokenStore` which keeps the token in memory.
+// Its zero value is ready to use.
+type MemoryTokenStore struct {
+	mu    sync.RWMutex
+	token *oauth2.Token
+}
+
+var _ TokenStore = (*MemoryTokenStore)(nil)
+
+// Get implements the `TokenStore` interface.
+func (s *MemoryTokenStore) Get(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.token == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	tok := *s.token
+	return &tok, nil
+}
+
+// Put implements the `TokenStore` interface.
+func (s *MemoryTokenStore) Put(ctx context.Context, token *oauth2.Token) error {
+	tok := *token
+
+	s.mu.Lock()
+	s.token = &tok
+	s.mu.Unlock()
+	return nil
+}
+
+// userTokenSource is the oauth2 token source of the interactive flow.
+// Each call refreshes the token and puts it to the store.
+// If the refresh token was revoked, the authorization flow runs again
+// when "reconsent" is true, otherwise `ErrTokenRevoked` is returned.
+type userTokenSource struct {
+	config    *oauth2.Config
+	store     TokenStore
+	reconsent bool
+
+	mu           sync.Mutex
+	ctx          context.Context
+	refreshToken string
+}
+
+func (s *userTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	ctx, refreshToken := s.ctx, s.refreshToken
+	s.mu.Unlock()
+
+	tok, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if !errors.As(err, &retrieveErr) || retrieveErr.ErrorCode != "invalid_grant" {
+			return nil, err
+		}
+
+		if !s.reconsent {
+			return nil, fmt.Errorf("%w: %w", ErrTokenRevoked, err)
+		}
+
+		if tok, err = getTokenFromWeb(ctx, s.config); err != nil {
+			return nil, err
+		}
+	}
+
+	if tok.RefreshToken != "" {
+		s.mu.Lock()
+		s.refreshToken = tok.RefreshToken
+		s.mu.Unlock()
+	}
+
+	if err = s.store.Put(ctx, tok); err != nil {
+		return nil, err
+	}
+
+	return tok, nil
+}
@@ -0,0 +1,159 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestTokenStores(t *testing.T) {
+	ctx := context.Background()
+	stores := []TokenStore{
+		FileTokenStore(filepath.Join(t.TempDir(), "token.json")),
+		new(MemoryTokenStore),
+	}
+
+	for i, store := range stores {
+		if _, err := store.Get(ctx); err != ErrTokenNotFound {
+			t.Fatalf("[%d] expected ErrTokenNotFound but got %v", i, err)
+		}
+
+		expected := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour).Round(time.Second)}
+		if err := store.Put(ctx, expected); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := store.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.AccessToken != expected.AccessToken || got.RefreshToken != expected.RefreshToken || !got.Expiry.Equal(expected.Expiry) {
+			t.Fatalf("[%d] expected token %#+v but got %#+v", i, expected, got)
+		}
+	}
+}
+
+type errTokenStore struct{ err error }
+
+func (s errTokenStore) Get(context.Context) (*oauth2.Token, error) { return nil, s.err }
+func (s errTokenStore) Put(context.Context, *oauth2.Token) error   { return nil }
+
+func TestGetTransportStoreError(t *testing.T) {
+	storeErr := errors.New("permission denied")
+	_, err := getTransport(context.Background(), errTokenStore{storeErr}, &oauth2.Config{ClientID: "client"})
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("expected the store error but got %v", err)
+	}
+}
+
+func TestUserTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if expected, got := "refresh", r.Form.Get("refresh_token"); expected != got {
+			t.Errorf("expected refresh token %q but got %q", expected, got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"refreshed","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	store := new(MemoryTokenStore)
+	ts := &userTokenSource{
+		ctx:          context.Background(),
+		config:       &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: srv.URL}},
+		store:        store,
+		refreshToken: "refresh",
+	}
+
+	if _, err := ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := store.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "refreshed", tok.AccessToken; expected != got {
+		t.Fatalf("expected stored token %q but got %q", expected, got)
+	}
+	if expected, got := "refresh", tok.RefreshToken; expected != got {
+		t.Fatalf("expected the refresh token %q to be kept but got %q", expected, got)
+	}
+}
+
+func TestUserTokenSourceRevoked(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+	}))
+	defer srv.Close()
+
+	ts := &userTokenSource{
+		ctx:          context.Background(),
+		config:       &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: srv.URL}},
+		store:        new(MemoryTokenStore),
+		refreshToken: "revoked",
+	}
+
+	if _, err := ts.Token(); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("expected ErrTokenRevoked but got %v", err)
+	}
+
+	if !reconsentEnabled(WithReconsent(context.Background())) || reconsentEnabled(context.Background()) {
+		t.Fatal("expected the re-consent to be enabled only through WithReconsent")
+	}
+}
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	aesCipher, err := NewAESGCMCipher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i, c := range []TokenCipher{aesCipher, NewPassphraseCipher("passphrase")} {
+		path := filepath.Join(t.TempDir(), "token.enc")
+		store := &EncryptedFileTokenStore{Path: path, Cipher: c}
+
+		if err := store.Put(ctx, &oauth2.Token{AccessToken: "access", RefreshToken: "refresh-secret"}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), "refresh-secret") {
+			t.Fatalf("[%d] expected the token file to be encrypted", i)
+		}
+
+		tok, err := store.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := "refresh-secret", tok.RefreshToken; expected != got {
+			t.Fatalf("[%d] expected refresh token %q but got %q", i, expected, got)
+		}
+	}
+
+	wrong := &EncryptedFileTokenStore{Path: filepath.Join(t.TempDir(), "token.enc"), Cipher: NewPassphraseCipher("one")}
+	if err := wrong.Put(ctx, &oauth2.Token{AccessToken: "access"}); err != nil {
+		t.Fatal(err)
+	}
+	wrong.Cipher = NewPassphraseCipher("two")
+	if _, err := wrong.Get(ctx); err == nil {
+		t.Fatal("expected an error when decrypting with a wrong passphrase")
+	}
+}
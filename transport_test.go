@@ -0,0 +1,70 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport, err := NewTransport(TransportOptions{Proxy: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://sheets.googleapis.com/v4/spreadsheets/test", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "http://proxy.internal:3128", proxyURL.String(); expected != got {
+		t.Fatalf("expected proxy %q but got %q", expected, got)
+	}
+
+	if _, err = NewTransport(TransportOptions{Proxy: "://invalid"}); err == nil {
+		t.Fatal("expected an error for an invalid proxy URL")
+	}
+}
+
+func TestClientSetTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tokeninfo" {
+			w.Write([]byte(`{"scope":"` + ScopeReadOnly + `","expires_in":"3599"}`))
+			return
+		}
+
+		if expected, got := "Bearer access", r.Header.Get("Authorization"); expected != got {
+			t.Errorf("expected authorization %q but got %q", expected, got)
+		}
+		w.Write([]byte(`{"spreadsheetId":"id"}`))
+	}))
+	defer srv.Close()
+
+	defer func(original string) { tokenInfoURL = original }(tokenInfoURL)
+	tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+	var hosts []string
+	base := rewriteTransport(srv.URL)
+	c := NewClient(FromTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})))
+	c.SetTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		hosts = append(hosts, r.URL.Host)
+		return base.RoundTrip(r)
+	}))
+
+	if _, err := c.GetSpreadsheetInfo(context.Background(), "id"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.TokenInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"sheets.googleapis.com", "oauth2.googleapis.com"}; !reflect.DeepEqual(expected, hosts) {
+		t.Fatalf("expected the requests to be sent through the base transport: %q but got %q", expected, hosts)
+	}
+}
@@ -1,9 +1,16 @@
 package sheets
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unique"
 )
 
 const structTag = "sheets"
@@ -53,6 +60,31 @@ type (
 		// This is only included if the request's includeValuesInResponse field was true.
 		UpdatedData []ValueRange `json:"updatedData"`
 	}
+
+	// AppendValuesResponse is the response when appending values to a spreadsheet.
+	AppendValuesResponse struct {
+		// The spreadsheet the updates were applied to.
+		SpreadsheetID string `json:"spreadsheetId"`
+		// The range (in A1 notation) of the table that values are being appended to (before the values were appended).
+		// Empty if no table was found.
+		TableRange string `json:"tableRange"`
+		// Information about the updates that were applied.
+		Updates UpdateValuesResponse `json:"updates"`
+	}
+
+	// BatchUpdateValuesResponse is the response when updating multiple ranges of values in a spreadsheet.
+	BatchUpdateValuesResponse struct {
+		// The spreadsheet the updates were applied to.
+		SpreadsheetID string `json:"spreadsheetId"`
+		// The total number of rows where at least one cell in the row was updated.
+		TotalUpdatedRows int `json:"totalUpdatedRows"`
+		// The total number of columns where at least one cell in the column was updated.
+		TotalUpdatedColumns int `json:"totalUpdatedColumns"`
+		// The total number of cells updated.
+		TotalUpdatedCells int `json:"totalUpdatedCells"`
+		// One response per updated range, in the same order as the ranges were requested.
+		Responses []UpdateValuesResponse `json:"responses"`
+	}
 )
 
 // Header is the row's header of a struct field.
@@ -71,14 +103,61 @@ var (
 	ErrOK = fmt.Errorf("ok")
 )
 
-var (
-	cache   = make(map[reflect.Type]*metadata)
-	cacheMu sync.RWMutex
-)
+// DecodeError is returned from `DecodeValueRange` when a cell value cannot be decoded.
+// It reports the range, row, column and header of the value so that it can be found in the sheet.
+type DecodeError struct {
+	// Range is the range of the ValueRange the value belongs to.
+	Range string
+	// Row and Column are the zero-based indexes of the value in the ValueRange.Values.
+	Row, Column int
+	// Header is the header name of the column.
+	Header string
+	// Cell is the A1 notation of the cell, e.g. "Sheet1!C3482",
+	// empty if it cannot be computed from the Range.
+	Cell string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements a Go error and returns a human-readable error text.
+func (e *DecodeError) Error() string {
+	location := fmt.Sprintf("row %d, column %d (%s)", e.Row, e.Column, e.Header)
+	if e.Cell != "" {
+		location = e.Cell + ": " + location
+	}
+
+	return fmt.Sprintf("sheets: decode %q: %s: %v", e.Range, location, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError returns a new `DecodeError` of the value at "row" and "column" indexes of "rangeValue".
+func newDecodeError(rangeValue ValueRange, row, column int, header string, err error) *DecodeError {
+	e := &DecodeError{
+		Range:  rangeValue.Range,
+		Row:    row,
+		Column: column,
+		Header: header,
+		Err:    err,
+	}
+
+	if rangeValue.MajorDimension == "" || rangeValue.MajorDimension == Rows {
+		e.Cell = cellOf(rangeValue.Range, row, column)
+	}
+
+	return e
+}
 
 type metadata struct {
 	headers []*Header
 	typ     reflect.Type
+	// decoders are the compiled decoders of the headers' fields, in the same order.
+	decoders []fieldDecodeFunc
+	// rowDecoder reports whether the struct implements the `RowDecoder` interface.
+	rowDecoder bool
 
 	decodeFieldFunc *reflect.Value
 }
@@ -91,14 +170,12 @@ type FieldDecoder interface {
 
 var fieldDecoderTyp = reflect.TypeOf((*FieldDecoder)(nil)).Elem()
 
+// getMetadata returns the metadata of the struct "typ" from the default decode cache.
 func getMetadata(typ reflect.Type) *metadata {
-	cacheMu.RLock()
-	meta, ok := cache[typ]
-	cacheMu.RUnlock()
-	if ok {
-		return meta
-	}
+	return defaultDecodeCache.metadata(typ)
+}
 
+func newMetadata(typ reflect.Type) *metadata {
 	if typ.Kind() != reflect.Struct {
 		panic("not a struct type")
 	}
@@ -127,9 +204,20 @@ func getMetadata(typ reflect.Type) *metadata {
 		})
 	}
 
-	meta = &metadata{
-		typ:     typ,
-		headers: headers,
+	decoders := make([]fieldDecodeFunc, len(headers))
+	for i, h := range headers {
+		decoders[i] = compileFieldDecoder(h.FieldType)
+	}
+
+	meta := &metadata{
+		typ:      typ,
+		headers:  headers,
+		decoders: decoders,
+	}
+
+	if reflect.PointerTo(typ).Implements(rowDecoderTyp) {
+		meta.rowDecoder = true
+		return meta
 	}
 
 	if typPtr := reflect.New(typ).Type(); typPtr.Implements(fieldDecoderTyp) {
@@ -139,15 +227,82 @@ func getMetadata(typ reflect.Type) *metadata {
 		}
 	}
 
-	cacheMu.Lock()
-	cache[typ] = meta
-	cacheMu.Unlock()
-
 	return meta
 }
 
+// DecodeErrors is a list of decode errors, one per row which failed to be decoded.
+// See `Decoder.CollectErrors` field.
+type DecodeErrors []*DecodeError
+
+// Error implements a Go error and returns a human-readable error text.
+func (e DecodeErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "sheets: no decode errors"
+	case 1:
+		return e[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+	}
+}
+
+// Unwrap returns the decode errors so that `errors.Is` and `errors.As` check all of them.
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Decoder binds value ranges to Go values.
+// The zero value is ready to use and it's the one `DecodeValueRange` package-level function uses.
+type Decoder struct {
+	// CollectErrors, if true, does not abort at the first row that fails to be decoded.
+	// The failed rows are skipped and their errors are collected into a `DecodeErrors` error,
+	// which is returned after all the successfully decoded rows are bound to the destination.
+	// Useful to ingest human-maintained sheets.
+	CollectErrors bool
+	// Strict, if true, reports a row with more cells than the struct fields as an error.
+	// By default the extra cells are ignored.
+	Strict bool
+	// Cache is the cache of the struct types metadata.
+	// Defaults to the package-level cache, see `ClearDecodeCache` and `NewDecodeCache` package-level functions.
+	Cache *DecodeCache
+	// MaxWorkers, if greater than 1, is the maximum number of value ranges decoded concurrently
+	// when a slice destination receives several ones, e.g. from a `Client.ReadSpreadsheet` call of many data ranges.
+	// The rows are bound in the order of the ranges either way. When enabled, custom field and row decoders
+	// must be safe for concurrent use. Defaults to 0, the ranges are decoded sequentially.
+	MaxWorkers int
+	// InternStrings, if true, interns the cell values decoded to string (and named or pointer to string) fields,
+	// so the rows of a column which repeats the same few values, e.g. a status or a category, share their memory.
+	// It trims the heap of large in-memory datasets at a small decoding cost.
+	// The values decoded by custom decoders (`FieldDecoder` and `RowDecoder`) are not interned.
+	InternStrings bool
+}
+
+func (d *Decoder) metadata(typ reflect.Type) *metadata {
+	if d.Cache != nil {
+		return d.Cache.metadata(typ)
+	}
+
+	return getMetadata(typ)
+}
+
+// ErrExtraCells is the underlying error of a `DecodeError` reported
+// when a row has more cells than the struct fields and `Decoder.Strict` is true.
+var ErrExtraCells = errors.New("row has more cells than struct fields")
+
 // DecodeValueRange binds "rangeValues" to the "dest" pointer of a struct instance.
+// See `Decoder` to collect row errors instead of aborting at the first one.
 func DecodeValueRange(dest interface{}, rangeValues ...ValueRange) error {
+	return new(Decoder).Decode(dest, rangeValues...)
+}
+
+// Decode binds "rangeValues" to the "dest" pointer of a struct instance
+// or a pointer to a slice of structs (or pointers to structs).
+func (d *Decoder) Decode(dest interface{}, rangeValues ...ValueRange) error {
 	if len(rangeValues) == 0 {
 		return nil
 	} else if len(rangeValues[0].Values) == 0 {
@@ -178,65 +333,579 @@ func DecodeValueRange(dest interface{}, rangeValues ...ValueRange) error {
 			return fmt.Errorf("not a pointer to a slice of structs")
 		}
 
-		meta := getMetadata(typ)
+		var errs DecodeErrors
+
+		// Grow the destination once, the rows are decoded in place
+		// instead of appending (and so copying) them one by one.
+		rows := 0
 		for _, rangeValue := range rangeValues {
-			// n := len(rangeValue.Values)
-			// arr := reflect.New(reflect.MakeSlice(elem.Type(), 0, n).Type()).Elem()
+			rows += len(rangeValue.Values)
+		}
+		elem.Grow(rows)
 
-			// if n := len(rangeValue.Values); elem.Cap() < n {
-			// 	elem.SetCap(n)
-			// }
+		meta := d.metadata(typ)
+		if workers := d.workers(len(rangeValues), rows); workers > 1 {
+			return d.decodeParallel(elem, typ, ptrElements, meta, rangeValues, rows, workers)
+		}
 
-			for _, row := range rangeValue.Values {
-				newStructValue := reflect.New(typ)
-				if err := decodeValue(row, meta, newStructValue); err != nil {
-					return err
-				}
-				if !ptrElements {
-					newStructValue = newStructValue.Elem()
+		for _, rangeValue := range rangeValues {
+			for i, row := range rangeValue.Values {
+				n := elem.Len()
+				elem.SetLen(n + 1)
+				slot := elem.Index(n)
+
+				var newStructValue reflect.Value
+				if ptrElements {
+					newStructValue = reflect.New(typ)
+					slot.Set(newStructValue)
+				} else {
+					slot.SetZero() // the backing array may hold a previous value.
+					newStructValue = slot.Addr()
 				}
 
-				elem.Set(reflect.Append(elem, newStructValue))
+				if err := d.decodeValue(rangeValue, i, row, meta, newStructValue); err != nil {
+					slot.SetZero()
+					elem.SetLen(n)
+
+					if !d.CollectErrors {
+						return err
+					}
+
+					errs = append(errs, asDecodeError(rangeValue, i, err))
+					continue
+				}
 			}
 		}
 
+		if len(errs) > 0 {
+			return errs
+		}
+
 		return nil
 	} else if kind != reflect.Struct {
 		return fmt.Errorf("not a pointer to a struct")
 	}
 
-	return decodeValue(rangeValues[0].Values[0], getMetadata(typ), v)
+	err := d.decodeValue(rangeValues[0], 0, rangeValues[0].Values[0], d.metadata(typ), v)
+	if err != nil && d.CollectErrors {
+		return DecodeErrors{asDecodeError(rangeValues[0], 0, err)}
+	}
+
+	return err
+}
+
+// parallelDecodeMinRows is the minimum number of rows worth decoding concurrently.
+const parallelDecodeMinRows = 256
+
+// workers returns the number of goroutines to decode "ranges" of total "rows" with.
+// Parallel decoding is opt-in, see `Decoder.MaxWorkers`.
+func (d *Decoder) workers(ranges, rows int) int {
+	workers := d.MaxWorkers
+	if workers <= 1 || ranges < 2 || rows < parallelDecodeMinRows {
+		return 1
+	}
+
+	if workers > ranges {
+		workers = ranges
+	}
+
+	return workers
+}
+
+// rowError is a row of a value range which failed to be decoded.
+type rowError struct {
+	row int
+	err error
 }
 
-func decodeValue(row []interface{}, meta *metadata, newStructOrPtr reflect.Value) error {
+// decodeParallel decodes the "rangeValues" to the "elem" slice, grown to hold their "rows",
+// using a pool of "workers" goroutines. Each range is decoded in place into its own slots of the slice
+// and then the slots of the failed rows are removed, so the result is the same as the sequential decoding.
+func (d *Decoder) decodeParallel(elem reflect.Value, typ reflect.Type, ptrElements bool, meta *metadata, rangeValues []ValueRange, rows, workers int) error {
+	start := elem.Len()
+	elem.SetLen(start + rows)
+
+	offsets := make([]int, len(rangeValues))
+	for i, offset := 0, start; i < len(rangeValues); i++ {
+		offsets[i] = offset
+		offset += len(rangeValues[i].Values)
+	}
+
+	failures := make([][]rowError, len(rangeValues))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for r := range jobs {
+				rangeValue := rangeValues[r]
+				for i, row := range rangeValue.Values {
+					slot := elem.Index(offsets[r] + i)
+
+					var newStructValue reflect.Value
+					if ptrElements {
+						newStructValue = reflect.New(typ)
+						slot.Set(newStructValue)
+					} else {
+						slot.SetZero()
+						newStructValue = slot.Addr()
+					}
+
+					if err := d.decodeValue(rangeValue, i, row, meta, newStructValue); err != nil {
+						failures[r] = append(failures[r], rowError{row: i, err: err})
+						if !d.CollectErrors {
+							break // the rows after the first error are dropped.
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	for r := range rangeValues {
+		jobs <- r
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Remove the failed (and, when errors are not collected, the not decoded) slots, in order.
+	var (
+		errs     DecodeErrors
+		firstErr error
+		n        = start
+	)
+
+merge:
+	for r, rangeValue := range rangeValues {
+		failed := failures[r]
+		for i := range rangeValue.Values {
+			if len(failed) > 0 && failed[0].row == i {
+				if !d.CollectErrors {
+					firstErr = failed[0].err
+					break merge
+				}
+
+				errs = append(errs, asDecodeError(rangeValue, i, failed[0].err))
+				failed = failed[1:]
+				continue
+			}
+
+			if k := offsets[r] + i; k != n {
+				elem.Index(n).Set(elem.Index(k))
+			}
+			n++
+		}
+	}
+
+	for k := n; k < start+rows; k++ {
+		elem.Index(k).SetZero()
+	}
+	elem.SetLen(n)
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// asDecodeError returns "err" as a `DecodeError`, it annotates it with the row location if necessary.
+func asDecodeError(rangeValue ValueRange, row int, err error) *DecodeError {
+	if decodeErr, ok := err.(*DecodeError); ok {
+		return decodeErr
+	}
+
+	return newDecodeError(rangeValue, row, -1, "", err)
+}
+
+// decodeValue decodes the "row" at "rowIndex" of "rangeValue" to the "newStructOrPtr".
+func (d *Decoder) decodeValue(rangeValue ValueRange, rowIndex int, row []interface{}, meta *metadata, newStructOrPtr reflect.Value) error {
 	if len(row) == 0 || meta == nil || len(meta.headers) == 0 /* all fields are unexported or ignored */ {
 		return nil
 	}
 
+	if n := len(meta.headers); len(row) > n {
+		if d.Strict {
+			return newDecodeError(rangeValue, rowIndex, n, "", fmt.Errorf("%w: %d cells, %d fields", ErrExtraCells, len(row), n))
+		}
+
+		row = row[:n] // ignore extra cells.
+	}
+
+	if meta.rowDecoder {
+		if err := newStructOrPtr.Interface().(RowDecoder).DecodeRow(row); err != nil {
+			return asDecodeError(rangeValue, rowIndex, err)
+		}
+
+		return nil
+	}
+
+	structValue := newStructOrPtr.Elem()
 	for i, value := range row {
 		h := meta.headers[i]
 
-		val := reflect.ValueOf(value)
-
 		if meta.decodeFieldFunc != nil {
+			value := value                        // do not move the loop's value to the heap.
+			val := reflect.ValueOf(&value).Elem() // keep the interface type, the value may be nil.
 			out := meta.decodeFieldFunc.Call([]reflect.Value{newStructOrPtr, reflect.ValueOf(h), val})
 			if errV := out[0]; !errV.IsNil() {
 				// if ErrOK should continue with the default behavior for this field.
 				if err := errV.Interface().(error); err != ErrOK {
-					return err
+					return newDecodeError(rangeValue, rowIndex, i, h.Name, err)
 				}
 			} else {
 				continue
 			}
 		}
 
-		newStructValue := newStructOrPtr.Elem()
-
-		if val.Type().AssignableTo(h.FieldType) {
-			fieldValue := newStructValue.Field(h.FieldIndex)
-			fieldValue.Set(val)
+		if d.InternStrings && isStringType(h.FieldType) {
+			if s, ok := value.(string); ok {
+				value = unique.Make(s).Value()
+			}
 		}
+
+		meta.decoders[i](structValue.Field(h.FieldIndex), value)
 	}
 
 	return nil
 }
+
+var timeTyp = reflect.TypeOf(time.Time{})
+
+// timeLayouts are the layouts of the date and time cell values parsed into time.Time fields.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// serialEpoch is the epoch of the serial numbers of date and time cell values (Lotus 1-2-3 compatible).
+var serialEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// isStringType reports whether "typ" is a string or a pointer to string kind.
+func isStringType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.String
+}
+
+// fieldDecodeFunc sets a cell value to a struct field, see `compileFieldDecoder`.
+type fieldDecodeFunc func(field reflect.Value, value interface{})
+
+// valueDecodeFunc sets a cell value to "v" and reports whether it was set, see `setValue`.
+type valueDecodeFunc func(v reflect.Value, value interface{}) bool
+
+var (
+	stringTyp          = reflect.TypeOf("")
+	boolTyp            = reflect.TypeOf(false)
+	textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// compileFieldDecoder returns the decoder of a struct field of "typ", it's compiled once per struct type
+// (see `metadata.decoders`) so that decoding a row is a loop of direct function calls
+// instead of resolving the conversion through reflection on each cell.
+// It behaves exactly like `setFieldValue`.
+func compileFieldDecoder(typ reflect.Type) fieldDecodeFunc {
+	if typ.Kind() != reflect.Ptr {
+		decode := compileValueDecoder(typ)
+		return func(field reflect.Value, value interface{}) {
+			decode(field, value)
+		}
+	}
+
+	elemTyp := typ.Elem()
+	decode := compileValueDecoder(elemTyp)
+	return func(field reflect.Value, value interface{}) {
+		if value == nil || value == "" {
+			field.Set(reflect.Zero(typ))
+			return
+		}
+
+		if ptr := reflect.New(elemTyp); decode(ptr.Elem(), value) {
+			field.Set(ptr)
+		}
+	}
+}
+
+// compileValueDecoder returns a fast path of `setValue` for the common field types
+// and cell values, i.e. strings, float64 numbers and bools.
+// Other types and values fall back to `setValue`.
+func compileValueDecoder(typ reflect.Type) valueDecodeFunc {
+	if typ == timeTyp || reflect.PointerTo(typ).Implements(textUnmarshalerTyp) {
+		return setValue
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		if typ != stringTyp {
+			return setValue
+		}
+
+		return func(v reflect.Value, value interface{}) bool {
+			switch value := value.(type) {
+			case string:
+				v.SetString(value)
+				return true
+			case float64, bool:
+				v.SetString(fmt.Sprint(value))
+				return true
+			default:
+				return setValue(v, value)
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, value interface{}) bool {
+			if i, ok := value.(int); ok { // e.g. values built in Go.
+				v.SetInt(int64(i))
+				return true
+			}
+
+			f, ok := decodeFloat(value)
+			if !ok {
+				return setValue(v, value)
+			}
+
+			v.SetInt(int64(f))
+			return true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value, value interface{}) bool {
+			f, ok := decodeFloat(value)
+			if !ok {
+				return setValue(v, value)
+			}
+
+			v.SetUint(uint64(f))
+			return true
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, value interface{}) bool {
+			f, ok := decodeFloat(value)
+			if !ok {
+				return setValue(v, value)
+			}
+
+			v.SetFloat(f)
+			return true
+		}
+	case reflect.Bool:
+		if typ != boolTyp {
+			return setValue
+		}
+
+		return func(v reflect.Value, value interface{}) bool {
+			if b, ok := value.(bool); ok {
+				v.SetBool(b)
+				return true
+			}
+
+			return setValue(v, value)
+		}
+	default:
+		return setValue
+	}
+}
+
+// decodeFloat returns the number of a float64 or a numeric string cell value.
+// It reports false for other values, which are decoded by `setValue`.
+func decodeFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// setFieldValue sets the cell "value" to the struct "field".
+// Pointer fields, e.g. *string, *int and *time.Time, are left nil for empty or missing cells,
+// so a blank cell can be told apart from a genuine zero value.
+// Values that cannot be converted to the field's type are skipped.
+func setFieldValue(field reflect.Value, value interface{}) {
+	if field.Kind() != reflect.Ptr {
+		setValue(field, value)
+		return
+	}
+
+	if value == nil || value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+
+	if ptr := reflect.New(field.Type().Elem()); setValue(ptr.Elem(), value) {
+		field.Set(ptr)
+	}
+}
+
+// setValue sets "value" to "v", converting it if necessary.
+// It reports whether the value was set.
+func setValue(v reflect.Value, value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Type().AssignableTo(v.Type()) {
+		v.Set(val)
+		return true
+	}
+
+	if v.Type() == timeTyp {
+		t, ok := parseTime(value)
+		if ok {
+			v.Set(reflect.ValueOf(t))
+		}
+		return ok
+	}
+
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(formatCell(value))) == nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s, ok := value.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return false
+			}
+			val = reflect.ValueOf(f)
+		}
+
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			v.Set(val.Convert(v.Type()))
+			return true
+		}
+	case reflect.Bool:
+		switch value := value.(type) {
+		case string:
+			// Checkbox cells are formatted as "TRUE" and "FALSE", a cleared one may be empty.
+			s := strings.TrimSpace(value)
+			if s == "" {
+				v.SetBool(false)
+				return true
+			}
+
+			b, err := strconv.ParseBool(strings.ToLower(s))
+			if err != nil {
+				return false
+			}
+			v.SetBool(b)
+			return true
+		case float64:
+			v.SetBool(value != 0)
+			return true
+		}
+	case reflect.String: // e.g. named string types, which a string value is not assignable to.
+		switch value := value.(type) {
+		case string:
+			v.SetString(value)
+			return true
+		case float64, bool:
+			v.SetString(fmt.Sprint(value))
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTime parses a date and time cell value,
+// either a formatted string or a serial number.
+func parseTime(value interface{}) (time.Time, bool) {
+	switch value := value.(type) {
+	case float64:
+		days, fraction := math.Modf(value)
+		return serialEpoch.AddDate(0, 0, int(days)).Add(time.Duration(fraction * float64(24*time.Hour))).Round(time.Millisecond), true
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// encodeRow returns the row values of "v".
+// The "v" can be a row of values or a struct value (or a pointer to a struct value),
+// the struct fields are encoded in the order of their headers.
+func encodeRow(v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return v, nil
+	case RowEncoder:
+		return v.EncodeRow()
+	}
+
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("not a struct or a row of values")
+	}
+
+	meta := getMetadata(val.Type())
+	row := make([]interface{}, len(meta.headers))
+	for i, h := range meta.headers {
+		value, err := encodeValue(val.Field(h.FieldIndex))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", h.FieldName, err)
+		}
+
+		row[i] = value
+	}
+
+	return row, nil
+}
+
+// encodeValue returns the cell value of a struct field.
+// Values which implement the `encoding.TextMarshaler` (e.g. UUIDs and decimals)
+// or the `fmt.Stringer` interface are encoded as text.
+func encodeValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	value := v.Interface()
+	if _, ok := value.(encoding.TextMarshaler); !ok && v.CanAddr() {
+		if _, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			value = v.Addr().Interface()
+		}
+	}
+
+	switch m := value.(type) {
+	case encoding.TextMarshaler:
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	case fmt.Stringer:
+		return m.String(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
@@ -1,7 +1,14 @@
 package sheets
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 type testRow struct {
@@ -100,3 +107,402 @@ func TestFieldDecoder(t *testing.T) {
 		}
 	}
 }
+
+type testRowFailingDecoder struct {
+	Name string `sheets:"Name"`
+	Age  int    `sheets:"Age"`
+}
+
+func (t *testRowFailingDecoder) DecodeField(h *Header, value interface{}) error {
+	if h.Name == "Age" && value == "n/a" {
+		return fmt.Errorf("invalid age %q", value)
+	}
+
+	return ErrOK
+}
+
+func TestDecodeValueRangeError(t *testing.T) {
+	var dest []testRowFailingDecoder
+	err := DecodeValueRange(&dest, ValueRange{
+		Range:  "Users!A2:B4",
+		Values: [][]interface{}{{"makis", 30.0}, {"giwrgos", "n/a"}},
+	})
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a decode error but got %v", err)
+	}
+
+	if decodeErr.Row != 1 || decodeErr.Column != 1 || decodeErr.Header != "Age" {
+		t.Fatalf("unexpected decode error location: %#v", decodeErr)
+	}
+	if expected, got := "Users!B3", decodeErr.Cell; expected != got {
+		t.Fatalf("expected cell %q but got %q", expected, got)
+	}
+	if expected, got := `sheets: decode "Users!A2:B4": Users!B3: row 1, column 1 (Age): invalid age "n/a"`, err.Error(); expected != got {
+		t.Fatalf("expected error %q but got %q", expected, got)
+	}
+}
+
+func TestDecoderCollectErrors(t *testing.T) {
+	var dest []testRowFailingDecoder
+	err := (&Decoder{CollectErrors: true}).Decode(&dest, ValueRange{
+		Range:  "Users!A2:B5",
+		Values: [][]interface{}{{"makis", "n/a"}, {"giwrgos", 30.0}, {"efi", "n/a"}},
+	})
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected decode errors but got %v", err)
+	}
+
+	if expected, got := 2, len(errs); expected != got {
+		t.Fatalf("expected %d errors but got %d", expected, got)
+	}
+	if errs[0].Cell != "Users!B2" || errs[1].Cell != "Users!B4" {
+		t.Fatalf("unexpected error cells: %s, %s", errs[0].Cell, errs[1].Cell)
+	}
+
+	if expected, got := 1, len(dest); expected != got {
+		t.Fatalf("expected %d decoded rows but got %d", expected, got)
+	}
+	if expected, got := "giwrgos", dest[0].Name; expected != got {
+		t.Fatalf("expected name %q but got %q", expected, got)
+	}
+}
+
+func TestDecodeValueRangeExtraCells(t *testing.T) {
+	rangeValue := ValueRange{
+		Range:  "A1:D2",
+		Values: [][]interface{}{{"makis", "extra", "cells"}},
+	}
+
+	var dest []testRow
+	if err := DecodeValueRange(&dest, rangeValue); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "makis", dest[0].Name; expected != got {
+		t.Fatalf("expected name %q but got %q", expected, got)
+	}
+
+	err := (&Decoder{Strict: true}).Decode(&dest, rangeValue)
+	if !errors.Is(err, ErrExtraCells) {
+		t.Fatalf("expected extra cells error but got %v", err)
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr); decodeErr.Cell != "C1" {
+		t.Fatalf("expected extra cells error at C1 but got %v", err)
+	}
+}
+
+type testRowPointers struct {
+	Name     *string
+	Age      *int
+	Birthday *time.Time
+	Active   *bool
+}
+
+func TestDecodeValueRangePointerFields(t *testing.T) {
+	var dest []testRowPointers
+	err := DecodeValueRange(&dest, ValueRange{
+		Values: [][]interface{}{
+			{"makis", "0", "1990-05-17", "TRUE"},
+			{"", "", ""},
+			{"giwrgos", 30.0, 33010.5},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if row := dest[0]; *row.Name != "makis" || *row.Age != 0 || row.Birthday.Format("2006-01-02") != "1990-05-17" || !*row.Active {
+		t.Fatalf("unexpected first row: %#v", row)
+	}
+
+	if row := dest[1]; row.Name != nil || row.Age != nil || row.Birthday != nil || row.Active != nil {
+		t.Fatalf("expected nil fields for empty and missing cells but got: %#v", row)
+	}
+
+	if expected, got := time.Date(1990, time.May, 17, 12, 0, 0, 0, time.UTC), *dest[2].Birthday; !expected.Equal(got) {
+		t.Fatalf("expected birthday %s but got %s", expected, got)
+	}
+	if expected, got := 30, *dest[2].Age; expected != got {
+		t.Fatalf("expected age %d but got %d", expected, got)
+	}
+}
+
+type testLevel int
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	if l == 1 {
+		return []byte("high"), nil
+	}
+
+	return []byte("low"), nil
+}
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "high":
+		*l = 1
+	case "low":
+		*l = 0
+	default:
+		return fmt.Errorf("invalid level %q", text)
+	}
+
+	return nil
+}
+
+type testRowText struct {
+	Level  testLevel
+	Amount *big.Int
+	Due    time.Time
+}
+
+func TestDecodeEncodeTextMarshaler(t *testing.T) {
+	var dest []testRowText
+	err := DecodeValueRange(&dest, ValueRange{
+		Values: [][]interface{}{{"high", "123456789012345678901234567890", "2024-01-02"}, {"unknown", ""}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dest[0].Level != 1 || dest[0].Amount.String() != "123456789012345678901234567890" || dest[0].Due.Day() != 2 {
+		t.Fatalf("unexpected first row: %#v", dest[0])
+	}
+	if dest[1].Level != 0 || dest[1].Amount != nil {
+		t.Fatalf("unexpected second row: %#v", dest[1])
+	}
+
+	row, err := encodeRow(dest[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{"high", "123456789012345678901234567890", "2024-01-02T00:00:00Z"}
+	if !reflect.DeepEqual(expected, row) {
+		t.Fatalf("expected row %v but got %v", expected, row)
+	}
+}
+
+type testRowCheckbox struct {
+	Task string
+	Done bool
+}
+
+func TestDecodeEncodeCheckbox(t *testing.T) {
+	var dest []testRowCheckbox
+	err := DecodeValueRange(&dest, ValueRange{
+		Values: [][]interface{}{{"a", "TRUE"}, {"b", "FALSE"}, {"c", ""}, {"d", true}, {"e", 1.0}, {"f", " True "}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testRowCheckbox{{"a", true}, {"b", false}, {"c", false}, {"d", true}, {"e", true}, {"f", true}}
+	if !reflect.DeepEqual(expected, dest) {
+		t.Fatalf("expected %v but got %v", expected, dest)
+	}
+
+	row, err := encodeRow(dest[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []interface{}{"a", true}; !reflect.DeepEqual(expected, row) {
+		t.Fatalf("expected row %v but got %v", expected, row)
+	}
+}
+
+func TestCompileFieldDecoder(t *testing.T) {
+	type (
+		named     string
+		namedInt  int8
+		namedBool bool
+	)
+
+	var (
+		types = []reflect.Type{
+			reflect.TypeOf(""), reflect.TypeOf(named("")), reflect.TypeOf(0), reflect.TypeOf(namedInt(0)),
+			reflect.TypeOf(uint(0)), reflect.TypeOf(0.0), reflect.TypeOf(float32(0)), reflect.TypeOf(false),
+			reflect.TypeOf(namedBool(false)), reflect.TypeOf(time.Time{}), reflect.TypeOf(testLevel(0)),
+			reflect.TypeOf((*interface{})(nil)).Elem(), reflect.TypeOf((*string)(nil)), reflect.TypeOf((*int)(nil)),
+			reflect.TypeOf((*time.Time)(nil)), reflect.TypeOf((*big.Int)(nil)),
+		}
+		values = []interface{}{nil, "", "text", "42", "-3.5", "TRUE", "high", "2024-01-02", 42.0, 7, true, false}
+	)
+
+	for _, typ := range types {
+		decode := compileFieldDecoder(typ)
+		for _, value := range values {
+			expected, got := reflect.New(typ).Elem(), reflect.New(typ).Elem()
+			setFieldValue(expected, value)
+			decode(got, value)
+
+			if !reflect.DeepEqual(expected.Interface(), got.Interface()) {
+				t.Fatalf("%s: %#v: expected %#v but got %#v", typ, value, expected.Interface(), got.Interface())
+			}
+		}
+	}
+}
+
+type testRowCodec struct {
+	Name string
+	Age  int
+}
+
+func (r *testRowCodec) DecodeRow(row []interface{}) error {
+	for i, value := range row {
+		switch i {
+		case 0:
+			r.Name, _ = CellString(value)
+		case 1:
+			age, ok := CellInt(value)
+			if !ok {
+				return fmt.Errorf("invalid age %v", value)
+			}
+			r.Age = int(age)
+		}
+	}
+
+	return nil
+}
+
+func (r testRowCodec) EncodeRow() ([]interface{}, error) {
+	return []interface{}{r.Name, "age:" + fmt.Sprint(r.Age)}, nil
+}
+
+func TestRowDecoderEncoder(t *testing.T) {
+	var dest []testRowCodec
+	err := (&Decoder{Strict: true}).Decode(&dest, ValueRange{Range: "Sheet1!A2:B3", Values: [][]interface{}{{"makis", "27"}, {"giwrgos", 30.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []testRowCodec{{"makis", 27}, {"giwrgos", 30}}; !reflect.DeepEqual(expected, dest) {
+		t.Fatalf("expected %v but got %v", expected, dest)
+	}
+
+	err = DecodeValueRange(&dest, ValueRange{Range: "Sheet1!A2:B3", Values: [][]interface{}{{"makis", "n/a"}}})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Row != 0 {
+		t.Fatalf("expected a decode error of the first row but got %v", err)
+	}
+
+	row, err := encodeRow(&dest[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []interface{}{"makis", "age:27"}; !reflect.DeepEqual(expected, row) {
+		t.Fatalf("expected row %v but got %v", expected, row)
+	}
+}
+
+func TestDecodeValueRangeExtendsSlice(t *testing.T) {
+	backing := []testRow{{Name: "kataras", Age: 27}, {Name: "stale", Other: "stale", Age: 99}}
+	dest := backing[:1]
+
+	err := (&Decoder{CollectErrors: true}).Decode(&dest, ValueRange{Values: [][]interface{}{{"makis"}}}, ValueRange{Values: [][]interface{}{{"giwrgos", 30.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testRow{{Name: "kataras", Age: 27}, {Name: "makis"}, {Name: "giwrgos", Age: 30}}
+	if !reflect.DeepEqual(expected, dest) {
+		t.Fatalf("expected %v but got %v", expected, dest)
+	}
+
+	var failing []testRowFailingDecoder
+	err = (&Decoder{CollectErrors: true}).Decode(&failing, ValueRange{Values: [][]interface{}{{"makis", "n/a"}, {"giwrgos", "30"}}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(failing) != 1 || failing[0].Name != "giwrgos" {
+		t.Fatalf("expected the failed row to be skipped but got %v", failing)
+	}
+}
+
+func TestDecodeParallel(t *testing.T) {
+	rangeValues := make([]ValueRange, 8)
+	for r := range rangeValues {
+		rangeValues[r].Range = fmt.Sprintf("Sheet%d!A2:B", r)
+		for i := 0; i < 100; i++ {
+			var age interface{} = float64(i)
+			if (r == 2 || r == 5) && i%30 == 7 {
+				age = "n/a"
+			}
+			rangeValues[r].Values = append(rangeValues[r].Values, []interface{}{fmt.Sprint(r, "-", i), age})
+		}
+	}
+
+	if workers := new(Decoder).workers(len(rangeValues), 800); workers != 1 {
+		t.Fatalf("expected sequential decoding by default but got %d workers", workers)
+	}
+
+	for _, collectErrors := range []bool{false, true} {
+		sequential := &Decoder{CollectErrors: collectErrors}
+		parallel := &Decoder{CollectErrors: collectErrors, MaxWorkers: 4}
+
+		expected := []testRowFailingDecoder{{Name: "existing"}}
+		expectedErr := sequential.Decode(&expected, rangeValues...)
+
+		got := []testRowFailingDecoder{{Name: "existing"}}
+		gotErr := parallel.Decode(&got, rangeValues...)
+
+		if expectedErr == nil || expectedErr.Error() != gotErr.Error() {
+			t.Fatalf("[collect errors=%v] expected error %v but got %v", collectErrors, expectedErr, gotErr)
+		}
+
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("[collect errors=%v] expected %d decoded rows but got %d", collectErrors, len(expected), len(got))
+		}
+
+		var ptrs []*testRowFailingDecoder
+		parallel.Decode(&ptrs, rangeValues...)
+		if len(ptrs) != len(expected)-1 || *ptrs[len(ptrs)-1] != expected[len(expected)-1] {
+			t.Fatalf("[collect errors=%v] expected %d decoded pointers but got %d", collectErrors, len(expected)-1, len(ptrs))
+		}
+	}
+}
+
+type testStatus string
+
+func TestDecodeInternStrings(t *testing.T) {
+	type task struct {
+		Title    string      `sheets:"Title"`
+		Status   string      `sheets:"Status"`
+		Owner    *string     `sheets:"Owner"`
+		State    testStatus  `sheets:"State"`
+		Previous *testStatus `sheets:"Previous"`
+	}
+
+	var values [][]interface{}
+	for i := 0; i < 3; i++ {
+		// Build each value separately, as the JSON decoder does.
+		values = append(values, []interface{}{fmt.Sprint("task ", i), strings.Clone("done"), strings.Clone("makis"),
+			strings.Clone("open"), strings.Clone("closed")})
+	}
+
+	for _, intern := range []bool{false, true} {
+		var dest []task
+		if err := (&Decoder{InternStrings: intern}).Decode(&dest, ValueRange{Values: values}); err != nil {
+			t.Fatal(err)
+		}
+
+		if dest[2].Status != "done" || *dest[2].Owner != "makis" || dest[2].State != "open" ||
+			dest[2].Previous == nil || *dest[2].Previous != "closed" {
+			t.Fatalf("unexpected decoded value: %#v", dest[2])
+		}
+
+		shared := unsafe.StringData(dest[0].Status) == unsafe.StringData(dest[2].Status) &&
+			unsafe.StringData(*dest[0].Owner) == unsafe.StringData(*dest[2].Owner) &&
+			unsafe.StringData(string(dest[0].State)) == unsafe.StringData(string(dest[2].State)) &&
+			unsafe.StringData(string(*dest[0].Previous)) == unsafe.StringData(string(*dest[2].Previous))
+		if shared != intern {
+			t.Fatalf("expected the repeated values to share memory: %v but got: %v", intern, shared)
+		}
+	}
+}
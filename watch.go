@@ -0,0 +1,218 @@
+package sheets
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	driveWatchURL            = driveFileURL + "/watch"
+	driveChannelsStopURL     = "/drive/v3/channels/stop"
+	driveChangesURL          = "/drive/v3/changes"
+	driveChangesStartPageURL = driveChangesURL + "/startPageToken"
+)
+
+// WatchChannel is a Drive notification channel which delivers the changes of a spreadsheet to a webhook.
+// See `Client.WatchSpreadsheet` method.
+type WatchChannel struct {
+	// ID is the channel ID, unique per channel.
+	ID string
+	// ResourceID identifies the watched spreadsheet, it's required to stop the channel.
+	ResourceID string
+	// Token is sent back on each notification, see `WatchHandler`.
+	Token string
+	// Expiration is the time the channel stops delivering notifications,
+	// it should be renewed with a new channel before that.
+	Expiration time.Time
+}
+
+// WatchNotification is a notification of a Drive channel, see `WatchHandler`.
+type WatchNotification struct {
+	ChannelID  string
+	ResourceID string
+	// State is "sync" for the first notification of a channel,
+	// "update" for a content or metadata change, "trash", "remove" and so on.
+	State string
+	// Changed lists the changed parts on an "update", e.g. "content", "properties", "parents".
+	Changed string
+	// MessageNumber increases for each notification of a channel.
+	MessageNumber int64
+}
+
+type watchChannelJSON struct {
+	ID         string `json:"id"`
+	ResourceID string `json:"resourceId,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Address    string `json:"address,omitempty"`
+	Token      string `json:"token,omitempty"`
+	Expiration string `json:"expiration,omitempty"` // Unix time in milliseconds.
+}
+
+// WatchSpreadsheet registers a Drive notification channel of "channelID" (e.g. a UUID) which posts to the HTTPS "address"
+// when the spreadsheet of "spreadsheetID" is modified, so caches and downstream systems can invalidate promptly.
+// The "token" is sent back on each notification so the receiver can verify it, see `WatchHandler`.
+// A zero "ttl" uses the Drive default of one hour, the maximum is one day.
+//
+// See `StopWatch` and `PollChanges` methods too.
+func (c *Client) WatchSpreadsheet(ctx context.Context, spreadsheetID, channelID, address, token string, ttl time.Duration) (*WatchChannel, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/files/watch
+	url := c.driveURL(driveWatchURL, spreadsheetID)
+
+	request := watchChannelJSON{
+		ID:      channelID,
+		Type:    "web_hook",
+		Address: address,
+		Token:   token,
+	}
+	if ttl > 0 {
+		request.Expiration = strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10)
+	}
+
+	var response watchChannelJSON
+	if err := c.ReadJSON(ctx, http.MethodPost, url, request, &response, driveQuery("id,resourceId,token,expiration")); err != nil {
+		return nil, c.opError(err, "files.watch", spreadsheetID, "", 0)
+	}
+
+	channel := &WatchChannel{ID: response.ID, ResourceID: response.ResourceID, Token: response.Token}
+	if response.Expiration != "" {
+		ms, err := strconv.ParseInt(response.Expiration, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sheets: watch: invalid channel expiration %q: %w", response.Expiration, err)
+		}
+
+		channel.Expiration = time.UnixMilli(ms)
+	}
+
+	return channel, nil
+}
+
+// StopWatch stops the notifications of the "channel", see `WatchSpreadsheet` method.
+func (c *Client) StopWatch(ctx context.Context, channel *WatchChannel) error {
+	// https://developers.google.com/drive/api/reference/rest/v3/channels/stop
+	url := c.driveURL(driveChannelsStopURL)
+
+	err := c.ReadJSON(ctx, http.MethodPost, url, watchChannelJSON{ID: channel.ID, ResourceID: channel.ResourceID}, nil)
+	return c.opError(err, "channels.stop", "", "", 0)
+}
+
+// WatchHandler returns an http.Handler which receives the notifications of the Drive channels
+// registered by `Client.WatchSpreadsheet` and calls "onChange" for each one of them, except the initial "sync" ones.
+// If "token" is not empty then notifications without the same channel token are rejected.
+func WatchHandler(token string, onChange func(WatchNotification)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(r.Header.Get("X-Goog-Channel-Token"))) != 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		n := WatchNotification{
+			ChannelID:  r.Header.Get("X-Goog-Channel-ID"),
+			ResourceID: r.Header.Get("X-Goog-Resource-ID"),
+			State:      r.Header.Get("X-Goog-Resource-State"),
+			Changed:    r.Header.Get("X-Goog-Changed"),
+		}
+		n.MessageNumber, _ = strconv.ParseInt(r.Header.Get("X-Goog-Message-Number"), 10, 64)
+
+		// Drive retries the notifications which are not acknowledged with a 2xx status code.
+		w.WriteHeader(http.StatusOK)
+
+		if n.State != "sync" {
+			onChange(n)
+		}
+	})
+}
+
+// DriveChange is a change of a Drive file, see `Client.PollChanges` method.
+type DriveChange struct {
+	FileID  string    `json:"fileId"`
+	Removed bool      `json:"removed"`
+	Time    time.Time `json:"time"`
+	// File is the file's metadata after the change, nil if it was removed.
+	File *DriveFile `json:"file"`
+}
+
+// PollChanges polls the Drive changes feed every "interval" and calls "onChange"
+// for each change of the spreadsheet of "spreadsheetID", when a webhook cannot be exposed (see `WatchSpreadsheet`).
+// Only the changes after the call are reported. It blocks until the "ctx" is canceled,
+// in which case it returns the context's error, or a request fails.
+// A zero or negative "interval" defaults to one minute.
+func (c *Client) PollChanges(ctx context.Context, spreadsheetID string, interval time.Duration, onChange func(DriveChange)) error {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	// https://developers.google.com/drive/api/reference/rest/v3/changes/getStartPageToken
+	var start struct {
+		StartPageToken string `json:"startPageToken"`
+	}
+	err := c.ReadJSON(ctx, http.MethodGet, c.driveURL(driveChangesStartPageURL), nil, &start, driveQuery("startPageToken"))
+	if err != nil {
+		return c.opError(err, "changes.getStartPageToken", spreadsheetID, "", 0)
+	}
+
+	pageToken := start.StartPageToken
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		pageToken, err = c.listChanges(ctx, spreadsheetID, pageToken, onChange)
+		if err != nil {
+			if ctx.Err() != nil { // e.g. canceled by the "onChange".
+				return ctx.Err()
+			}
+
+			return err
+		}
+	}
+}
+
+// listChanges reports the changes of the spreadsheet since the "pageToken"
+// and returns the page token of the next poll.
+func (c *Client) listChanges(ctx context.Context, spreadsheetID, pageToken string, onChange func(DriveChange)) (string, error) {
+	// https://developers.google.com/drive/api/reference/rest/v3/changes/list
+	url := c.driveURL(driveChangesURL)
+
+	for {
+		// The "supportsAllDrives" parameter, required along with the "includeItemsFromAllDrives" one, is set by driveQuery.
+		q := driveQuery("nextPageToken,newStartPageToken,changes(fileId,removed,time,file(" + driveFileFields + "))")
+		q["includeItemsFromAllDrives"] = []string{"true"}
+		q["pageToken"] = []string{pageToken}
+
+		var response struct {
+			NextPageToken     string        `json:"nextPageToken"`
+			NewStartPageToken string        `json:"newStartPageToken"`
+			Changes           []DriveChange `json:"changes"`
+		}
+		if err := c.ReadJSON(ctx, http.MethodGet, url, nil, &response, q); err != nil {
+			return pageToken, c.opError(err, "changes.list", spreadsheetID, "", 0)
+		}
+
+		for _, change := range response.Changes {
+			if change.FileID == spreadsheetID {
+				onChange(change)
+			}
+		}
+
+		if response.NextPageToken == "" {
+			return response.NewStartPageToken, nil
+		}
+
+		pageToken = response.NextPageToken
+	}
+}
@@ -0,0 +1,185 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientWatchSpreadsheet(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var channel map[string]string
+		json.NewDecoder(r.Body).Decode(&channel)
+
+		switch r.URL.Path {
+		case "/drive/v3/files/id/watch":
+			if channel["type"] != "web_hook" || channel["address"] != "https://example.com/hook" || channel["expiration"] == "" {
+				t.Errorf("unexpected watch request: %v", channel)
+			}
+			json.NewEncoder(w).Encode(map[string]string{
+				"id":         channel["id"],
+				"resourceId": "res",
+				"token":      channel["token"],
+				"expiration": strconv.FormatInt(expiration.UnixMilli(), 10),
+			})
+		case "/drive/v3/channels/stop":
+			if channel["id"] != "ch" || channel["resourceId"] != "res" {
+				t.Errorf("unexpected stop request: %v", channel)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx := context.Background()
+
+	channel, err := c.WatchSpreadsheet(ctx, "id", "ch", "https://example.com/hook", "secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &WatchChannel{ID: "ch", ResourceID: "res", Token: "secret", Expiration: expiration}
+	if !reflect.DeepEqual(expected, channel) {
+		t.Fatalf("expected channel %#v but got %#v", expected, channel)
+	}
+
+	if err = c.StopWatch(ctx, channel); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchHandler(t *testing.T) {
+	var notifications []WatchNotification
+	h := WatchHandler("secret", func(n WatchNotification) {
+		notifications = append(notifications, n)
+	})
+
+	send := func(token, state string) int {
+		r := httptest.NewRequest(http.MethodPost, "/hook", nil)
+		r.Header.Set("X-Goog-Channel-ID", "ch")
+		r.Header.Set("X-Goog-Channel-Token", token)
+		r.Header.Set("X-Goog-Resource-ID", "res")
+		r.Header.Set("X-Goog-Resource-State", state)
+		r.Header.Set("X-Goog-Changed", "content")
+		r.Header.Set("X-Goog-Message-Number", "2")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := send("secret", "sync"); code != http.StatusOK {
+		t.Fatalf("expected status OK but got %d", code)
+	}
+	if code := send("other", "update"); code != http.StatusForbidden {
+		t.Fatalf("expected status forbidden but got %d", code)
+	}
+	if code := send("secret", "update"); code != http.StatusOK {
+		t.Fatalf("expected status OK but got %d", code)
+	}
+
+	expected := []WatchNotification{{ChannelID: "ch", ResourceID: "res", State: "update", Changed: "content", MessageNumber: 2}}
+	if !reflect.DeepEqual(expected, notifications) {
+		t.Fatalf("expected notifications %#v but got %#v", expected, notifications)
+	}
+}
+
+func TestClientPollChanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/changes/startPageToken":
+			w.Write([]byte(`{"startPageToken":"1"}`))
+		case "/drive/v3/changes":
+			if q := r.URL.Query(); q.Get("supportsAllDrives") != "true" || q.Get("includeItemsFromAllDrives") != "true" {
+				t.Errorf("expected the all drives parameters but got %q", r.URL.RawQuery)
+			}
+
+			switch r.URL.Query().Get("pageToken") {
+			case "1":
+				w.Write([]byte(`{"nextPageToken":"2","changes":[{"fileId":"other"},{"fileId":"id","file":{"id":"id","name":"Report"}}]}`))
+			case "2":
+				w.Write([]byte(`{"newStartPageToken":"3","changes":[{"fileId":"id","removed":true}]}`))
+			default:
+				w.Write([]byte(`{"newStartPageToken":"3"}`))
+			}
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes []DriveChange
+	err := c.PollChanges(ctx, "id", time.Millisecond, func(change DriveChange) {
+		changes = append(changes, change)
+		if len(changes) == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error but got %v", err)
+	}
+
+	if len(changes) != 2 || changes[0].File == nil || changes[0].File.Name != "Report" || !changes[1].Removed {
+		t.Fatalf("unexpected changes: %#v", changes)
+	}
+}
+
+func TestClientPollChangesCanceledDuringRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/changes/startPageToken":
+			w.Write([]byte(`{"startPageToken":"1"}`))
+		case "/drive/v3/changes":
+			w.Write([]byte(`{"nextPageToken":"2","changes":[{"fileId":"id"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The next page request fails because of the canceled context,
+	// the context error is returned instead of the request one.
+	err := c.PollChanges(ctx, "id", time.Millisecond, func(DriveChange) { cancel() })
+	if err != context.Canceled {
+		t.Fatalf("expected the context canceled error but got %v", err)
+	}
+}
+
+func TestClientPollChangesDefaultInterval(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/changes/startPageToken":
+			w.Write([]byte(`{"startPageToken":"1"}`))
+		default:
+			t.Errorf("unexpected request before the default interval: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// A zero interval must not panic, it polls every minute instead.
+	err := c.PollChanges(ctx, "id", 0, func(DriveChange) {})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected the context deadline exceeded error but got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package sheets
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// DefaultMaxWriteRows is the default `Client.MaxWriteRows` value.
+	DefaultMaxWriteRows = 10000
+	// DefaultMaxWriteBytes is the default `Client.MaxWriteBytes` value,
+	// the recommended maximum payload size of the Sheets API.
+	DefaultMaxWriteBytes = 2 << 20
+)
+
+// writeChunks splits the rows of "values" so that each chunk does not exceed
+// the Client's write limits. Each chunk's range is the first cell of its rows,
+// the values of a request are written starting at the range's first cell.
+// It returns nil if the values do not need to be split or cannot be split,
+// i.e. they are not in rows or their range is not an A1 notation one, e.g. a named range.
+func (c *Client) writeChunks(values ValueRange) []ValueRange {
+	if values.MajorDimension != Rows || len(values.Values) < 2 {
+		return nil
+	}
+
+	rowsPerChunk := len(values.Values)
+	if maxRows := c.MaxWriteRows; maxRows >= 0 {
+		if maxRows == 0 {
+			maxRows = DefaultMaxWriteRows
+		}
+
+		if maxRows < rowsPerChunk {
+			rowsPerChunk = maxRows
+		}
+	}
+
+	if maxBytes := c.MaxWriteBytes; maxBytes >= 0 {
+		if maxBytes == 0 {
+			maxBytes = DefaultMaxWriteBytes
+		}
+
+		if b, err := c.codec().Marshal(values.Values); err == nil && len(b) > maxBytes {
+			// Assume the rows are of similar size.
+			if n := int(int64(len(values.Values)) * int64(maxBytes) / int64(len(b))); n < rowsPerChunk {
+				rowsPerChunk = n
+			}
+		}
+	}
+
+	if rowsPerChunk < 1 {
+		rowsPerChunk = 1
+	}
+
+	if rowsPerChunk >= len(values.Values) {
+		return nil
+	}
+
+	sheetTitle, gridRange, err := ParseA1(values.Range)
+	if err != nil || (!strings.Contains(values.Range, "!") && !isCells(values.Range)) {
+		// A named range (or a sheet title) has no cells to start the chunks from.
+		return nil
+	}
+
+	chunks := make([]ValueRange, 0, (len(values.Values)+rowsPerChunk-1)/rowsPerChunk)
+	for start := 0; start < len(values.Values); start += rowsPerChunk {
+		end := start + rowsPerChunk
+		if end > len(values.Values) {
+			end = len(values.Values)
+		}
+
+		cell := Cell{Row: int(gridRange.StartRowIndex) + start, Col: int(gridRange.StartColumnIndex)}.A1()
+		if sheetTitle != "" {
+			cell = QuoteSheetTitle(sheetTitle) + "!" + cell
+		}
+
+		chunks = append(chunks, ValueRange{
+			Range:          cell,
+			MajorDimension: values.MajorDimension,
+			Values:         values.Values[start:end],
+		})
+	}
+
+	return chunks
+}
+
+// updateChunks writes the "chunks" sequentially and returns the aggregate response.
+// If a chunk fails then the previous chunks are already written,
+// the response reports them and the error is returned.
+func (c *Client) updateChunks(ctx context.Context, spreadsheetID string, chunks []ValueRange) (response UpdateValuesResponse, err error) {
+	response.SpreadsheetID = spreadsheetID
+
+	var (
+		sheetTitle string
+		updated    GridRange
+		found      bool
+	)
+
+	for _, chunk := range chunks {
+		chunkResponse, err := c.updateValues(ctx, spreadsheetID, chunk)
+		if err != nil {
+			return response, err
+		}
+
+		response.UpdatedRows += chunkResponse.UpdatedRows
+		response.UpdatedCells += chunkResponse.UpdatedCells
+		if chunkResponse.UpdatedColumns > response.UpdatedColumns {
+			response.UpdatedColumns = chunkResponse.UpdatedColumns
+		}
+
+		// The updated range is the union of the chunks' ones.
+		title, r, err := ParseA1(chunkResponse.UpdatedRange)
+		if err != nil {
+			continue
+		}
+
+		if !found {
+			sheetTitle, updated, found = title, r, true
+			continue
+		}
+
+		if r.EndRowIndex > updated.EndRowIndex {
+			updated.EndRowIndex = r.EndRowIndex
+		}
+		if r.StartColumnIndex < updated.StartColumnIndex {
+			updated.StartColumnIndex = r.StartColumnIndex
+		}
+		if r.EndColumnIndex > updated.EndColumnIndex {
+			updated.EndColumnIndex = r.EndColumnIndex
+		}
+	}
+
+	if found {
+		response.UpdatedRange = updated.A1(sheetTitle)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,83 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientUpdateSpreadsheetChunks(t *testing.T) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var values ValueRange
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+			t.Fatal(err)
+		}
+
+		dataRange := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/id/values/")
+		ranges = append(ranges, dataRange)
+		if values.Range != dataRange {
+			t.Errorf("expected the body range %q to match the URL range %q", values.Range, dataRange)
+		}
+
+		_, start, _ := ParseA1(dataRange)
+		updated := Cell{Row: int(start.StartRowIndex), Col: int(start.StartColumnIndex)}.Block(len(values.Values), 2)
+		json.NewEncoder(w).Encode(UpdateValuesResponse{
+			SpreadsheetID:  "id",
+			UpdatedRange:   updated.A1("My Sheet"),
+			UpdatedRows:    len(values.Values),
+			UpdatedColumns: 2,
+			UpdatedCells:   2 * len(values.Values),
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(rewriteTransport(srv.URL))
+	c.MaxWriteRows = 2
+
+	values := make([][]interface{}, 5)
+	for i := range values {
+		values[i] = []interface{}{fmt.Sprint("name", i), i}
+	}
+
+	response, err := c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "'My Sheet'!B2:C", Values: values})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"'My Sheet'!B2", "'My Sheet'!B4", "'My Sheet'!B6"}; !reflect.DeepEqual(expected, ranges) {
+		t.Fatalf("expected chunk ranges %q but got %q", expected, ranges)
+	}
+
+	expected := UpdateValuesResponse{SpreadsheetID: "id", UpdatedRange: "'My Sheet'!B2:C6", UpdatedRows: 5, UpdatedColumns: 2, UpdatedCells: 10}
+	if !reflect.DeepEqual(expected, response) {
+		t.Fatalf("expected response %#v but got %#v", expected, response)
+	}
+
+	// Split by size.
+	ranges = nil
+	c.MaxWriteRows = -1
+	c.MaxWriteBytes = 40
+	if _, err = c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "A1:B", Values: values}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) < 2 || ranges[0] != "A1" {
+		t.Fatalf("expected the values to be split by size but got ranges %q", ranges)
+	}
+
+	// Named ranges are not split.
+	ranges = nil
+	c.MaxWriteBytes = -1
+	c.MaxWriteRows = 2
+	if _, err = c.UpdateSpreadsheet(context.Background(), "id", ValueRange{Range: "Totals", Values: values}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Totals"}; !reflect.DeepEqual(expected, ranges) {
+		t.Fatalf("expected a single request but got ranges %q", ranges)
+	}
+}